@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hecatoncheir/Configuration"
+	"github.com/hecatoncheir/Sproot/engine"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/settings"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+// Command is a subcommand of sproot, actions of command are selected by first argument
+type Command struct {
+	Name  string
+	Usage string
+	Run   func(cli *CLI, arguments []string) error
+}
+
+// Commands are all subcommands of sproot
+var Commands []Command
+
+func init() {
+	Commands = []Command{
+		{Name: "serve", Usage: "serve [-seed seed.yaml]: apply seed, connect to broker and handle events", Run: serve},
+		{Name: "config", Usage: "config: print effective settings of selected environment", Run: printSettings},
+		{Name: "migrate", Usage: "migrate: apply pending migrations of schema of database", Run: migrate},
+		{Name: "check-schema", Usage: "check-schema <directory>: check predicates of schema, structures and queries", Run: checkSchema},
+		{Name: "seed", Usage: "seed [-file seed.yaml]: apply seed file to database", Run: seed},
+		{Name: "companies", Usage: "companies list|create|deactivate: manage companies", Run: companies},
+		{Name: "categories", Usage: "categories list|create|deactivate: manage categories", Run: categories},
+		{Name: "cities", Usage: "cities list|create|deactivate: manage cities", Run: cities},
+		{Name: "instructions", Usage: "instructions list|create|activate|deactivate|add-page|add-city|add-category: manage instructions", Run: instructions},
+		{Name: "products", Usage: "products search -name <name>: search products by name", Run: products},
+		{Name: "prices", Usage: "prices history -product <id>: show prices of product", Run: prices},
+		{Name: "offers", Usage: "offers list|rebuild: show current offers ordered by value or update them by all prices", Run: offers},
+		{Name: "rates", Usage: "rates list|set -currency USD -value 92.5: manage rates of currencies to reference currency", Run: rates},
+		{Name: "retention", Usage: "retention [-days N] [-per day|change] [-archive file] [-dry-run]: downsample old prices", Run: retention},
+		{Name: "export", Usage: "export graph|companies|prices|instructions|cities|report: export data of database", Run: export},
+		{Name: "import", Usage: "import graph|companies|prices|instructions|cities: import exported data", Run: importData},
+		{Name: "drop-all", Usage: "drop-all [-yes]: remove all data and schema of database", Run: dropAll},
+	}
+}
+
+var (
+	// ErrUnknownCommand means that the command or action is not supported
+	ErrUnknownCommand = errors.New("unknown command")
+
+	// ErrRequiredFlag means that the command is called without required flag
+	ErrRequiredFlag = errors.New("required flag is not set")
+
+	// ErrNotConfirmed means that the dangerous command was not confirmed by user
+	ErrNotConfirmed = errors.New("command is not confirmed")
+)
+
+// CLI is a state of sproot command line, storage is connected on first use
+type CLI struct {
+	Configuration *configuration.Configuration
+	Settings      settings.Settings
+	Engine        *engine.Engine
+	Input         io.Reader
+	Output        io.Writer
+	LookupEnv     func(key string) (string, bool)
+}
+
+// New is a constructor for CLI with standard input and output and environment variables of process
+func New(config *configuration.Configuration) (*CLI, error) {
+	puffer, err := engine.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CLI{
+		Configuration: config,
+		Engine:        puffer,
+		Input:         os.Stdin,
+		Output:        os.Stdout,
+		LookupEnv:     os.LookupEnv}, nil
+}
+
+// Run is a method for execute subcommand with arguments, serve is executed if command is not set.
+// Flags of settings are placed before subcommand and override environment variables.
+func (cli *CLI) Run(arguments []string) error {
+	flags := flag.NewFlagSet("sproot", flag.ContinueOnError)
+	flags.SetOutput(cli.Output)
+	overrides := settings.Flags(flags)
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	arguments = flags.Args()
+	if len(arguments) == 0 {
+		arguments = []string{"serve"}
+	}
+
+	if arguments[0] == "help" {
+		cli.PrintUsage()
+		flags.PrintDefaults()
+		return nil
+	}
+
+	for _, command := range Commands {
+		if command.Name != arguments[0] {
+			continue
+		}
+
+		cli.Settings, err = settings.Load(cli.Configuration, cli.LookupEnv, overrides)
+		if err != nil {
+			return err
+		}
+
+		if command.Name != "config" {
+			err = cli.Settings.Validate()
+			if err != nil {
+				return err
+			}
+		}
+
+		cli.Engine.Settings = cli.Settings
+
+		level, err := logging.ParseLevel(cli.Settings.LogLevel)
+		if err == nil {
+			logging.Default.SetLevel(level)
+		}
+
+		defer cli.closeStorage()
+
+		return command.Run(cli, arguments[1:])
+	}
+
+	cli.PrintUsage()
+
+	return ErrUnknownCommand
+}
+
+// PrintUsage is a method for print all commands
+func (cli *CLI) PrintUsage() {
+	fmt.Fprintln(cli.Output, "Usage: sproot [settings flags] <command> [flags]")
+	for _, command := range Commands {
+		fmt.Fprintf(cli.Output, "  %v\n", command.Usage)
+	}
+}
+
+// Storage is a method for get connected storage of engine
+func (cli *CLI) Storage() (*storage.Storage, error) {
+	if cli.Engine.Storage != nil && cli.Engine.Storage.Client != nil {
+		return cli.Engine.Storage, nil
+	}
+
+	database := cli.Settings.Database
+	err := cli.Engine.SetUpStorage(database.Host, database.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.Engine.Storage, nil
+}
+
+// closeStorage close connections to database if storage was used by command
+func (cli *CLI) closeStorage() {
+	if cli.Engine.Storage == nil {
+		return
+	}
+
+	err := cli.Engine.Storage.Close()
+	if err != nil {
+		logging.Default.Warning(err.Error())
+	}
+}
+
+// confirm ask user to type yes
+func (cli *CLI) confirm(question string) bool {
+	fmt.Fprintf(cli.Output, "%v Type yes to continue: ", question)
+
+	answer, _ := bufio.NewReader(cli.Input).ReadString('\n')
+
+	return strings.TrimSpace(answer) == "yes"
+}
+
+// table is a writer of aligned columns, flush must be called after last row
+func (cli *CLI) table(header ...string) *tabwriter.Writer {
+	table := tabwriter.NewWriter(cli.Output, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+
+	return table
+}
+
+func row(table io.Writer, cells ...interface{}) {
+	texts := make([]string, len(cells))
+	for index, cell := range cells {
+		texts[index] = fmt.Sprint(cell)
+	}
+
+	fmt.Fprintln(table, strings.Join(texts, "\t"))
+}
+
+// action split arguments to action and its flags
+func action(arguments []string) (string, []string) {
+	if len(arguments) == 0 {
+		return "", nil
+	}
+
+	return arguments[0], arguments[1:]
+}
+
+// known check that action is one of actions of command
+func known(name string, actions ...string) error {
+	for _, action := range actions {
+		if action == name {
+			return nil
+		}
+	}
+
+	return ErrUnknownCommand
+}
+
+func newFlags(name string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	language := flags.String("language", "ru", "language of names")
+
+	return flags, language
+}
+
+func required(values ...string) error {
+	for _, value := range values {
+		if value == "" {
+			return ErrRequiredFlag
+		}
+	}
+
+	return nil
+}
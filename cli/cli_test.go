@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/hecatoncheir/Configuration"
+	"github.com/hecatoncheir/Sproot/engine/settings"
+)
+
+func newTestCLI(test *testing.T, input string) (*CLI, *bytes.Buffer) {
+	output := &bytes.Buffer{}
+
+	variables := map[string]string{
+		"SPROOT_API_VERSION":        "v1",
+		"SPROOT_DATABASE_HOST":      "localhost",
+		"SPROOT_DATABASE_PORT":      "9080",
+		"SPROOT_EVENT_BUS_HOST":     "localhost",
+		"SPROOT_EVENT_BUS_PORT":     "4150",
+		"SPROOT_TOPIC":              "Sproot",
+		"SPROOT_HECATONCHEIR_TOPIC": "Hecatoncheir",
+		"SPROOT_INITIAL_TOPIC":      "Initial",
+		"SPROOT_LOGUNA_TOPIC":       "Loguna"}
+
+	cli, err := New(configuration.New())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	cli.Input = strings.NewReader(input)
+	cli.Output = output
+	cli.LookupEnv = func(key string) (string, bool) {
+		value, ok := variables[key]
+		return value, ok
+	}
+
+	return cli, output
+}
+
+func TestUnknownCommandCanNotBeRun(test *testing.T) {
+	cli, output := newTestCLI(test, "")
+
+	err := cli.Run([]string{"unknown"})
+	if err != ErrUnknownCommand {
+		test.Error(err)
+	}
+
+	if !strings.Contains(output.String(), "Usage: sproot") {
+		test.Error("Usage must be printed for unknown command")
+	}
+}
+
+func TestUnknownActionCanNotBeRun(test *testing.T) {
+	cli, _ := newTestCLI(test, "")
+
+	for _, command := range []string{"companies", "categories", "cities", "instructions", "products", "prices", "export", "import"} {
+		err := cli.Run([]string{command, "unknown"})
+		if err != ErrUnknownCommand {
+			test.Errorf("Command %v: %v", command, err)
+		}
+	}
+}
+
+func TestUsageListsAllCommands(test *testing.T) {
+	cli, output := newTestCLI(test, "")
+
+	err := cli.Run([]string{"help"})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	for _, command := range Commands {
+		if !strings.Contains(output.String(), command.Usage) {
+			test.Errorf("Usage of %v is not printed", command.Name)
+		}
+	}
+}
+
+func TestDropAllMustBeConfirmed(test *testing.T) {
+	cli, output := newTestCLI(test, "no\n")
+
+	err := cli.Run([]string{"drop-all"})
+	if err != ErrNotConfirmed {
+		test.Error(err)
+	}
+
+	if !strings.Contains(output.String(), "Type yes to continue") {
+		test.Error("Confirmation must be asked")
+	}
+
+	if cli.Engine.Storage != nil {
+		test.Error("Storage must not be connected without confirmation")
+	}
+}
+
+func TestSettingsCanBeOverriddenByFlags(test *testing.T) {
+	cli, output := newTestCLI(test, "")
+
+	err := cli.Run([]string{"-environment", "development", "-database-port", "9081", "config"})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if cli.Settings.Environment != "development" || cli.Settings.Database.Port != 9081 {
+		test.Error(cli.Settings)
+	}
+
+	if cli.Engine.Settings.Database.Port != 9081 {
+		test.Error("Settings of engine must be the same as settings of command line")
+	}
+
+	if !strings.Contains(output.String(), "database-port: 9081") {
+		test.Error(output.String())
+	}
+}
+
+func TestCommandIsNotRunWithInvalidSettings(test *testing.T) {
+	cli, _ := newTestCLI(test, "yes\n")
+
+	err := cli.Run([]string{"-event-bus-port", "70000", "drop-all"})
+	if _, ok := err.(*settings.InvalidSettingsError); !ok {
+		test.Error(err)
+	}
+
+	if cli.Engine.Storage != nil {
+		test.Error("Storage must not be connected with invalid settings")
+	}
+}
+
+func TestServeFailsWhenAddressOfHTTPServerIsInUse(test *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	cli, _ := newTestCLI(test, "")
+
+	err = cli.Run([]string{"serve", "-http-address", listener.Addr().String()})
+	if _, ok := err.(*net.OpError); !ok {
+		test.Error(err)
+	}
+
+	if cli.Engine.Storage != nil {
+		test.Error("Storage must not be connected when http server can not be started")
+	}
+}
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+func products(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+	if name != "search" {
+		return ErrUnknownCommand
+	}
+
+	flags, language := newFlags("products search")
+	productName := flags.String("name", "", "part of name of product")
+	page := flags.Int("page", 1, "number of page")
+	perPage := flags.Int("per-page", 20, "products on page")
+	currency := flags.String("currency", "", "currency of prices like USD, prices are not converted by default")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = required(*productName)
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	found, err := store.Products.ReadProductsByNameWithPagination(context.Background(), *productName, *language, *page, *perPage,
+		storage.Projection{Detail: storage.Summary, PricesLimit: 1, Currency: *currency})
+	if err != nil {
+		return err
+	}
+
+	if len(found.Products) == 0 {
+		fmt.Fprintln(cli.Output, "Products not found")
+		return nil
+	}
+
+	table := cli.table("ID", "NAME", "IRI", "LAST PRICE")
+	for _, product := range found.Products {
+		lastPrice := ""
+		if len(product.Prices) > 0 {
+			lastPrice = product.Prices[0].MoneyOf(store.PricesCurrency()).String()
+		}
+
+		row(table, product.ID, product.Name, product.IRI, lastPrice)
+	}
+
+	err = table.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cli.Output, "Page %v, %v products found\n", found.CurrentPage, found.TotalProductsFound)
+
+	return nil
+}
+
+func prices(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+	if name != "history" {
+		return ErrUnknownCommand
+	}
+
+	flags, language := newFlags("prices history")
+	productID := flags.String("product", "", "uid of product")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = required(*productID)
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	product, err := store.Products.ReadProductByID(context.Background(), *productID, *language)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cli.Output, "%v %v\n", product.Name, product.IRI)
+
+	table := cli.table("DATETIME", "LAST SEEN", "VALUE", "CITY", "COMPANY")
+	for _, price := range product.Prices {
+		cityName, companyName := "", ""
+		if len(price.Cities) > 0 {
+			cityName = price.Cities[0].Name
+		}
+
+		if len(price.Companies) > 0 {
+			companyName = price.Companies[0].Name
+		}
+
+		lastSeenAt := ""
+		if !price.LastSeenAt.IsZero() {
+			lastSeenAt = price.LastSeenAt.Format(time.RFC3339)
+		}
+
+		row(table, price.DateTime.Format(time.RFC3339), lastSeenAt, price.MoneyOf(store.PricesCurrency()), cityName, companyName)
+	}
+
+	return table.Flush()
+}
+
+func offers(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags, language := newFlags("offers " + name)
+	productID := flags.String("product", "", "uid of product")
+	companyID := flags.String("company", "", "uid of company")
+	cityID := flags.String("city", "", "uid of city")
+	descending := flags.Bool("desc", false, "order from the most expensive offer")
+	first := flags.Int("first", 20, "count of offers, 0 for all")
+	currency := flags.String("currency", "", "currency of values like USD, values are not converted by default")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = known(name, "list", "rebuild")
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	if name == "rebuild" {
+		count, err := store.Offers.RebuildOffers(context.Background())
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cli.Output, "Offers are updated by %v prices\n", count)
+
+		return nil
+	}
+
+	filter := storage.OfferFilter{ProductID: *productID, CompanyID: *companyID, CityID: *cityID,
+		Descending: *descending, First: *first, Currency: *currency}
+
+	found, err := store.Offers.ReadOffers(context.Background(), filter, *language)
+	if err != nil {
+		return err
+	}
+
+	table := cli.table("PRODUCT", "COMPANY", "CITY", "VALUE", "PREVIOUS", "CURRENCY", "DATETIME")
+	for _, offer := range found {
+		productName, companyName, cityName := "", "", ""
+		if len(offer.Products) > 0 {
+			productName = offer.Products[0].Name
+		}
+
+		if len(offer.Companies) > 0 {
+			companyName = offer.Companies[0].Name
+		}
+
+		if len(offer.Cities) > 0 {
+			cityName = offer.Cities[0].Name
+		}
+
+		offerCurrency := offer.Currency
+		if offerCurrency == "" {
+			offerCurrency = store.PricesCurrency()
+		}
+
+		row(table, productName, companyName, cityName, offer.Value, offer.PreviousValue, offerCurrency,
+			offer.DateTime.Format(time.RFC3339))
+	}
+
+	return table.Flush()
+}
+
+func retention(cli *CLI, arguments []string) error {
+	request := cli.Engine.RetentionRequestOfSettings()
+
+	flags := flag.NewFlagSet("retention", flag.ContinueOnError)
+	flags.IntVar(&request.KeepDays, "days", request.KeepDays, "count of days of prices with full resolution")
+	flags.StringVar(&request.Per, "per", request.Per, "resolution of older prices: day or change")
+	flags.StringVar(&request.ArchivePath, "archive", request.ArchivePath, "file for JSON lines of removed prices")
+	flags.BoolVar(&request.DryRun, "dry-run", false, "count prices without removing them")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	report, err := cli.Engine.ApplyRetention(context.Background(), request)
+	fmt.Fprintln(cli.Output, report)
+
+	return err
+}
+
+func rates(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags := flag.NewFlagSet("rates "+name, flag.ContinueOnError)
+	currency := flags.String("currency", "", "currency like USD")
+	value := flags.String("value", "", "price of one unit of currency in reference currency like 92.5")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = known(name, "list", "set")
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	if name == "set" {
+		err = required(*currency, *value)
+		if err != nil {
+			return err
+		}
+
+		rate, err := store.Rates.SetRate(context.Background(), *currency, *value, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cli.Output, "Rate of %v is %v %v\n", rate.Currency, rate.Value, cli.Settings.ReferenceCurrency)
+
+		return nil
+	}
+
+	found, err := store.Rates.ReadRates(context.Background())
+	if err != nil {
+		return err
+	}
+
+	table := cli.table("CURRENCY", "RATE", "REFERENCE", "DATETIME")
+	for _, rate := range found {
+		row(table, rate.Currency, rate.Value, cli.Settings.ReferenceCurrency, rate.DateTime.Format(time.RFC3339))
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+func companies(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags, language := newFlags("companies " + name)
+	companyName := flags.String("name", "", "name of company")
+	iri := flags.String("iri", "", "IRI of site of company")
+	id := flags.String("id", "", "uid of company")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = known(name, "list", "create", "deactivate")
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		allCompanies, err := store.Companies.ReadAllCompanies(context.Background(), *language)
+		if err != nil && !errors.Is(err, storage.ErrCompaniesByNameNotFound) {
+			return err
+		}
+
+		table := cli.table("ID", "NAME", "IRI", "CATEGORIES")
+		for _, company := range allCompanies {
+			row(table, company.ID, company.Name, company.IRI, len(company.Categories))
+		}
+
+		return table.Flush()
+	case "create":
+		err = required(*companyName)
+		if err != nil {
+			return err
+		}
+
+		company, err := store.Companies.CreateCompany(context.Background(), storage.Company{Name: *companyName, IRI: *iri}, *language)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cli.Output, company.ID)
+
+		return nil
+	case "deactivate":
+		err = required(*id)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.Companies.DeactivateCompany(context.Background(), storage.Company{ID: *id})
+
+		return err
+	}
+
+	return ErrUnknownCommand
+}
+
+func categories(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags, language := newFlags("categories " + name)
+	categoryName := flags.String("name", "", "name of category")
+	companyID := flags.String("company", "", "uid of company of created category")
+	id := flags.String("id", "", "uid of category")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = known(name, "list", "create", "deactivate")
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		allCategories, err := store.Categories.ReadAllCategories(context.Background(), *language)
+		if err != nil && !errors.Is(err, storage.ErrCategoriesByNameNotFound) {
+			return err
+		}
+
+		table := cli.table("ID", "NAME", "COMPANIES")
+		for _, category := range allCategories {
+			companyNames := make([]string, len(category.Companies))
+			for index, company := range category.Companies {
+				companyNames[index] = company.Name
+			}
+
+			row(table, category.ID, category.Name, companyNames)
+		}
+
+		return table.Flush()
+	case "create":
+		err = required(*categoryName)
+		if err != nil {
+			return err
+		}
+
+		category, err := store.Categories.CreateCategory(context.Background(), storage.Category{Name: *categoryName}, *language)
+		if err != nil && !errors.Is(err, storage.ErrCategoryAlreadyExist) {
+			return err
+		}
+
+		if *companyID != "" {
+			err = store.Categories.AddCompanyToCategory(context.Background(), category.ID, *companyID)
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintln(cli.Output, category.ID)
+
+		return nil
+	case "deactivate":
+		err = required(*id)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.Categories.DeactivateCategory(context.Background(), storage.Category{ID: *id})
+
+		return err
+	}
+
+	return ErrUnknownCommand
+}
+
+func cities(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags, language := newFlags("cities " + name)
+	cityName := flags.String("name", "", "name of city")
+	id := flags.String("id", "", "uid of city")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = known(name, "list", "create", "deactivate")
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		allCities, err := store.Cities.ReadAllCities(context.Background(), *language)
+		if err != nil && !errors.Is(err, storage.ErrCitiesByNameNotFound) {
+			return err
+		}
+
+		table := cli.table("ID", "NAME")
+		for _, city := range allCities {
+			row(table, city.ID, city.Name)
+		}
+
+		return table.Flush()
+	case "create":
+		err = required(*cityName)
+		if err != nil {
+			return err
+		}
+
+		city, err := store.Cities.CreateCity(context.Background(), storage.City{Name: *cityName}, *language)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cli.Output, city.ID)
+
+		return nil
+	case "deactivate":
+		err = required(*id)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.Cities.DeactivateCity(context.Background(), storage.City{ID: *id})
+
+		return err
+	}
+
+	return ErrUnknownCommand
+}
+
+func instructions(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags, language := newFlags("instructions " + name)
+	companyID := flags.String("company", "", "uid of company")
+	id := flags.String("id", "", "uid of instruction")
+	instructionLanguage := flags.String("instruction-language", "ru", "language of created instruction")
+	cityID := flags.String("city", "", "uid of city")
+	categoryID := flags.String("category", "", "uid of category")
+	page := storage.PageInstruction{IsActive: true}
+	flags.StringVar(&page.Path, "path", "", "path of page of category on site of company")
+	flags.StringVar(&page.PageInPaginationSelector, "page-in-pagination-selector", "", "selector of pages")
+	flags.StringVar(&page.PreviewImageOfItemSelector, "preview-image-selector", "", "selector of image of item")
+	flags.StringVar(&page.PageParamPath, "page-param-path", "", "query parameter of page")
+	flags.StringVar(&page.CityParamPath, "city-param-path", "", "query parameter of city")
+	flags.StringVar(&page.ItemSelector, "item-selector", "", "selector of item")
+	flags.StringVar(&page.NameOfItemSelector, "name-selector", "", "selector of name of item")
+	flags.StringVar(&page.LinkOfItemSelector, "link-selector", "", "selector of link of item")
+	flags.StringVar(&page.CityInCookieKey, "city-in-cookie-key", "", "cookie with city")
+	flags.StringVar(&page.CityIDForCookie, "city-id-for-cookie", "", "value of cookie with city")
+	flags.StringVar(&page.PriceOfItemSelector, "price-selector", "", "selector of price of item")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = known(name, "list", "create", "activate", "deactivate", "add-page", "add-city", "add-category")
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		err = required(*companyID)
+		if err != nil {
+			return err
+		}
+
+		instructionsOfCompany, err := store.Instructions.ReadInstructionsOfCompany(context.Background(), *companyID, *language)
+		if err != nil && !errors.Is(err, storage.ErrInstructionsForCompanyDoesNotExist) {
+			return err
+		}
+
+		table := cli.table("ID", "LANGUAGE", "ACTIVE", "CITIES", "CATEGORIES", "PAGE", "PAGE ACTIVE")
+		for _, instruction := range instructionsOfCompany {
+			row(table, instruction.ID, instruction.Language, instruction.IsActive,
+				len(instruction.Cities), len(instruction.Categories), "", "")
+
+			for _, pageInstruction := range instruction.PagesInstruction {
+				row(table, pageInstruction.ID, "", "", "", "", pageInstruction.Path, pageInstruction.IsActive)
+			}
+		}
+
+		return table.Flush()
+	case "create":
+		err = required(*companyID)
+		if err != nil {
+			return err
+		}
+
+		instruction, err := store.Instructions.CreateInstructionForCompany(context.Background(), *companyID, *instructionLanguage)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cli.Output, instruction.ID)
+
+		return nil
+	case "activate":
+		err = required(*id)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.Instructions.ActivateInstruction(context.Background(), storage.Instruction{ID: *id})
+
+		return err
+	case "deactivate":
+		err = required(*id)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.Instructions.DeactivateInstruction(context.Background(), storage.Instruction{ID: *id})
+
+		return err
+	case "add-page":
+		err = required(*id, page.Path)
+		if err != nil {
+			return err
+		}
+
+		createdPage, err := store.Instructions.CreatePageInstruction(context.Background(), page)
+		if err != nil {
+			return err
+		}
+
+		err = store.Instructions.AddPageInstructionToInstruction(context.Background(), *id, createdPage.ID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cli.Output, createdPage.ID)
+
+		return nil
+	case "add-city":
+		err = required(*id, *cityID)
+		if err != nil {
+			return err
+		}
+
+		return store.Instructions.AddCityToInstruction(context.Background(), *id, *cityID)
+	case "add-category":
+		err = required(*id, *categoryID)
+		if err != nil {
+			return err
+		}
+
+		return store.Instructions.AddCategoryToInstruction(context.Background(), *id, *categoryID)
+	}
+
+	return ErrUnknownCommand
+}
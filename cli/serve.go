@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/hecatoncheir/Sproot/engine/metrics"
+	"github.com/hecatoncheir/Sproot/engine/modeler"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+	"github.com/hecatoncheir/Sproot/engine/tracing"
+)
+
+func serve(cli *CLI, arguments []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	seedFilePath := flags.String("seed", "seed.yaml", "YAML or JSON file with data for storage")
+	httpAddress := flags.String("http-address", ":8090",
+		"address of http server with /health/live, /health/ready and /metrics endpoints, empty for disable")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Settings:\n%v", cli.Settings)
+
+	exporter, err := tracing.NewExporter(cli.Settings.TraceExporter, cli.Settings.TraceEndpoint,
+		cli.Settings.ServiceName, cli.Output)
+	if err != nil {
+		return err
+	}
+
+	tracing.Default.SetExporter(exporter)
+	defer tracing.Default.Shutdown()
+
+	if *httpAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/health/", cli.Engine.HealthHandler())
+		mux.Handle("/metrics", metrics.Handler())
+
+		listener, err := net.Listen("tcp", *httpAddress)
+		if err != nil {
+			return err
+		}
+
+		defer listener.Close()
+
+		go func() {
+			err := http.Serve(listener, mux)
+			if err != nil {
+				log.Println(err)
+			}
+		}()
+	}
+
+	_, err = cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	err = cli.Engine.SetUpModel(context.Background(), *seedFilePath)
+	if err != nil {
+		return err
+	}
+
+	eventBus := cli.Settings.EventBus
+	err = cli.Engine.SetUpBroker(eventBus.Host, eventBus.Port)
+	if err != nil {
+		return err
+	}
+
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	cli.Engine.Schedule(jobs, cli.Engine.RetentionJob())
+
+	cli.Engine.SubscribeOnEvents(cli.Settings.SprootTopic)
+
+	return nil
+}
+
+// printSettings print effective settings and problems of them
+func printSettings(cli *CLI, arguments []string) error {
+	fmt.Fprintln(cli.Output, cli.Settings)
+
+	return cli.Settings.Validate()
+}
+
+func migrate(cli *CLI, arguments []string) error {
+	database := cli.Settings.Database
+
+	migrations, err := cli.Engine.MigrateStorage(database.Host, database.Port)
+	for _, migration := range migrations {
+		log.Printf("Migration %v applied: %v", migration.Version, migration.Description)
+	}
+
+	return err
+}
+
+func checkSchema(cli *CLI, arguments []string) error {
+	sourceDirectory := "engine/storage"
+	if len(arguments) > 0 {
+		sourceDirectory = arguments[0]
+	}
+
+	issues, err := storage.CheckSchemaConsistency(sourceDirectory)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintln(cli.Output, issue)
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("schema has %v issues", len(issues))
+	}
+
+	return nil
+}
+
+func seed(cli *CLI, arguments []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	seedFilePath := flags.String("file", "seed.yaml", "YAML or JSON file with data for storage")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	report, err := modeler.New(store).SetUpFromFile(context.Background(), *seedFilePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cli.Output, report)
+
+	return nil
+}
+
+func dropAll(cli *CLI, arguments []string) error {
+	flags := flag.NewFlagSet("drop-all", flag.ContinueOnError)
+	confirmed := flags.Bool("yes", false, "do not ask for confirmation")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	database := cli.Settings.Database
+	question := fmt.Sprintf("All data and schema of database %v:%v will be removed.", database.Host, database.Port)
+
+	if !*confirmed && !cli.confirm(question) {
+		return ErrNotConfirmed
+	}
+
+	store, err := cli.Storage()
+	if err != nil && !errors.Is(err, storage.ErrSchemaIsOutdated) && !errors.Is(err, storage.ErrSchemaIsNewer) {
+		return err
+	}
+
+	if store == nil {
+		store = cli.Engine.Storage
+	}
+
+	err = store.DeleteAll(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cli.Output, "Database is empty, apply migrations before use: sproot migrate")
+
+	return nil
+}
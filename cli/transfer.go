@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/sheet"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+func graphOptions() storage.GraphOptions {
+	return storage.GraphOptions{
+		Progress: func(progress storage.GraphProgress) {
+			log.Printf("%v of %v: %v records, %v nodes, %v edges",
+				progress.Section, progress.Kind, progress.Records, progress.Nodes, progress.Edges)
+		}}
+}
+
+// outputOf open file for write or return output of CLI if path is empty
+func (cli *CLI) outputOf(path string) (io.Writer, func() error, error) {
+	if path == "" {
+		return cli.Output, func() error { return nil }, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, file.Close, nil
+}
+
+func export(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags, language := newFlags("export " + name)
+	output := flags.String("output", "", "file for exported data, stdout if empty")
+	resume := flags.Bool("resume", false, "continue interrupted export of graph")
+	format := flags.String("format", sheet.FormatCSV, "format of report: csv or xlsx")
+	from := flags.String("from", "", "first date of prices of report, YYYY-MM-DD")
+	to := flags.String("to", "", "last date of prices of report, YYYY-MM-DD")
+	company := flags.String("company", "", "name of company of prices of report")
+	category := flags.String("category", "", "name of category of prices of report")
+	city := flags.String("city", "", "name of city of prices of report")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	var exportJSON func(*storage.Storage) ([]byte, error)
+
+	switch name {
+	case "companies":
+		exportJSON = func(store *storage.Storage) ([]byte, error) {
+			return store.Companies.ExportJSON(context.Background(), *language)
+		}
+	case "prices":
+		exportJSON = func(store *storage.Storage) ([]byte, error) {
+			return store.Prices.ExportJSON(context.Background(), *language)
+		}
+	case "instructions":
+		exportJSON = func(store *storage.Storage) ([]byte, error) {
+			return store.Instructions.ExportJSON(context.Background(), *language)
+		}
+	case "cities":
+		exportJSON = func(store *storage.Storage) ([]byte, error) {
+			return store.Cities.ExportJSON(context.Background(), *language)
+		}
+	case "graph", "report":
+	default:
+		return ErrUnknownCommand
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	if name == "graph" {
+		err = required(*output)
+		if err != nil {
+			return err
+		}
+
+		progress, err := store.ExportGraphToFile(context.Background(), *output, *resume, graphOptions())
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Graph exported to %v: %v records", *output, progress.Records)
+
+		return nil
+	}
+
+	writer, closeOutput, err := cli.outputOf(*output)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	if name == "report" {
+		filter := storage.PriceFilter{CompanyName: *company, CategoryName: *category, CityName: *city}
+
+		if *from != "" {
+			filter.From, err = time.Parse("2006-01-02", *from)
+			if err != nil {
+				return err
+			}
+		}
+
+		if *to != "" {
+			date, err := time.Parse("2006-01-02", *to)
+			if err != nil {
+				return err
+			}
+
+			filter.To = date.Add(24*time.Hour - time.Nanosecond)
+		}
+
+		buffer := bufio.NewWriter(writer)
+		rows, err := cli.Engine.WritePrices(context.Background(), filter, *language, *format, buffer)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Prices exported: %v rows", rows)
+
+		return buffer.Flush()
+	}
+
+	exported, err := exportJSON(store)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(exported)
+
+	return err
+}
+
+func importData(cli *CLI, arguments []string) error {
+	name, arguments := action(arguments)
+
+	flags := flag.NewFlagSet("import "+name, flag.ContinueOnError)
+	input := flags.String("input", "", "file with exported data")
+	resume := flags.Bool("resume", false, "continue interrupted import of graph")
+	err := flags.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	err = known(name, "graph", "companies", "prices", "instructions", "cities")
+	if err != nil {
+		return err
+	}
+
+	err = required(*input)
+	if err != nil {
+		return err
+	}
+
+	store, err := cli.Storage()
+	if err != nil {
+		return err
+	}
+
+	if name == "graph" {
+		return importGraph(store, *input, *resume)
+	}
+
+	exported, err := ioutil.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+
+	var report storage.ImportReport
+
+	switch name {
+	case "companies":
+		report, err = store.Companies.ImportJSON(context.Background(), exported)
+	case "prices":
+		report, err = store.Prices.ImportJSON(context.Background(), exported)
+	case "instructions":
+		err = store.Instructions.ImportJSON(context.Background(), exported)
+	case "cities":
+		err = store.Cities.ImportJSON(context.Background(), exported)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(report.Nodes) > 0 {
+		fmt.Fprintf(cli.Output, "%v nodes created, %v nodes matched\n", report.Created(), report.Matched())
+	}
+
+	return nil
+}
+
+func importGraph(store *storage.Storage, path string, resume bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = storage.VerifyGraph(file)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	options := graphOptions()
+	if resume {
+		options.CheckpointPath = path + ".checkpoint"
+	}
+
+	progress, err := store.ImportGraph(context.Background(), file, options)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Graph imported from %v: %v nodes, %v edges, %v skipped",
+		path, progress.Nodes, progress.Edges, progress.Skipped)
+
+	return nil
+}
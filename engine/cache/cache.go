@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/metrics"
+)
+
+var (
+	// DefaultCapacity is a count of entries of cache after which least recently used entries are evicted
+	DefaultCapacity = 1000
+
+	// DefaultTTL is a time after which entry of cache is expired
+	DefaultTTL = time.Minute
+
+	// InvalidatedTagsLimit is a count of tags which generations of invalidation are kept,
+	// when it is exceeded generations are forgotten and values read before are not saved
+	InvalidatedTagsLimit = 10000
+)
+
+var (
+	requests = metrics.NewCounterVec("sproot_cache_requests_total",
+		"Lookups of entries of cache by name of cache and result: hit or miss.", "cache", "result")
+
+	evictions = metrics.NewCounterVec("sproot_cache_evictions_total",
+		"Entries removed from cache by name of cache and reason: capacity, expired or invalidated.", "cache", "reason")
+)
+
+// Cache is a LRU cache of values with time to live and tags for invalidation.
+// Nil cache is a disabled cache: nothing is found in it and nothing is saved.
+// Values are shared between callers and must not be changed.
+type Cache struct {
+	name     string
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mutex       sync.Mutex
+	entries     map[string]*list.Element
+	order       *list.List
+	tagged      map[string]map[string]struct{}
+	generation  uint64
+	invalidated map[string]uint64
+	forgotten   uint64
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	tags      []string
+	expiresAt time.Time
+}
+
+// New is a constructor for cache with name for metrics, capacity of entries and time to live of entry,
+// cache is disabled and nil is returned when capacity or ttl is not positive
+func New(name string, capacity int, ttl time.Duration) *Cache {
+	if capacity <= 0 || ttl <= 0 {
+		return nil
+	}
+
+	return &Cache{
+		name:        name,
+		capacity:    capacity,
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     make(map[string]*list.Element),
+		order:       list.New(),
+		tagged:      make(map[string]map[string]struct{}),
+		invalidated: make(map[string]uint64)}
+}
+
+// Get is a method for get value of key which is not expired
+func (cache *Cache) Get(key string) (interface{}, bool) {
+	if cache == nil {
+		return nil, false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		requests.Inc(cache.name, "miss")
+		return nil, false
+	}
+
+	found := element.Value.(*entry)
+	if !cache.now().Before(found.expiresAt) {
+		cache.remove(element, "expired")
+		requests.Inc(cache.name, "miss")
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+	requests.Inc(cache.name, "hit")
+
+	return found.value, true
+}
+
+// Generation is a method for get count of invalidations of cache,
+// it is taken before read of value from database and passed to Set with the value.
+// Each invalidated tag keeps generation of its last invalidation.
+func (cache *Cache) Generation() uint64 {
+	if cache == nil {
+		return 0
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.generation
+}
+
+// Set is a method for save value of key with tags. Value is not saved when any of its tags was invalidated
+// or cache was purged after generation was taken, because the value could be read before changes
+// which invalidated it. Invalidations of other tags don't prevent saving.
+func (cache *Cache) Set(key string, value interface{}, generation uint64, tags ...string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if generation < cache.forgotten {
+		return
+	}
+
+	for _, tag := range tags {
+		if cache.invalidated[tag] > generation {
+			return
+		}
+	}
+
+	if element, ok := cache.entries[key]; ok {
+		cache.remove(element, "")
+	}
+
+	saved := &entry{key: key, value: value, tags: tags, expiresAt: cache.now().Add(cache.ttl)}
+	cache.entries[key] = cache.order.PushFront(saved)
+
+	for _, tag := range tags {
+		keys, ok := cache.tagged[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			cache.tagged[tag] = keys
+		}
+
+		keys[key] = struct{}{}
+	}
+
+	for cache.order.Len() > cache.capacity {
+		cache.remove(cache.order.Back(), "capacity")
+	}
+}
+
+// Invalidate is a method for remove all entries with any of tags
+func (cache *Cache) Invalidate(tags ...string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.generation++
+
+	if len(cache.invalidated)+len(tags) > InvalidatedTagsLimit {
+		cache.forget()
+	}
+
+	for _, tag := range tags {
+		cache.invalidated[tag] = cache.generation
+
+		for key := range cache.tagged[tag] {
+			if element, ok := cache.entries[key]; ok {
+				cache.remove(element, "invalidated")
+			}
+		}
+	}
+}
+
+// Purge is a method for remove all entries of cache
+func (cache *Cache) Purge() {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.generation++
+	cache.forget()
+	cache.entries = make(map[string]*list.Element)
+	cache.order.Init()
+	cache.tagged = make(map[string]map[string]struct{})
+}
+
+// Len is a method for get count of entries of cache, expired entries are counted until they are evicted
+func (cache *Cache) Len() int {
+	if cache == nil {
+		return 0
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.order.Len()
+}
+
+// forget remove generations of invalidated tags, values read before current generation are not saved
+func (cache *Cache) forget() {
+	cache.forgotten = cache.generation
+	cache.invalidated = make(map[string]uint64)
+}
+
+// remove delete entry from order, keys and tags, eviction is counted when reason is set
+func (cache *Cache) remove(element *list.Element, reason string) {
+	removed := cache.order.Remove(element).(*entry)
+	delete(cache.entries, removed.key)
+
+	for _, tag := range removed.tags {
+		keys := cache.tagged[tag]
+		delete(keys, removed.key)
+		if len(keys) == 0 {
+			delete(cache.tagged, tag)
+		}
+	}
+
+	if reason != "" {
+		evictions.Inc(cache.name, reason)
+	}
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeastRecentlyUsedEntriesAreEvicted(test *testing.T) {
+	cache := New("test-capacity", 2, time.Minute)
+
+	cache.Set("first", 1, cache.Generation())
+	cache.Set("second", 2, cache.Generation())
+	cache.Get("first")
+	cache.Set("third", 3, cache.Generation())
+
+	if _, ok := cache.Get("second"); ok {
+		test.Error("Least recently used entry must be evicted")
+	}
+
+	value, ok := cache.Get("first")
+	if !ok || value != 1 || cache.Len() != 2 {
+		test.Error(value, cache.Len())
+	}
+
+	if requests.Value("test-capacity", "hit") != 2 || requests.Value("test-capacity", "miss") != 1 {
+		test.Error(requests.Value("test-capacity", "hit"), requests.Value("test-capacity", "miss"))
+	}
+}
+
+func TestEntriesAreExpired(test *testing.T) {
+	now := time.Now()
+	cache := New("test-ttl", 10, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("key", "value", cache.Generation())
+
+	now = now.Add(59 * time.Second)
+	if _, ok := cache.Get("key"); !ok {
+		test.Error("Entry must be found before its ttl")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := cache.Get("key"); ok || cache.Len() != 0 {
+		test.Error("Entry must be expired after its ttl")
+	}
+}
+
+func TestEntriesAreInvalidatedByTags(test *testing.T) {
+	cache := New("test-tags", 10, time.Minute)
+
+	cache.Set("product/0x1", "iPhone", cache.Generation(), "0x1", "0x2")
+	cache.Set("products/iphone", "page", cache.Generation(), "products", "0x1")
+	cache.Set("company/0x3", "company", cache.Generation(), "0x3")
+
+	generation := cache.Generation()
+	cache.Invalidate("0x1")
+
+	if cache.Len() != 1 {
+		test.Error(cache.Len())
+	}
+
+	if _, ok := cache.Get("company/0x3"); !ok {
+		test.Error("Entry without tag must be kept")
+	}
+
+	cache.Set("product/0x1", "stale", generation, "0x1")
+	if _, ok := cache.Get("product/0x1"); ok {
+		test.Error("Value read before invalidation must not be saved")
+	}
+
+	generation = cache.Generation()
+	cache.Purge()
+	if cache.Len() != 0 {
+		test.Error(cache.Len())
+	}
+
+	cache.Set("company/0x3", "stale", generation, "0x3")
+	if _, ok := cache.Get("company/0x3"); ok {
+		test.Error("Value read before purge must not be saved")
+	}
+}
+
+func TestInvalidationOfOtherTagsDoesNotPreventSaving(test *testing.T) {
+	cache := New("test-other-tags", 10, time.Minute)
+
+	generation := cache.Generation()
+	cache.Invalidate("0x2", "products")
+
+	cache.Set("company/0x3", "company", generation, "0x3", "companies")
+	if _, ok := cache.Get("company/0x3"); !ok {
+		test.Error("Value without invalidated tags must be saved")
+	}
+
+	cache.Set("product/0x2", "stale", generation, "0x2")
+	if _, ok := cache.Get("product/0x2"); ok {
+		test.Error("Value with invalidated tag must not be saved")
+	}
+
+	cache.Set("product/0x2", "fresh", cache.Generation(), "0x2")
+	if _, ok := cache.Get("product/0x2"); !ok {
+		test.Error("Value read after invalidation must be saved")
+	}
+}
+
+func TestForgottenInvalidationsPreventSavingOfOlderValues(test *testing.T) {
+	limit := InvalidatedTagsLimit
+	defer func() { InvalidatedTagsLimit = limit }()
+	InvalidatedTagsLimit = 2
+
+	cache := New("test-forgotten-tags", 10, time.Minute)
+
+	generation := cache.Generation()
+	cache.Invalidate("0x1", "0x2")
+	cache.Invalidate("0x3")
+
+	cache.Set("company/0x4", "company", generation, "0x4")
+	if _, ok := cache.Get("company/0x4"); ok {
+		test.Error("Value read before forgotten invalidations must not be saved")
+	}
+
+	cache.Set("company/0x4", "company", cache.Generation(), "0x4")
+	if _, ok := cache.Get("company/0x4"); !ok {
+		test.Error("Value read after forgotten invalidations must be saved")
+	}
+}
+
+func TestNilCacheIsDisabled(test *testing.T) {
+	cache := New("test-disabled", 0, time.Minute)
+	if cache != nil {
+		test.Fatal("Cache without capacity must be disabled")
+	}
+
+	cache.Set("key", "value", cache.Generation(), "tag")
+	cache.Invalidate("tag")
+
+	if _, ok := cache.Get("key"); ok || cache.Len() != 0 {
+		test.Error("Nothing must be saved in disabled cache")
+	}
+}
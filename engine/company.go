@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/hecatoncheir/Broker"
@@ -58,7 +59,7 @@ func (entity *Company) ParseAll(instructions []InstructionOfCompany) error {
 			Message: "Need products of category of company",
 			Data:    string(data)}
 
-		go entity.Broker.Write(message)
+		go publish(context.Background(), entity.Broker, message)
 	}
 
 	return nil
@@ -66,7 +67,7 @@ func (entity *Company) ParseAll(instructions []InstructionOfCompany) error {
 
 // GetInstructions is a method of Company for get all instruction of category for parse products
 func (entity *Company) GetInstructions() ([]InstructionOfCompany, error) {
-	instructions, err := entity.Storage.Instructions.ReadAllInstructionsForCompany(entity.ID, ".")
+	instructions, err := entity.Storage.Instructions.ReadAllInstructionsForCompany(context.Background(), entity.ID, ".")
 	if err != nil {
 		return nil, err
 	}
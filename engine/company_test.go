@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -12,30 +13,35 @@ func TestIntegrationCompanyCanGetInstructions(test *testing.T) {
 	config := configuration.New()
 
 	store := storage.New(config.Development.Database.Host, config.Development.Database.Port)
-	err := store.SetUp()
+	_, err := store.Migrate(context.Background())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	err = store.SetUp(context.Background())
 	if err != nil {
 		test.Fatalf(err.Error())
 	}
 
-	createdCompany, err := store.Companies.CreateCompany(storage.Company{Name: "Company test name"}, "en")
+	createdCompany, err := store.Companies.CreateCompany(context.Background(), storage.Company{Name: "Company test name"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := store.Companies.DeleteCompany(createdCompany)
+		_, err := store.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := store.Instructions.CreateInstructionForCompany(createdCompany.ID, "en")
+	instruction, err := store.Instructions.CreateInstructionForCompany(context.Background(), createdCompany.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := store.Instructions.DeleteInstruction(instruction)
+		_, err := store.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
@@ -45,9 +51,9 @@ func TestIntegrationCompanyCanGetInstructions(test *testing.T) {
 		test.Error(err)
 	}
 
-	city, err := store.Cities.CreateCity(storage.City{Name: "Test city"}, "en")
+	city, err := store.Cities.CreateCity(context.Background(), storage.City{Name: "Test city"}, "en")
 	defer func() {
-		_, err := store.Cities.DeleteCity(city)
+		_, err := store.Cities.DeleteCity(context.Background(), city)
 		if err != nil {
 			test.Error(err)
 		}
@@ -57,7 +63,7 @@ func TestIntegrationCompanyCanGetInstructions(test *testing.T) {
 		test.Error(err)
 	}
 
-	err = store.Instructions.AddCityToInstruction(instruction.ID, city.ID)
+	err = store.Instructions.AddCityToInstruction(context.Background(), instruction.ID, city.ID)
 	if err != nil {
 		test.Error(err)
 	}
@@ -71,19 +77,19 @@ func TestIntegrationCompanyCanGetInstructions(test *testing.T) {
 		NameOfItemSelector:       ".product-tile-title",
 		PriceOfItemSelector:      ".product-price-current"}
 
-	page, err := store.Instructions.CreatePageInstruction(mVideoPageInstruction)
+	page, err := store.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := store.Instructions.DeletePageInstruction(page)
+		_, err := store.Instructions.DeletePageInstruction(context.Background(), page)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = store.Instructions.AddPageInstructionToInstruction(instruction.ID, page.ID)
+	err = store.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, page.ID)
 	if err != nil {
 		test.Error(err)
 	}
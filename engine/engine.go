@@ -1,37 +1,91 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"time"
 
 	"github.com/hecatoncheir/Broker"
 	"github.com/hecatoncheir/Configuration"
 	"github.com/hecatoncheir/Logger"
+	"github.com/hecatoncheir/Sproot/engine/cache"
+	"github.com/hecatoncheir/Sproot/engine/logging"
 	"github.com/hecatoncheir/Sproot/engine/modeler"
+	"github.com/hecatoncheir/Sproot/engine/settings"
 	"github.com/hecatoncheir/Sproot/engine/storage"
-	"time"
+	"github.com/hecatoncheir/Sproot/engine/tracing"
 )
 
 // Engine is a main object of engine pkg
 type Engine struct {
 	Configuration *configuration.Configuration
+	Settings      settings.Settings
 	Storage       *storage.Storage
 	Broker        *broker.Broker
 	Logger        *logger.LogWriter
 	Modeler       *modeler.Modeler
+	health        health
 }
 
-// New is a constructor for Engine
-func New(config *configuration.Configuration) *Engine {
-	engine := Engine{Configuration: config}
-	return &engine
+// New is a constructor for Engine with settings of production environment of configuration,
+// configuration which settings can't be read is an error
+func New(config *configuration.Configuration) (*Engine, error) {
+	productionSettings, err := settings.New(config, settings.Production)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := Engine{Configuration: config, Settings: productionSettings}
+	return &engine, nil
+}
+
+// newStorage make storage with endpoints, TLS, keepalive and timeouts of settings
+func (engine *Engine) newStorage(host string, port int) (*storage.Storage, error) {
+	endpoints, err := storage.ParseEndpoints(engine.Settings.DatabaseEndpoints, port)
+	if err != nil {
+		return nil, err
+	}
+
+	store := storage.New(host, port, endpoints...)
+
+	tls := engine.Settings.DatabaseTLS
+	if tls.IsEnabled() {
+		store.TLS = &storage.TLSConfig{
+			CAFile:     tls.CAFile,
+			CertFile:   tls.CertFile,
+			KeyFile:    tls.KeyFile,
+			ServerName: tls.ServerName}
+	}
+
+	if engine.Settings.DatabaseKeepalive > 0 {
+		store.KeepaliveTime = engine.Settings.DatabaseKeepalive
+	}
+
+	if engine.Settings.QueryTimeout > 0 {
+		store.QueryTimeout = engine.Settings.QueryTimeout
+	}
+
+	if engine.Settings.MutationTimeout > 0 {
+		store.MutationTimeout = engine.Settings.MutationTimeout
+	}
+
+	store.Cache = cache.New("storage", engine.Settings.CacheSize, engine.Settings.CacheTTL)
+	store.DefaultCurrency = engine.Settings.DefaultCurrency
+	store.ReferenceCurrency = engine.Settings.ReferenceCurrency
+
+	return store, nil
 }
 
 // SetUpStorage for make connect to database and prepare client for requests
 func (engine *Engine) SetUpStorage(host string, port int) error {
-	engine.Storage = storage.New(host, port)
-	err := engine.Storage.SetUp()
+	store, err := engine.newStorage(host, port)
+	if err != nil {
+		return err
+	}
+
+	engine.Storage = store
+	err = engine.Storage.SetUp(context.Background())
 	if err != nil {
 		return err
 	}
@@ -39,20 +93,38 @@ func (engine *Engine) SetUpStorage(host string, port int) error {
 	return nil
 }
 
-func (engine *Engine) SetUpModel() error {
+// MigrateStorage for apply pending migrations of schema to database
+func (engine *Engine) MigrateStorage(host string, port int) ([]storage.Migration, error) {
+	store, err := engine.newStorage(host, port)
+	if err != nil {
+		return nil, err
+	}
+
+	engine.Storage = store
+	return engine.Storage.Migrate(context.Background())
+}
+
+// SetUpModel for apply seed file with companies, categories, cities and instructions to storage
+func (engine *Engine) SetUpModel(ctx context.Context, seedFilePath string) error {
 
 	engine.Modeler = modeler.New(engine.Storage)
-	err := engine.Modeler.SetUpAll()
+	report, err := engine.Modeler.SetUpFromFile(ctx, seedFilePath)
 	if err != nil {
 		return err
 	}
 
+	logging.Default.With(logging.Fields{"seed": seedFilePath}).Info(fmt.Sprintf("Seed applied:\n%v", report))
+
+	engine.health.Lock()
+	engine.health.modelReady = true
+	engine.health.Unlock()
+
 	return nil
 }
 
 // SetUpBroker for make connect to broker and prepare client for requests
 func (engine *Engine) SetUpBroker(host string, port int) error {
-	bro := broker.New(engine.Configuration.APIVersion, engine.Configuration.ServiceName)
+	bro := broker.New(engine.Settings.APIVersion, engine.Settings.ServiceName)
 	engine.Broker = bro
 
 	err := bro.Connect(host, port)
@@ -61,198 +133,225 @@ func (engine *Engine) SetUpBroker(host string, port int) error {
 	}
 
 	engine.Logger = logger.New(
-		engine.Configuration.APIVersion,
-		engine.Configuration.ServiceName, engine.Configuration.Production.LogunaTopic, bro)
+		engine.Settings.APIVersion,
+		engine.Settings.ServiceName, engine.Settings.LogunaTopic, bro)
+
+	logging.Default.SetRemote(brokerSink{writer: engine.Logger})
 
 	return nil
 }
 
 func (engine *Engine) SubscribeOnEvents(inputTopic string) {
 
-	fmt.Println("Subscribed on events")
+	logging.Default.With(logging.Fields{"topic": inputTopic}).Info("Subscribed on events")
+
+	engine.health.Lock()
+	engine.health.subscribed = true
+	engine.health.Unlock()
+
+	defer func() {
+		engine.health.Lock()
+		engine.health.finished = true
+		engine.health.Unlock()
+	}()
 
 	for event := range engine.Broker.InputChannel {
-		log.Println(fmt.Sprintf("Received message: '%v' with data: %v", event.Message, event.Data))
+		logging.Default.With(logging.Fields{"event": event.Message, "clientID": event.ClientID, "data": event.Data}).
+			Debug("Received message")
+		eventsReceived.Inc(event.Message)
 
 		if event.Message == "Need items by name" {
 			details := storage.ProductsByNameForPage{}
 			err := json.Unmarshal([]byte(event.Data), &details)
 			if err != nil {
-				log.Println(err)
+				logging.Default.With(logging.Fields{"event": event.Message, "clientID": event.ClientID}).Error(err.Error())
 			}
 
-			go engine.productsByNameAndPaginationHandler(
-				details, event.ClientID, event.APIVersion, engine.Configuration.Production.InitialTopic)
+			clientID, APIVersion := event.ClientID, event.APIVersion
+			go engine.handle(event, func(ctx context.Context) {
+				engine.productsByNameAndPaginationHandler(ctx, details, clientID, APIVersion, engine.Settings.InitialTopic)
+			})
 		}
 
 		if event.Message == "Product of category of company ready" {
-			go engine.productOfCategoryOfCompanyReadyEventHandler(event.Data)
+			data := event.Data
+			go engine.handle(event, func(ctx context.Context) { engine.productOfCategoryOfCompanyReadyEventHandler(ctx, data) })
+		}
+
+		if event.Message == "Need prices report" {
+			data, clientID, APIVersion := event.Data, event.ClientID, event.APIVersion
+			go engine.handle(event, func(ctx context.Context) { engine.pricesReportHandler(ctx, data, clientID, APIVersion) })
+		}
+
+		if event.Message == "Need price statistics of product" {
+			data, clientID, APIVersion := event.Data, event.ClientID, event.APIVersion
+			go engine.handle(event, func(ctx context.Context) {
+				engine.priceStatisticsHandler(ctx, "Need price statistics of product", "Price statistics of product ready",
+					data, clientID, APIVersion, engine.ReadPriceStatistics)
+			})
+		}
+
+		if event.Message == "Need price statistics of categories" {
+			data, clientID, APIVersion := event.Data, event.ClientID, event.APIVersion
+			go engine.handle(event, func(ctx context.Context) {
+				engine.priceStatisticsHandler(ctx, "Need price statistics of categories", "Price statistics of categories ready",
+					data, clientID, APIVersion, engine.ReadCategoriesPriceStatistics)
+			})
 		}
 
 		if event.Message == "Products of categories of companies must be parsed" {
-			go engine.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(engine.Configuration.Production.HecatoncheirTopic)
+			go engine.handle(event, func(ctx context.Context) {
+				engine.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(ctx, engine.Settings.HecatoncheirTopic)
+			})
 		}
 	}
 }
 
-func (engine *Engine) productsByNameAndPaginationHandler(
+func (engine *Engine) productsByNameAndPaginationHandler(ctx context.Context,
 	details storage.ProductsByNameForPage, clientID, APIVersion string, outputTopic string) {
 
-	logMessage := fmt.Sprintf("Input event of search product by name: %v", details.SearchedName)
-	logEvent := logger.LogData{Message: logMessage, Level: "info", Time: time.Now().UTC()}
-	err := engine.Logger.Write(logEvent)
-	if err != nil {
-		log.Println(err)
-	}
+	eventLog := logOf(ctx, logging.Fields{
+		"event": "Need items by name", "clientID": clientID, "name": details.SearchedName})
+	eventLog.Info("Input event of search product by name")
 
-	productsForPage, err := engine.Storage.Products.ReadProductsByNameWithPagination(
-		details.SearchedName, details.Language, details.CurrentPage, details.TotalProductsForOnePage)
-
-	if err != nil && err != storage.ErrProductsByNameNotFound {
-		log.Println(err)
-	}
+	searchCtx, span := tracing.Start(storage.WithConsistency(ctx, storage.BestEffort), "search products by name")
+	span.SetAttribute("name", details.SearchedName)
+	span.SetAttribute("page", details.CurrentPage)
 
-	if err != nil && err == storage.ErrProductsByNameNotFound {
-		data, err := json.Marshal(productsForPage)
-		if err != nil {
-			log.Println(err)
-		}
+	startedAt := time.Now()
+	productsForPage, err := engine.Storage.Products.ReadProductsByNameWithPagination(searchCtx,
+		details.SearchedName, details.Language, details.CurrentPage, details.TotalProductsForOnePage, details.Projection)
+	duration := time.Since(startedAt)
+	searchDuration.Observe(duration.Seconds())
 
-		logMessage := fmt.Sprintf("Output event no products found by name: %v", details.SearchedName)
-		logEvent := logger.LogData{Message: logMessage, Level: "info", Time: time.Now().UTC()}
-		err = engine.Logger.Write(logEvent)
-		if err != nil {
-			log.Println(err)
-		}
+	if err != nil {
+		span.SetError(err)
+		span.Finish()
 
-		event := broker.EventData{
-			Message:    "Items by name not found",
-			Data:       string(data),
-			APIVersion: APIVersion,
-			ClientID:   clientID}
+		eventLog.With(logging.Fields{"duration": duration, "kind": KindOf(err)}).Error(err.Error())
+		engine.replyWithError(ctx, "Items by name can not be found", err, clientID, APIVersion)
 
-		go engine.Broker.Write(event)
+		return
 	}
 
-	if err == nil {
-		data, err := json.Marshal(productsForPage)
-		if err != nil {
-			log.Println(err)
-		}
+	span.SetAttribute("products", len(productsForPage.Products))
+	span.Finish()
+	searchResults.Observe(float64(len(productsForPage.Products)))
 
-		event := broker.EventData{
-			Message:    "Items by name ready",
-			Data:       string(data),
-			APIVersion: APIVersion,
-			ClientID:   clientID}
-
-		logMessage := fmt.Sprintf("Output event found products: %v by name: %v",
-			len(productsForPage.Products), details.SearchedName)
-		logEvent := logger.LogData{Message: logMessage, Level: "info", Time: time.Now().UTC()}
-		go func() {
-			err := engine.Logger.Write(logEvent)
-			if err != nil {
-				log.Println(err)
-			}
-		}()
+	eventLog = eventLog.With(logging.Fields{"duration": duration})
 
-		go engine.Broker.Write(event)
+	data, err := json.Marshal(productsForPage)
+	if err != nil {
+		eventLog.Error(err.Error())
+	}
+
+	event := broker.EventData{
+		Message:    "Items by name ready",
+		Data:       string(data),
+		APIVersion: APIVersion,
+		ClientID:   clientID}
+
+	if len(productsForPage.Products) == 0 {
+		event.Message = "Items by name not found"
+		eventLog.Info("Output event no products found by name")
+	} else {
+		eventLog.With(logging.Fields{"products": len(productsForPage.Products)}).Info("Output event found products")
 	}
 
+	go publish(ctx, engine.Broker, event)
 }
 
-func (engine *Engine) productOfCategoryOfCompanyReadyEventHandler(productOfCategoryOfCompanyData string) {
+func (engine *Engine) productOfCategoryOfCompanyReadyEventHandler(ctx context.Context, productOfCategoryOfCompanyData string) {
+	eventLog := logOf(ctx, logging.Fields{"event": "Product of category of company ready"})
+
 	product := ProductOfCompany{}
 	err := json.Unmarshal([]byte(productOfCategoryOfCompanyData), &product)
 	if err != nil {
-		log.Println(err)
+		eventLog.Error(err.Error())
 	}
 
-	go func() {
-		if engine.Logger != nil {
-			logMessage := fmt.Sprintf("Input event with product: %v", product)
-			logEvent := logger.LogData{Message: logMessage, Level: "info", Time: time.Now().UTC()}
+	eventLog = eventLog.With(logging.Fields{"company": product.Company.Name, "product": product.Name})
+	eventLog.Info("Input event with product")
 
-			err := engine.Logger.Write(logEvent)
-			if err != nil {
-				log.Println(err)
-			}
-		}
-	}()
+	ctx, span := tracing.Start(ctx, "update product in storage")
+	span.SetAttribute("company", product.Company.Name)
+	span.SetAttribute("product", product.Name)
+	defer span.Finish()
 
-	_, err = product.UpdateInStorage(engine.Storage)
+	policy := storage.IngestPolicy{Mode: engine.Settings.PriceIngest, Heartbeat: engine.Settings.PriceHeartbeat}
+	productInStorage, err := product.UpdateInStorage(ctx, engine.Storage, policy)
 	if err != nil {
-		log.Println(err)
+		span.SetError(err)
+		eventLog.Error(err.Error())
+		return
 	}
+
+	eventLog.With(logging.Fields{"productID": productInStorage.ID}).Debug("Product updated in storage")
 }
 
-func (engine *Engine) productsOfCategoriesOfCompaniesMustBeParsedEventHandler(outputTopic string) {
+func (engine *Engine) productsOfCategoriesOfCompaniesMustBeParsedEventHandler(ctx context.Context, outputTopic string) {
 	supportedLanguages := []string{"ru"}
 
-	logMessage := fmt.Sprintf("Input event for starting parse products of categories of companies")
-	logEvent := logger.LogData{Message: logMessage, Level: "info", Time: time.Now().UTC()}
-
-	if engine.Logger != nil {
-		go func() {
-			err := engine.Logger.Write(logEvent)
-			if err != nil {
-				fmt.Println("Error ", err)
-			}
-		}()
-	}
+	eventLog := logOf(ctx, logging.Fields{
+		"event": "Products of categories of companies must be parsed", "topic": outputTopic})
+	eventLog.Info("Input event for starting parse products of categories of companies")
 
 	for _, language := range supportedLanguages {
-		allCompanies, err := engine.Storage.Companies.ReadAllCompanies(language)
+		allCompanies, err := engine.Storage.Companies.ReadAllCompanies(ctx, language)
 		if err != nil {
-			log.Println(err)
+			eventLog.Error(err.Error())
 		}
 
 		for _, company := range allCompanies {
 
 			for _, category := range company.Categories {
 
-				cities, err := engine.Storage.Cities.ReadAllCities(language)
+				cities, err := engine.Storage.Cities.ReadAllCities(ctx, language)
 				if err != nil {
-					log.Println(err)
+					eventLog.Error(err.Error())
 				}
 
 				for _, city := range cities {
 
-					instructions, err := engine.Storage.Instructions.ReadAllInstructionsForCompany(
+					instructions, err := engine.Storage.Instructions.ReadAllInstructionsForCompany(ctx,
 						company.ID, language)
 					if err != nil {
-						log.Println(err)
+						eventLog.With(logging.Fields{"company": company.Name}).Error(err.Error())
 					}
 
 					for _, instruction := range instructions {
 
-						request := InstructionOfCompany{
-							Language: language,
-							Company: CompanyData{
-								ID:   company.ID,
-								Name: company.Name,
-								IRI:  company.IRI},
-							Category: CategoryData{
-								ID:   category.ID,
-								Name: category.Name},
-							City: CityData{
-								ID:   city.ID,
-								Name: city.Name},
-							PageInstruction: instruction.PagesInstruction[0],
+						for _, pageInstruction := range instruction.PagesInstruction {
+
+							request := InstructionOfCompany{
+								Language: language,
+								Company: CompanyData{
+									ID:   company.ID,
+									Name: company.Name,
+									IRI:  company.IRI},
+								Category: CategoryData{
+									ID:   category.ID,
+									Name: category.Name},
+								City: CityData{
+									ID:   city.ID,
+									Name: city.Name},
+								PageInstruction: pageInstruction,
+							}
+
+							data, err := json.Marshal(request)
+							if err != nil {
+								eventLog.Error(err.Error())
+							}
+
+							event := broker.EventData{
+								Message: "Need products of category of company",
+								Data:    string(data)}
+
+							eventLog.With(logging.Fields{"company": company.Name, "category": category.Name, "city": city.Name}).
+								Debug("Write: " + event.Message)
+
+							publish(ctx, engine.Broker, event)
 						}
-
-						data, err := json.Marshal(request)
-						if err != nil {
-							log.Println(err)
-						}
-
-						event := broker.EventData{
-							Message: "Need products of category of company",
-							Data:    string(data)}
-
-						println(fmt.Sprintf("Write: %v to %v", event.Message, outputTopic))
-
-						engine.Broker.Write(event)
 					}
 
 				}
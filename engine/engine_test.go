@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"testing"
 
 	"encoding/json"
@@ -17,8 +18,16 @@ func TestIntegrationEngineCanBeSetUp(test *testing.T) {
 
 	config := configuration.New()
 
-	engine := New(config)
-	err := engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
+	engine, err := New(config)
+	if err != nil {
+		test.Fatal(err)
+	}
+	_, err = engine.MigrateStorage(config.Development.Database.Host, config.Development.Database.Port)
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
 	if err != nil {
 		test.Error(err)
 	}
@@ -31,86 +40,94 @@ func TestIntegrationProductCanBeReturnFromParser(test *testing.T) {
 	test.Skip()
 
 	config := configuration.New()
-	puffer := New(config)
+	puffer, err := New(config)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = puffer.MigrateStorage(config.Development.Database.Host, config.Development.Database.Port)
+	if err != nil {
+		test.Error(err)
+	}
 
-	err := puffer.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
+	err = puffer.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
 	if err != nil {
 		test.Error(err)
 	}
 
 	companyForTest := storage.Company{Name: "М.Видео", IRI: "http://www.mvideo.ru/"}
-	createdCompany, err := puffer.Storage.Companies.CreateCompany(companyForTest, "ru")
+	createdCompany, err := puffer.Storage.Companies.CreateCompany(context.Background(), companyForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Companies.DeleteCompany(createdCompany)
+		_, err := puffer.Storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	categoryForTest := storage.Category{Name: "Смартфоны"}
-	createdCategory, err := puffer.Storage.Categories.CreateCategory(categoryForTest, "ru")
+	createdCategory, err := puffer.Storage.Categories.CreateCategory(context.Background(), categoryForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Categories.DeleteCategory(createdCategory)
+		_, err := puffer.Storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = puffer.Storage.Categories.AddCompanyToCategory(createdCategory.ID, createdCompany.ID)
+	err = puffer.Storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdCity, err := puffer.Storage.Cities.CreateCity(storage.City{Name: "Москва"}, "ru")
+	createdCity, err := puffer.Storage.Cities.CreateCity(context.Background(), storage.City{Name: "Москва"}, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Cities.DeleteCity(createdCity)
+		_, err := puffer.Storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	pageInstruction, err := puffer.Storage.Instructions.CreatePageInstruction(storage.PageInstruction{Path: "/test/"})
+	pageInstruction, err := puffer.Storage.Instructions.CreatePageInstruction(context.Background(), storage.PageInstruction{Path: "/test/"})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Instructions.DeletePageInstruction(pageInstruction)
+		_, err := puffer.Storage.Instructions.DeletePageInstruction(context.Background(), pageInstruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := puffer.Storage.Instructions.CreateInstructionForCompany(createdCompany.ID, "ru")
+	instruction, err := puffer.Storage.Instructions.CreateInstructionForCompany(context.Background(), createdCompany.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Instructions.DeleteInstruction(instruction)
+		_, err := puffer.Storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = puffer.Storage.Instructions.AddPageInstructionToInstruction(instruction.ID, pageInstruction.ID)
+	err = puffer.Storage.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, pageInstruction.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	go puffer.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(config.Development.SprootTopic)
+	go puffer.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(context.Background(), config.Development.SprootTopic)
 
 	nameOfProduct := ""
 
@@ -198,12 +215,12 @@ func TestIntegrationProductCanBeReturnFromParser(test *testing.T) {
 			test.Fail()
 		}
 
-		puffer.productOfCategoryOfCompanyReadyEventHandler(event.Data)
+		puffer.productOfCategoryOfCompanyReadyEventHandler(context.Background(), event.Data)
 
 		close(puffer.Broker.InputChannel)
 	}
 
-	category, err := puffer.Storage.Categories.ReadCategoryByID(createdCategory.ID, "ru")
+	category, err := puffer.Storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -220,7 +237,7 @@ func TestIntegrationProductCanBeReturnFromParser(test *testing.T) {
 		test.Fail()
 	}
 
-	products, err := puffer.Storage.Products.ReadProductsByName(nameOfProduct, "ru")
+	products, err := puffer.Storage.Products.ReadProductsByName(context.Background(), nameOfProduct, "ru", storage.Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -233,12 +250,12 @@ func TestIntegrationProductCanBeReturnFromParser(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = puffer.Storage.Products.DeleteProduct(products[0])
+	_, err = puffer.Storage.Products.DeleteProduct(context.Background(), products[0])
 	if err != nil {
 		test.Fail()
 	}
 
-	_, err = puffer.Storage.Prices.DeletePrice(products[0].Prices[0])
+	_, err = puffer.Storage.Prices.DeletePrice(context.Background(), products[0].Prices[0])
 	if err != nil {
 		test.Fail()
 	}
@@ -251,88 +268,96 @@ func TestIntegrationPriceCanBeReturnFromParser(test *testing.T) {
 	test.Skip()
 
 	config := configuration.New()
-	puffer := New(config)
+	puffer, err := New(config)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = puffer.MigrateStorage(config.Development.Database.Host, config.Development.Database.Port)
+	if err != nil {
+		test.Error(err)
+	}
 
-	err := puffer.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
+	err = puffer.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
 	if err != nil {
 		test.Error(err)
 	}
 
 	companyForTest := storage.Company{Name: "М.Видео", IRI: "http://www.mvideo.ru/"}
-	createdCompany, err := puffer.Storage.Companies.CreateCompany(companyForTest, "ru")
+	createdCompany, err := puffer.Storage.Companies.CreateCompany(context.Background(), companyForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Companies.DeleteCompany(createdCompany)
+		_, err := puffer.Storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	categoryForTest := storage.Category{Name: "Смартфоны"}
-	createdCategory, err := puffer.Storage.Categories.CreateCategory(categoryForTest, "ru")
+	createdCategory, err := puffer.Storage.Categories.CreateCategory(context.Background(), categoryForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Categories.DeleteCategory(createdCategory)
+		_, err := puffer.Storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = puffer.Storage.Categories.AddCompanyToCategory(createdCategory.ID, createdCompany.ID)
+	err = puffer.Storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdCity, err := puffer.Storage.Cities.CreateCity(storage.City{Name: "Москва"}, "ru")
+	createdCity, err := puffer.Storage.Cities.CreateCity(context.Background(), storage.City{Name: "Москва"}, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Cities.DeleteCity(createdCity)
+		_, err := puffer.Storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	pageInstruction, err := puffer.Storage.Instructions.CreatePageInstruction(storage.PageInstruction{Path: "/test/"})
+	pageInstruction, err := puffer.Storage.Instructions.CreatePageInstruction(context.Background(), storage.PageInstruction{Path: "/test/"})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Instructions.DeletePageInstruction(pageInstruction)
+		_, err := puffer.Storage.Instructions.DeletePageInstruction(context.Background(), pageInstruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := puffer.Storage.Instructions.CreateInstructionForCompany(createdCompany.ID, "ru")
+	instruction, err := puffer.Storage.Instructions.CreateInstructionForCompany(context.Background(), createdCompany.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := puffer.Storage.Instructions.DeleteInstruction(instruction)
+		_, err := puffer.Storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = puffer.Storage.Instructions.AddPageInstructionToInstruction(instruction.ID, pageInstruction.ID)
+	err = puffer.Storage.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, pageInstruction.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
 	puffer.Broker = broker.New(config.APIVersion, config.ServiceName)
 
-	go puffer.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(config.Development.SprootTopic)
+	go puffer.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(context.Background(), config.Development.SprootTopic)
 
 	nameOfProduct := ""
 
@@ -393,16 +418,16 @@ func TestIntegrationPriceCanBeReturnFromParser(test *testing.T) {
 
 		nameOfProduct = request.Name
 
-		puffer.productOfCategoryOfCompanyReadyEventHandler(event.Data)
+		puffer.productOfCategoryOfCompanyReadyEventHandler(context.Background(), event.Data)
 
 		go func() {
-			puffer.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(config.Development.SprootTopic)
+			puffer.productsOfCategoriesOfCompaniesMustBeParsedEventHandler(context.Background(), config.Development.SprootTopic)
 
 			close(puffer.Broker.InputChannel)
 		}()
 	}
 
-	category, err := puffer.Storage.Categories.ReadCategoryByID(createdCategory.ID, "ru")
+	category, err := puffer.Storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, "ru")
 	if err != nil {
 		test.Fail()
 	}
@@ -415,7 +440,7 @@ func TestIntegrationPriceCanBeReturnFromParser(test *testing.T) {
 		test.Fail()
 	}
 
-	products, err := puffer.Storage.Products.ReadProductsByName(nameOfProduct, "ru")
+	products, err := puffer.Storage.Products.ReadProductsByName(context.Background(), nameOfProduct, "ru", storage.Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -428,12 +453,12 @@ func TestIntegrationPriceCanBeReturnFromParser(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = puffer.Storage.Products.DeleteProduct(products[0])
+	_, err = puffer.Storage.Products.DeleteProduct(context.Background(), products[0])
 	if err != nil {
 		test.Fail()
 	}
 
-	_, err = puffer.Storage.Prices.DeletePrice(products[0].Prices[0])
+	_, err = puffer.Storage.Prices.DeletePrice(context.Background(), products[0].Prices[0])
 	if err != nil {
 		test.Fail()
 	}
@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hecatoncheir/Broker"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/sheet"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+// ErrRequestIsNotValid means that data of event can not be decoded as request
+var ErrRequestIsNotValid = errors.New("request is not valid")
+
+// ErrorReply is a data of broker event and body of http response about failed request
+type ErrorReply struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// KindOf is a function for get kind of error of engine or of storage
+func KindOf(err error) storage.Kind {
+	switch {
+	case errors.Is(err, ErrRequestIsNotValid), errors.Is(err, sheet.ErrFormatIsNotSupported):
+		return storage.InvalidInput
+	case errors.Is(err, ErrStorageIsNotSetUp), errors.Is(err, ErrBrokerIsNotSetUp):
+		return storage.Unavailable
+	}
+
+	return storage.KindOf(err)
+}
+
+// ReplyOf is a function for make reply about error for client,
+// message of error of storage does not contain cause of failure of database
+func ReplyOf(err error) ErrorReply {
+	reply := ErrorReply{Kind: KindOf(err).String(), Message: err.Error()}
+
+	var storageError *storage.Error
+	if errors.As(err, &storageError) {
+		reply.Message = storageError.Err.Error()
+	}
+
+	return reply
+}
+
+// StatusOf is a function for get http status of error by its kind
+func StatusOf(err error) int {
+	switch KindOf(err) {
+	case storage.NotFound:
+		return http.StatusNotFound
+	case storage.Conflict:
+		return http.StatusConflict
+	case storage.InvalidInput:
+		return http.StatusBadRequest
+	case storage.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
+// WriteError is a function for write http response with status and reply of error
+func WriteError(response http.ResponseWriter, err error) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(StatusOf(err))
+
+	encodeErr := json.NewEncoder(response).Encode(ReplyOf(err))
+	if encodeErr != nil {
+		logging.Default.Error(encodeErr.Error())
+	}
+}
+
+// replyWithError publish event for client with reply of error as data
+func (engine *Engine) replyWithError(ctx context.Context, message string, err error, clientID, APIVersion string) {
+	data, encodeErr := json.Marshal(ReplyOf(err))
+	if encodeErr != nil {
+		logOf(ctx, logging.Fields{"event": message, "clientID": clientID}).Error(encodeErr.Error())
+		return
+	}
+
+	event := broker.EventData{
+		Message:    message,
+		Data:       string(data),
+		APIVersion: APIVersion,
+		ClientID:   clientID}
+
+	go publish(ctx, engine.Broker, event)
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hecatoncheir/Sproot/engine/sheet"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+func TestReplyOfErrorHasKindAndMessageWithoutCause(test *testing.T) {
+	err := fmt.Errorf("search: %w", &storage.Error{
+		Kind: storage.Unavailable, Err: storage.ErrProductsByNameCanNotBeFound, Cause: errors.New("connection refused")})
+
+	reply := ReplyOf(err)
+	if reply.Kind != "unavailable" || reply.Message != "products by name can not be found" {
+		test.Error(reply)
+	}
+
+	reply = ReplyOf(sheet.ErrFormatIsNotSupported)
+	if reply.Kind != "invalid input" || reply.Message != sheet.ErrFormatIsNotSupported.Error() {
+		test.Error(reply)
+	}
+}
+
+func TestErrorIsWrittenWithStatusOfKind(test *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{&storage.Error{Kind: storage.NotFound, Err: storage.ErrProductDoesNotExist}, http.StatusNotFound},
+		{&storage.Error{Kind: storage.Conflict, Err: storage.ErrProductAlreadyExist}, http.StatusConflict},
+		{fmt.Errorf("%w: unexpected end of JSON input", ErrRequestIsNotValid), http.StatusBadRequest},
+		{ErrStorageIsNotSetUp, http.StatusServiceUnavailable},
+		{errors.New("unknown"), http.StatusInternalServerError}}
+
+	for _, testCase := range cases {
+		response := httptest.NewRecorder()
+		WriteError(response, testCase.err)
+
+		if response.Code != testCase.status {
+			test.Errorf("Expected status %v of %v, actual: %v", testCase.status, testCase.err, response.Code)
+		}
+
+		reply := ErrorReply{}
+		err := json.Unmarshal(response.Body.Bytes(), &reply)
+		if err != nil || reply.Message == "" {
+			test.Error(err, reply)
+		}
+	}
+}
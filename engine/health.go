@@ -0,0 +1,257 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hecatoncheir/Broker"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+	"github.com/hecatoncheir/Sproot/engine/tracing"
+)
+
+var (
+	// CheckTimeout is a limit of time of one check of health
+	CheckTimeout = 3 * time.Second
+
+	// MaxHandlerDuration is a time after which running handler of event is considered stuck
+	MaxHandlerDuration = 5 * time.Minute
+)
+
+var (
+	// ErrCheckTimedOut means that check of health did not finish in CheckTimeout
+	ErrCheckTimedOut = errors.New("check timed out")
+
+	// ErrStorageIsNotSetUp means that engine is not connected to database yet
+	ErrStorageIsNotSetUp = errors.New("storage is not set up")
+
+	// ErrBrokerIsNotSetUp means that engine is not connected to broker yet
+	ErrBrokerIsNotSetUp = errors.New("broker is not set up")
+
+	// ErrModelIsNotSetUp means that seed is not applied to storage yet
+	ErrModelIsNotSetUp = errors.New("model is not set up")
+
+	// ErrEventsAreNotSubscribed means that event loop is not started yet
+	ErrEventsAreNotSubscribed = errors.New("events are not subscribed")
+
+	// ErrEventLoopIsFinished means that input channel of broker is closed and events are not handled
+	ErrEventLoopIsFinished = errors.New("event loop is finished")
+
+	// ErrHandlerIsStuck means that handler of event runs longer than MaxHandlerDuration
+	ErrHandlerIsStuck = errors.New("handler of event is stuck")
+)
+
+// Check is a result of one check of health
+type Check struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthReport is a result of all checks of liveness or readiness
+type HealthReport struct {
+	Healthy bool    `json:"healthy"`
+	Checks  []Check `json:"checks"`
+}
+
+// health is a state of engine for checks, it is changed by set up methods and event loop
+type health struct {
+	sync.Mutex
+	modelReady     bool
+	subscribed     bool
+	finished       bool
+	lastHandlerID  int
+	handlersStarts map[int]time.Time
+}
+
+// handle run handler of event in span which continues trace of event, remember when handler is started
+// for check of liveness and measure its duration
+func (engine *Engine) handle(event broker.EventData, handler func(ctx context.Context)) {
+	message := event.Message
+
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), event.Data), "handle "+message)
+	span.SetAttribute("event", message)
+	span.SetAttribute("clientID", event.ClientID)
+	defer span.Finish()
+
+	engine.health.Lock()
+	if engine.health.handlersStarts == nil {
+		engine.health.handlersStarts = map[int]time.Time{}
+	}
+
+	engine.health.lastHandlerID++
+	id := engine.health.lastHandlerID
+	engine.health.handlersStarts[id] = time.Now()
+	engine.health.Unlock()
+
+	defer func(startedAt time.Time) {
+		duration := time.Since(startedAt)
+		handlerDuration.Observe(duration.Seconds(), message)
+		logOf(ctx, logging.Fields{"event": message, "duration": duration}).Debug("Event handled")
+
+		engine.health.Lock()
+		delete(engine.health.handlersStarts, id)
+		engine.health.Unlock()
+	}(time.Now())
+
+	handler(ctx)
+}
+
+// check run check with CheckTimeout
+func check(name string, checker func() error) Check {
+	result := make(chan error, 1)
+	go func() { result <- checker() }()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-time.After(CheckTimeout):
+		err = ErrCheckTimedOut
+	}
+
+	if err != nil {
+		return Check{Name: name, Healthy: false, Error: err.Error()}
+	}
+
+	return Check{Name: name, Healthy: true}
+}
+
+func reportOf(checks ...Check) HealthReport {
+	report := HealthReport{Healthy: true, Checks: checks}
+	for _, check := range checks {
+		if !check.Healthy {
+			report.Healthy = false
+		}
+	}
+
+	return report
+}
+
+// Liveness is a method for check that event loop is not finished and handlers of events are not stuck
+func (engine *Engine) Liveness() HealthReport {
+	return reportOf(check("events", func() error {
+		engine.health.Lock()
+		defer engine.health.Unlock()
+
+		if engine.health.finished {
+			return ErrEventLoopIsFinished
+		}
+
+		for _, startedAt := range engine.health.handlersStarts {
+			if time.Since(startedAt) > MaxHandlerDuration {
+				return ErrHandlerIsStuck
+			}
+		}
+
+		return nil
+	}))
+}
+
+// Readiness is a method for check connections to database and broker,
+// version of schema, applied seed and subscription on events
+func (engine *Engine) Readiness() HealthReport {
+	version := -1
+
+	database := check("database", func() error {
+		if engine.Storage == nil || engine.Storage.Client == nil {
+			return ErrStorageIsNotSetUp
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), CheckTimeout)
+		defer cancel()
+
+		var err error
+		version, err = engine.Storage.SchemaVersion(ctx)
+
+		return err
+	})
+
+	schema := check("schema", func() error {
+		if !database.Healthy {
+			return storage.ErrSchemaVersionCanNotBeRead
+		}
+
+		if version > storage.LatestSchemaVersion() {
+			return storage.ErrSchemaIsNewer
+		}
+
+		if version < storage.LatestSchemaVersion() {
+			return storage.ErrSchemaIsOutdated
+		}
+
+		return nil
+	})
+
+	eventBus := check("broker", func() error {
+		if engine.Broker == nil {
+			return ErrBrokerIsNotSetUp
+		}
+
+		address := net.JoinHostPort(engine.Settings.EventBus.Host, fmt.Sprint(engine.Settings.EventBus.Port))
+		connection, err := net.DialTimeout("tcp", address, CheckTimeout)
+		if err != nil {
+			return err
+		}
+
+		return connection.Close()
+	})
+
+	model := check("model", func() error {
+		engine.health.Lock()
+		defer engine.health.Unlock()
+
+		if !engine.health.modelReady {
+			return ErrModelIsNotSetUp
+		}
+
+		return nil
+	})
+
+	events := check("events", func() error {
+		engine.health.Lock()
+		defer engine.health.Unlock()
+
+		if !engine.health.subscribed {
+			return ErrEventsAreNotSubscribed
+		}
+
+		if engine.health.finished {
+			return ErrEventLoopIsFinished
+		}
+
+		return nil
+	})
+
+	return reportOf(database, schema, eventBus, model, events)
+}
+
+func healthHandler(report func() HealthReport) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		result := report()
+
+		response.Header().Set("Content-Type", "application/json")
+		if !result.Healthy {
+			response.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		err := json.NewEncoder(response).Encode(result)
+		if err != nil {
+			logging.Default.With(logging.Fields{"path": request.URL.Path}).Error(err.Error())
+		}
+	}
+}
+
+// HealthHandler is a http handler with /health/live and /health/ready endpoints for probes of Kubernetes
+func (engine *Engine) HealthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/health/live", healthHandler(engine.Liveness))
+	mux.Handle("/health/ready", healthHandler(engine.Readiness))
+
+	return mux
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hecatoncheir/Broker"
+	"github.com/hecatoncheir/Configuration"
+)
+
+func TestEngineIsNotReadyBeforeSetUp(test *testing.T) {
+	engine, err := New(configuration.New())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	report := engine.Readiness()
+	if report.Healthy {
+		test.Fatal("Engine without storage and broker must not be ready")
+	}
+
+	expected := map[string]string{
+		"database": ErrStorageIsNotSetUp.Error(),
+		"broker":   ErrBrokerIsNotSetUp.Error(),
+		"model":    ErrModelIsNotSetUp.Error(),
+		"events":   ErrEventsAreNotSubscribed.Error()}
+
+	for _, check := range report.Checks {
+		message, ok := expected[check.Name]
+		if ok && check.Error != message {
+			test.Errorf("Check %v: %v", check.Name, check.Error)
+		}
+	}
+}
+
+func TestEngineIsNotAliveWithStuckHandler(test *testing.T) {
+	engine, err := New(configuration.New())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if !engine.Liveness().Healthy {
+		test.Fatal("Engine without running handlers must be alive")
+	}
+
+	limit := MaxHandlerDuration
+	MaxHandlerDuration = time.Millisecond
+	defer func() { MaxHandlerDuration = limit }()
+
+	release := make(chan bool)
+	go engine.handle(broker.EventData{Message: "Test"}, func(ctx context.Context) { <-release })
+
+	time.Sleep(10 * time.Millisecond)
+
+	report := engine.Liveness()
+	if report.Healthy || report.Checks[0].Error != ErrHandlerIsStuck.Error() {
+		test.Error(report)
+	}
+
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+
+	if !engine.Liveness().Healthy {
+		test.Error("Engine must be alive after handler is finished")
+	}
+}
+
+func TestHealthEndpointsAnswerWithStatusOfChecks(test *testing.T) {
+	engine, err := New(configuration.New())
+	if err != nil {
+		test.Fatal(err)
+	}
+	server := httptest.NewServer(engine.HealthHandler())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/health/live")
+	if err != nil {
+		test.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		test.Error(response.Status)
+	}
+
+	response, err = http.Get(server.URL + "/health/ready")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusServiceUnavailable {
+		test.Error(response.Status)
+	}
+
+	report := HealthReport{}
+	err = json.NewDecoder(response.Body).Decode(&report)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if report.Healthy || len(report.Checks) != 5 {
+		test.Error(report)
+	}
+}
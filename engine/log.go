@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/hecatoncheir/Logger"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/tracing"
+)
+
+// logOf is a function for get logger with fields and with identifier of trace of context
+func logOf(ctx context.Context, fields logging.Fields) *logging.Logger {
+	spanContext := tracing.SpanContextOf(ctx)
+	if spanContext.IsValid() {
+		fields["traceID"] = spanContext.TraceID.String()
+	}
+
+	return logging.Default.With(fields)
+}
+
+// brokerSink write entries of log to topic of logs through logger of broker
+type brokerSink struct {
+	writer *logger.LogWriter
+}
+
+func (sink brokerSink) Write(entry logging.Entry) error {
+	message := entry.Message
+	if len(entry.Fields) > 0 {
+		message += " " + logging.FormatFields(entry.Fields)
+	}
+
+	return sink.writer.Write(logger.LogData{Message: message, Level: entry.Level.String(), Time: entry.Time})
+}
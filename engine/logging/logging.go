@@ -0,0 +1,216 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level of entry of log
+type Level int
+
+const (
+	// Debug level is for details of work of engine
+	Debug Level = iota
+
+	// Info level is for events of engine
+	Info
+
+	// Warning level is for problems which do not stop handling of event
+	Warning
+
+	// Error level is for failed operations
+	Error
+)
+
+var levelNames = []string{"debug", "info", "warning", "error"}
+
+func (level Level) String() string {
+	if level < Debug || level > Error {
+		return fmt.Sprintf("level(%d)", int(level))
+	}
+
+	return levelNames[level]
+}
+
+// ErrLevelIsNotSupported means that name of level is not debug, info, warning or error
+var ErrLevelIsNotSupported = errors.New("level of log is not supported")
+
+// ParseLevel is a function for get level by name
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(level), nil
+		}
+	}
+
+	return Info, ErrLevelIsNotSupported
+}
+
+// Fields are structured details of entry like event, clientID, company, productID, duration
+type Fields map[string]interface{}
+
+// Entry of log
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  Fields
+}
+
+// Sink is a destination of entries of log
+type Sink interface {
+	Write(entry Entry) error
+}
+
+// output is shared by logger and all loggers made by With
+type output struct {
+	mutex  sync.RWMutex
+	level  Level
+	local  Sink
+	remote Sink
+}
+
+// Logger write entries with level not lower than level of logger to remote sink
+// or to local sink when remote sink is not set or can not write entry
+type Logger struct {
+	output *output
+	fields Fields
+}
+
+// New is a constructor for Logger with local sink
+func New(level Level, local Sink) *Logger {
+	return &Logger{output: &output{level: level, local: local}}
+}
+
+// Default is a logger of engine and storage, entries are written to stderr until remote sink is set
+var Default = New(Info, NewTextSink(os.Stderr))
+
+// SetLevel is a method for change minimal level of written entries
+func (logger *Logger) SetLevel(level Level) {
+	logger.output.mutex.Lock()
+	logger.output.level = level
+	logger.output.mutex.Unlock()
+}
+
+// SetRemote is a method for set sink like broker of logs, nil remove remote sink
+func (logger *Logger) SetRemote(remote Sink) {
+	logger.output.mutex.Lock()
+	logger.output.remote = remote
+	logger.output.mutex.Unlock()
+}
+
+// With is a method for make logger which adds fields to each entry
+func (logger *Logger) With(fields Fields) *Logger {
+	merged := make(Fields, len(logger.fields)+len(fields))
+	for key, value := range logger.fields {
+		merged[key] = value
+	}
+
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return &Logger{output: logger.output, fields: merged}
+}
+
+// Debug is a method for write entry with Debug level
+func (logger *Logger) Debug(message string) {
+	logger.write(Debug, message)
+}
+
+// Info is a method for write entry with Info level
+func (logger *Logger) Info(message string) {
+	logger.write(Info, message)
+}
+
+// Warning is a method for write entry with Warning level
+func (logger *Logger) Warning(message string) {
+	logger.write(Warning, message)
+}
+
+// Error is a method for write entry with Error level
+func (logger *Logger) Error(message string) {
+	logger.write(Error, message)
+}
+
+func (logger *Logger) write(level Level, message string) {
+	logger.output.mutex.RLock()
+	minimal, local, remote := logger.output.level, logger.output.local, logger.output.remote
+	logger.output.mutex.RUnlock()
+
+	if level < minimal {
+		return
+	}
+
+	entry := Entry{Time: time.Now().UTC(), Level: level, Message: message, Fields: logger.fields}
+
+	if remote != nil {
+		err := remote.Write(entry)
+		if err == nil {
+			return
+		}
+
+		entry.Fields = logger.With(Fields{"remoteError": err.Error()}).fields
+	}
+
+	if local != nil {
+		local.Write(entry)
+	}
+}
+
+// FormatFields is a function for format fields as key=value pairs sorted by key
+func FormatFields(fields Fields) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for index, key := range keys {
+		value := fmt.Sprint(fields[key])
+		if strings.ContainsAny(value, " \"=\n\t") || value == "" {
+			value = strconv.Quote(value)
+		}
+
+		pairs[index] = key + "=" + value
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// TextSink write entries as lines of key=value pairs
+type TextSink struct {
+	mutex sync.Mutex
+	out   io.Writer
+}
+
+// NewTextSink is a constructor for TextSink
+func NewTextSink(out io.Writer) *TextSink {
+	return &TextSink{out: out}
+}
+
+// Write is a method for write one line of entry
+func (sink *TextSink) Write(entry Entry) error {
+	line := fmt.Sprintf("time=%v level=%v message=%v",
+		entry.Time.Format(time.RFC3339Nano), entry.Level, strconv.Quote(entry.Message))
+
+	if len(entry.Fields) > 0 {
+		line += " " + FormatFields(entry.Fields)
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	_, err := fmt.Fprintln(sink.out, line)
+
+	return err
+}
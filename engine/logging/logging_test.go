@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type sinkForTest struct {
+	entries []Entry
+	err     error
+}
+
+func (sink *sinkForTest) Write(entry Entry) error {
+	if sink.err != nil {
+		return sink.err
+	}
+
+	sink.entries = append(sink.entries, entry)
+
+	return nil
+}
+
+func TestEntriesAreWrittenWithFields(test *testing.T) {
+	out := bytes.Buffer{}
+	logger := New(Info, NewTextSink(&out))
+
+	eventLog := logger.With(Fields{"event": "Need items by name", "clientID": "client"})
+	eventLog.With(Fields{"products": 2}).Info("Output event found products")
+	eventLog.Debug("Not written")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		test.Fatal(out.String())
+	}
+
+	if !strings.Contains(lines[0], `level=info message="Output event found products" `+
+		`clientID=client event="Need items by name" products=2`) {
+		test.Error(lines[0])
+	}
+
+	logger.SetLevel(Debug)
+	eventLog.Debug("Written")
+
+	if !strings.Contains(out.String(), `message="Written"`) {
+		test.Error(out.String())
+	}
+}
+
+func TestLocalSinkIsUsedWhenRemoteSinkFails(test *testing.T) {
+	local := &sinkForTest{}
+	remote := &sinkForTest{}
+	logger := New(Info, local)
+
+	logger.SetRemote(remote)
+	logger.Error("Sent to broker")
+
+	if len(remote.entries) != 1 || len(local.entries) != 0 {
+		test.Fatal(remote.entries, local.entries)
+	}
+
+	remote.err = errors.New("broker is not available")
+	logger.With(Fields{"productID": "0x1"}).Error("Written locally")
+
+	if len(local.entries) != 1 {
+		test.Fatal(local.entries)
+	}
+
+	fields := local.entries[0].Fields
+	if fields["remoteError"] != "broker is not available" || fields["productID"] != "0x1" {
+		test.Error(fields)
+	}
+}
+
+func TestLevelCanBeParsed(test *testing.T) {
+	level, err := ParseLevel("WARNING")
+	if err != nil || level != Warning {
+		test.Error(level, err)
+	}
+
+	_, err = ParseLevel("verbose")
+	if err != ErrLevelIsNotSupported {
+		test.Error(err)
+	}
+}
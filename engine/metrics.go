@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/hecatoncheir/Broker"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/metrics"
+	"github.com/hecatoncheir/Sproot/engine/tracing"
+)
+
+var (
+	eventsReceived = metrics.NewCounterVec("sproot_events_received_total",
+		"Events received from broker by message.", "message")
+
+	handlerDuration = metrics.NewHistogramVec("sproot_event_handler_duration_seconds",
+		"Duration of handlers of events by message.", metrics.DefaultBuckets, "message")
+
+	productsUpdated = metrics.NewCounterVec("sproot_products_updated_total",
+		"Products of parsed events which are created or matched with products in storage.", "result")
+
+	pricesInserted = metrics.NewCounterVec("sproot_prices_inserted_total",
+		"Prices of parsed products inserted to storage.")
+
+	pricesDeduplicated = metrics.NewCounterVec("sproot_prices_deduplicated_total",
+		"Parsed prices with the same value as the latest price which are not inserted to storage.")
+
+	searchDuration = metrics.NewHistogramVec("sproot_search_duration_seconds",
+		"Duration of search of products by name.", metrics.DefaultBuckets)
+
+	searchResults = metrics.NewHistogramVec("sproot_search_results",
+		"Count of products found by name for page.", []float64{0, 1, 5, 10, 20, 50, 100})
+
+	jobRuns = metrics.NewCounterVec("sproot_job_runs_total",
+		"Runs of scheduled jobs by job and result.", "job", "result")
+
+	jobDuration = metrics.NewHistogramVec("sproot_job_duration_seconds",
+		"Duration of runs of scheduled jobs by job.", metrics.DefaultBuckets, "job")
+
+	publishFailures = metrics.NewCounterVec("sproot_broker_publish_failures_total",
+		"Events which can not be written to broker by message.", "message")
+)
+
+// publish write event with traceparent of context to broker and count failures
+func publish(ctx context.Context, bro *broker.Broker, event broker.EventData) {
+	ctx, span := tracing.Start(ctx, "publish "+event.Message)
+	span.SetAttribute("event", event.Message)
+	defer span.Finish()
+
+	event.Data = tracing.Inject(ctx, event.Data)
+
+	err := bro.Write(event)
+	if err != nil {
+		publishFailures.Inc(event.Message)
+		span.SetError(err)
+		logOf(ctx, logging.Fields{"event": event.Message, "clientID": event.ClientID}).Error(err.Error())
+	}
+}
@@ -0,0 +1,301 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hecatoncheir/Sproot/engine/logging"
+)
+
+// DefaultBuckets are upper bounds of histograms of durations in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector is a metric which writes itself in text format of Prometheus
+type collector interface {
+	name() string
+	write(out *bytes.Buffer)
+}
+
+// Registry is a set of metrics exposed together
+type Registry struct {
+	mutex      sync.Mutex
+	collectors []collector
+}
+
+// Default is a registry of all metrics of Sproot
+var Default = &Registry{}
+
+func (registry *Registry) register(metric collector) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, registered := range registry.collectors {
+		if registered.name() == metric.name() {
+			panic(fmt.Sprintf("metric %v is registered twice", metric.name()))
+		}
+	}
+
+	registry.collectors = append(registry.collectors, metric)
+}
+
+// WriteTo is a method for write all metrics of registry in text format of Prometheus
+func (registry *Registry) WriteTo(writer io.Writer) (int64, error) {
+	registry.mutex.Lock()
+	collectors := append([]collector{}, registry.collectors...)
+	registry.mutex.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+
+	out := bytes.Buffer{}
+	for _, metric := range collectors {
+		metric.write(&out)
+	}
+
+	return out.WriteTo(writer)
+}
+
+// Handler is a http handler of /metrics endpoint for registry
+func (registry *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		_, err := registry.WriteTo(response)
+		if err != nil {
+			logging.Default.With(logging.Fields{"path": request.URL.Path}).Error(err.Error())
+		}
+	})
+}
+
+// Handler is a http handler of /metrics endpoint for Default registry
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// family is a common part of metrics with labels
+type family struct {
+	mutex      sync.Mutex
+	metricName string
+	help       string
+	kind       string
+	labels     []string
+}
+
+func (family *family) name() string {
+	return family.metricName
+}
+
+func (family *family) key(labelValues []string) string {
+	if len(labelValues) != len(family.labels) {
+		panic(fmt.Sprintf("metric %v has %v labels, got %v values", family.metricName, len(family.labels), len(labelValues)))
+	}
+
+	return strings.Join(labelValues, "\xff")
+}
+
+func (family *family) writeHeader(out *bytes.Buffer) {
+	fmt.Fprintf(out, "# HELP %v %v\n# TYPE %v %v\n", family.metricName, family.help, family.metricName, family.kind)
+}
+
+// labelsOf format labels of one series, extra label is used for le of histograms
+func (family *family) labelsOf(labelValues []string, extra ...string) string {
+	pairs := make([]string, 0, len(labelValues)+1)
+	for index, value := range labelValues {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", family.labels[index], strconv.Quote(value)))
+	}
+
+	if len(extra) == 2 {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", extra[0], strconv.Quote(extra[1])))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// CounterVec is a counter with labels
+type CounterVec struct {
+	family
+	values map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec is a constructor for counter registered in Default registry
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	counter := &CounterVec{
+		family: family{metricName: name, help: help, kind: "counter", labels: labels},
+		values: map[string]*counterSeries{}}
+
+	Default.register(counter)
+
+	return counter
+}
+
+// Add is a method for increase counter of series by value
+func (counter *CounterVec) Add(value float64, labelValues ...string) {
+	key := counter.key(labelValues)
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	series, ok := counter.values[key]
+	if !ok {
+		series = &counterSeries{labelValues: append([]string{}, labelValues...)}
+		counter.values[key] = series
+	}
+
+	series.value += value
+}
+
+// Inc is a method for increase counter of series by one
+func (counter *CounterVec) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+// Value is a method for get current value of series
+func (counter *CounterVec) Value(labelValues ...string) float64 {
+	key := counter.key(labelValues)
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	series, ok := counter.values[key]
+	if !ok {
+		return 0
+	}
+
+	return series.value
+}
+
+func (counter *CounterVec) write(out *bytes.Buffer) {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	counter.writeHeader(out)
+	for _, key := range sortedKeys(counter.values) {
+		series := counter.values[key]
+		fmt.Fprintf(out, "%v%v %v\n", counter.metricName, counter.labelsOf(series.labelValues), formatValue(series.value))
+	}
+}
+
+// HistogramVec is a histogram with labels
+type HistogramVec struct {
+	family
+	buckets []float64
+	values  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec is a constructor for histogram registered in Default registry
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	histogram := &HistogramVec{
+		family:  family{metricName: name, help: help, kind: "histogram", labels: labels},
+		buckets: append(append([]float64{}, buckets...), math.Inf(1)),
+		values:  map[string]*histogramSeries{}}
+
+	sort.Float64s(histogram.buckets)
+	Default.register(histogram)
+
+	return histogram
+}
+
+// Observe is a method for add value to series
+func (histogram *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := histogram.key(labelValues)
+
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	series, ok := histogram.values[key]
+	if !ok {
+		series = &histogramSeries{labelValues: append([]string{}, labelValues...), counts: make([]uint64, len(histogram.buckets))}
+		histogram.values[key] = series
+	}
+
+	for index, upperBound := range histogram.buckets {
+		if value <= upperBound {
+			series.counts[index]++
+		}
+	}
+
+	series.count++
+	series.sum += value
+}
+
+// Count is a method for get count of observed values of series
+func (histogram *HistogramVec) Count(labelValues ...string) uint64 {
+	key := histogram.key(labelValues)
+
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	series, ok := histogram.values[key]
+	if !ok {
+		return 0
+	}
+
+	return series.count
+}
+
+func (histogram *HistogramVec) write(out *bytes.Buffer) {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	histogram.writeHeader(out)
+	for _, key := range sortedKeys(histogram.values) {
+		series := histogram.values[key]
+		for index, upperBound := range histogram.buckets {
+			fmt.Fprintf(out, "%v_bucket%v %v\n", histogram.metricName,
+				histogram.labelsOf(series.labelValues, "le", formatValue(upperBound)), series.counts[index])
+		}
+
+		labels := histogram.labelsOf(series.labelValues)
+		fmt.Fprintf(out, "%v_sum%v %v\n", histogram.metricName, labels, formatValue(series.sum))
+		fmt.Fprintf(out, "%v_count%v %v\n", histogram.metricName, labels, series.count)
+	}
+}
+
+func sortedKeys(values interface{}) []string {
+	var keys []string
+
+	switch series := values.(type) {
+	case map[string]*counterSeries:
+		for key := range series {
+			keys = append(keys, key)
+		}
+	case map[string]*histogramSeries:
+		for key := range series {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCounterIsWrittenInTextFormat(test *testing.T) {
+	counter := NewCounterVec("test_events_total", "Events of test.", "message")
+	counter.Inc("Need items by name")
+	counter.Add(2, "Need items by name")
+	counter.Inc(`Quoted "message"`)
+
+	if counter.Value("Need items by name") != 3 {
+		test.Error(counter.Value("Need items by name"))
+	}
+
+	out := bytes.Buffer{}
+	_, err := Default.WriteTo(&out)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# HELP test_events_total Events of test.",
+		"# TYPE test_events_total counter",
+		`test_events_total{message="Need items by name"} 3`,
+		`test_events_total{message="Quoted \"message\""} 1`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			test.Errorf("Line %v is not written:\n%v", line, out.String())
+		}
+	}
+}
+
+func TestHistogramIsWrittenWithCumulativeBuckets(test *testing.T) {
+	histogram := NewHistogramVec("test_duration_seconds", "Duration of test.", []float64{0.1, 1})
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+	histogram.Observe(5)
+
+	if histogram.Count() != 3 {
+		test.Error(histogram.Count())
+	}
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	for _, line := range []string{
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{le="0.1"} 1`,
+		`test_duration_seconds_bucket{le="1"} 2`,
+		`test_duration_seconds_bucket{le="+Inf"} 3`,
+		"test_duration_seconds_sum 5.55",
+		"test_duration_seconds_count 3",
+	} {
+		if !strings.Contains(recorder.Body.String(), line+"\n") {
+			test.Errorf("Line %v is not written:\n%v", line, recorder.Body.String())
+		}
+	}
+}
+
+func TestMetricCanNotBeRegisteredTwice(test *testing.T) {
+	NewCounterVec("test_twice_total", "Registered twice.")
+
+	defer func() {
+		if recover() == nil {
+			test.Error("Registration of the same metric must panic")
+		}
+	}()
+
+	NewCounterVec("test_twice_total", "Registered twice.")
+}
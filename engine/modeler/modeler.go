@@ -1,93 +1,387 @@
 package modeler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/hecatoncheir/Sproot/engine/storage"
-	"log"
 )
 
+// Modeler is a object for prepare data in storage by seed
 type Modeler struct {
 	Storage *storage.Storage
 }
 
+// New is a constructor for Modeler
 func New(storage *storage.Storage) *Modeler {
 	return &Modeler{Storage: storage}
 }
 
-func (modeler *Modeler) SetUpAll() error {
-	companyForCreate := storage.Company{
-		IRI:  "http://www.mvideo.ru/",
-		Name: "М.Видео"}
+// Change is a record about one operation of modeler in storage
+type Change struct {
+	Action string
+	Kind   string
+	Name   string
+}
 
-	createdCompany, err := modeler.Storage.Companies.CreateCompany(companyForCreate, "ru")
-	if err != nil && err != storage.ErrCompanyAlreadyExist {
-		log.Fatal(err)
-		return err
-	}
+// String is a method for print change of storage in log
+func (change Change) String() string {
+	return fmt.Sprintf("%v %v: %v", change.Action, change.Kind, change.Name)
+}
 
-	categoryForCreate := storage.Category{
-		Name: "Смартфоны"}
+// Report is a diff of storage made by modeler
+type Report struct {
+	Changes []Change
+}
 
-	createdCategory, err := modeler.Storage.Categories.CreateCategory(categoryForCreate, "ru")
-	if err != nil && err != storage.ErrCategoryAlreadyExist {
-		log.Fatal(err)
-		return err
+func (report *Report) add(action, kind, name string) {
+	report.Changes = append(report.Changes, Change{Action: action, Kind: kind, Name: name})
+}
+
+// String is a method for print all changes of storage in log
+func (report Report) String() string {
+	if len(report.Changes) == 0 {
+		return "nothing changed"
 	}
 
-	err = modeler.Storage.Categories.AddCompanyToCategory(createdCategory.ID, createdCompany.ID)
-	if err != nil {
-		log.Fatal(err)
-		return err
+	lines := make([]string, len(report.Changes))
+	for index, change := range report.Changes {
+		lines[index] = change.String()
 	}
 
-	createdInstruction, err := modeler.Storage.Instructions.CreateInstructionForCompany(createdCompany.ID, "ru")
+	return strings.Join(lines, "\n")
+}
+
+// SetUpFromFile read seed file and apply it to storage
+func (modeler *Modeler) SetUpFromFile(ctx context.Context, path string) (Report, error) {
+	seed, err := ReadSeedFile(path)
 	if err != nil {
-		log.Println(err)
-		return err
+		return Report{}, err
 	}
 
-	err = modeler.Storage.Instructions.AddCategoryToInstruction(createdInstruction.ID, createdCategory.ID)
+	return modeler.Apply(ctx, seed)
+}
+
+// seedApplying is a state of one Apply call with nodes of storage found by names
+type seedApplying struct {
+	storage  *storage.Storage
+	language string
+	report   Report
+
+	cities     map[string]storage.City
+	categories map[string]storage.Category
+	companies  map[string]storage.Company
+}
+
+// Apply make all missing nodes and edges of seed in storage, update changed nodes and leave the rest.
+// Apply can be called many times with the same seed. Storage is called with ctx, so its deadline,
+// cancellation and trace are kept.
+func (modeler *Modeler) Apply(ctx context.Context, seed Seed) (Report, error) {
+	err := seed.Validate()
 	if err != nil {
-		log.Println(err)
-		return err
+		return Report{}, err
+	}
+
+	applying := seedApplying{
+		storage:    modeler.Storage,
+		language:   seed.Language,
+		cities:     map[string]storage.City{},
+		categories: map[string]storage.Category{},
+		companies:  map[string]storage.Company{}}
+
+	for _, city := range seed.Cities {
+		_, err := applying.city(ctx, city.Name)
+		if err != nil {
+			return applying.report, err
+		}
+	}
+
+	for _, category := range seed.Categories {
+		_, err := applying.category(ctx, category.Name)
+		if err != nil {
+			return applying.report, err
+		}
+	}
+
+	for _, company := range seed.Companies {
+		err := applying.company(ctx, company)
+		if err != nil {
+			return applying.report, err
+		}
+	}
+
+	for _, instruction := range seed.Instructions {
+		err := applying.instruction(ctx, instruction)
+		if err != nil {
+			return applying.report, err
+		}
 	}
 
-	cityForCreate := storage.City{
-		Name: "Москва"}
+	return applying.report, nil
+}
+
+func (applying *seedApplying) city(ctx context.Context, name string) (storage.City, error) {
+	if city, ok := applying.cities[name]; ok {
+		return city, nil
+	}
+
+	city, err := applying.storage.Cities.CreateCity(ctx, storage.City{Name: name}, applying.language)
+	if err != nil && !errors.Is(err, storage.ErrCityAlreadyExist) {
+		return city, err
+	}
+
+	if err == nil {
+		applying.report.add("created", "city", name)
+	}
+
+	applying.cities[name] = city
 
-	createdCity, err := modeler.Storage.Cities.CreateCity(cityForCreate, "ru")
-	if err != nil && err != storage.ErrCityAlreadyExist {
-		log.Fatal(err)
+	return city, nil
+}
+
+func (applying *seedApplying) category(ctx context.Context, name string) (storage.Category, error) {
+	if category, ok := applying.categories[name]; ok {
+		return category, nil
+	}
+
+	category, err := applying.storage.Categories.CreateCategory(ctx, storage.Category{Name: name}, applying.language)
+	if err != nil && !errors.Is(err, storage.ErrCategoryAlreadyExist) {
+		return category, err
+	}
+
+	if err == nil {
+		applying.report.add("created", "category", name)
+	}
+
+	applying.categories[name] = category
+
+	return category, nil
+}
+
+func (applying *seedApplying) company(ctx context.Context, seedCompany SeedCompany) error {
+	companyForCreate := storage.Company{Name: seedCompany.Name, IRI: seedCompany.IRI}
+
+	company, err := applying.storage.Companies.CreateCompany(ctx, companyForCreate, applying.language)
+	if err != nil && !errors.Is(err, storage.ErrCompanyAlreadyExist) {
 		return err
 	}
 
-	err = modeler.Storage.Instructions.AddCityToInstruction(createdInstruction.ID, createdCity.ID)
-	if err != nil {
-		log.Println(err)
+	if err == nil {
+		applying.report.add("created", "company", seedCompany.Name)
+	}
+
+	if errors.Is(err, storage.ErrCompanyAlreadyExist) && companyChanged(company, seedCompany) {
+		companyForUpdate := storage.Company{ID: company.ID, IRI: company.IRI, IsActive: company.IsActive}
+		if seedCompany.IRI != "" {
+			companyForUpdate.IRI = seedCompany.IRI
+		}
+
+		if seedCompany.Active != nil {
+			companyForUpdate.IsActive = *seedCompany.Active
+		}
+
+		_, err = applying.storage.Companies.UpdateCompany(ctx, companyForUpdate)
+		if err != nil {
+			return err
+		}
+
+		company.IRI, company.IsActive = companyForUpdate.IRI, companyForUpdate.IsActive
+		applying.report.add("updated", "company", seedCompany.Name)
+	}
+
+	applying.companies[seedCompany.Name] = company
+
+	for _, categoryName := range seedCompany.Categories {
+		category, err := applying.category(ctx, categoryName)
+		if err != nil {
+			return err
+		}
+
+		if hasCategory(company.Categories, category.ID) {
+			continue
+		}
+
+		err = applying.storage.Categories.AddCompanyToCategory(ctx, category.ID, company.ID)
+		if err != nil {
+			return err
+		}
+
+		applying.report.add("linked", "category of company",
+			fmt.Sprintf("%v - %v", seedCompany.Name, categoryName))
+	}
+
+	return nil
+}
+
+func (applying *seedApplying) instruction(ctx context.Context, seedInstruction SeedInstruction) error {
+	company := applying.companies[seedInstruction.Company]
+
+	language := seedInstruction.Language
+	if language == "" {
+		language = applying.language
+	}
+
+	name := fmt.Sprintf("%v (%v)", seedInstruction.Company, language)
+
+	instructions, err := applying.storage.Instructions.ReadInstructionsOfCompany(ctx, company.ID, applying.language)
+	if err != nil && !errors.Is(err, storage.ErrInstructionsForCompanyDoesNotExist) {
 		return err
 	}
 
-	pageInstructionForCreate := storage.PageInstruction{
-		Path: "smartfony-i-svyaz/smartfony-205",
-		PageInPaginationSelector:   ".c-pagination > .c-pagination__num",
-		PageParamPath:              "/f/page=",
-		CityParamPath:              "?cityId=",
-		ItemSelector:               ".c-product-tile",
-		PreviewImageOfItemSelector: ".c-product-tile-picture__link .lazy-load-image-holder img",
-		NameOfItemSelector:         ".c-product-tile__description .sel-product-tile-title",
-		LinkOfItemSelector:         ".c-product-tile__description .sel-product-tile-title",
-		PriceOfItemSelector:        ".c-product-tile__checkout-section .c-pdp-price__current"}
+	var instruction storage.Instruction
+	for _, instructionOfCompany := range instructions {
+		if instructionOfCompany.Language == language {
+			instruction = instructionOfCompany
+			break
+		}
+	}
+
+	if instruction.ID == "" {
+		instruction, err = applying.storage.Instructions.CreateInstructionForCompany(ctx, company.ID, language)
+		if err != nil {
+			return err
+		}
 
-	createdPageInstruction, err := modeler.Storage.Instructions.CreatePageInstruction(pageInstructionForCreate)
+		applying.report.add("created", "instruction", name)
+	}
+
+	for _, categoryName := range seedInstruction.Categories {
+		category, err := applying.category(ctx, categoryName)
+		if err != nil {
+			return err
+		}
+
+		if hasCategory(instruction.Categories, category.ID) {
+			continue
+		}
+
+		err = applying.storage.Instructions.AddCategoryToInstruction(ctx, instruction.ID, category.ID)
+		if err != nil {
+			return err
+		}
+
+		applying.report.add("linked", "category of instruction", fmt.Sprintf("%v - %v", name, categoryName))
+	}
+
+	for _, cityName := range seedInstruction.Cities {
+		city, err := applying.city(ctx, cityName)
+		if err != nil {
+			return err
+		}
+
+		if hasCity(instruction.Cities, city.ID) {
+			continue
+		}
+
+		err = applying.storage.Instructions.AddCityToInstruction(ctx, instruction.ID, city.ID)
+		if err != nil {
+			return err
+		}
+
+		applying.report.add("linked", "city of instruction", fmt.Sprintf("%v - %v", name, cityName))
+	}
+
+	for _, seedPage := range seedInstruction.Pages {
+		err := applying.pageInstruction(ctx, instruction, seedPage, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (applying *seedApplying) pageInstruction(ctx context.Context, instruction storage.Instruction, seedPage SeedPageInstruction, instructionName string) error {
+	name := fmt.Sprintf("%v - %v", instructionName, seedPage.Path)
+	page := seedPage.PageInstruction()
+
+	for _, existPage := range instruction.PagesInstruction {
+		if existPage.Path != page.Path {
+			continue
+		}
+
+		if !pageInstructionChanged(existPage, page) {
+			return nil
+		}
+
+		page.ID = existPage.ID
+
+		_, err := applying.storage.Instructions.UpdatePageInstruction(ctx, page)
+		if err != nil {
+			return err
+		}
+
+		applying.report.add("updated", "page instruction", name)
+
+		return nil
+	}
+
+	createdPage, err := applying.storage.Instructions.CreatePageInstruction(ctx, page)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 
-	err = modeler.Storage.Instructions.AddPageInstructionToInstruction(createdInstruction.ID, createdPageInstruction.ID)
+	err = applying.storage.Instructions.AddPageInstructionToInstruction(ctx, instruction.ID, createdPage.ID)
 	if err != nil {
-		log.Println(err)
 		return err
 	}
 
+	applying.report.add("created", "page instruction", name)
+
 	return nil
 }
+
+// companyChanged compare only IRI and activity which are set in seed, so activity of company set by operator is kept
+func companyChanged(exist storage.Company, wanted SeedCompany) bool {
+	if wanted.IRI != "" && exist.IRI != wanted.IRI {
+		return true
+	}
+
+	return wanted.Active != nil && exist.IsActive != *wanted.Active
+}
+
+// pageInstructionChanged compare only filled fields of wanted page instruction,
+// because empty fields are not written by update
+func pageInstructionChanged(exist, wanted storage.PageInstruction) bool {
+	pairs := [][2]string{
+		{exist.PageInPaginationSelector, wanted.PageInPaginationSelector},
+		{exist.PreviewImageOfItemSelector, wanted.PreviewImageOfItemSelector},
+		{exist.PageParamPath, wanted.PageParamPath},
+		{exist.CityParamPath, wanted.CityParamPath},
+		{exist.ItemSelector, wanted.ItemSelector},
+		{exist.NameOfItemSelector, wanted.NameOfItemSelector},
+		{exist.LinkOfItemSelector, wanted.LinkOfItemSelector},
+		{exist.CityInCookieKey, wanted.CityInCookieKey},
+		{exist.CityIDForCookie, wanted.CityIDForCookie},
+		{exist.PriceOfItemSelector, wanted.PriceOfItemSelector}}
+
+	for _, pair := range pairs {
+		if pair[1] != "" && pair[0] != pair[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasCategory(categories []storage.Category, categoryID string) bool {
+	for _, category := range categories {
+		if category.ID == categoryID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasCity(cities []storage.City, cityID string) bool {
+	for _, city := range cities {
+		if city.ID == cityID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,7 +1,149 @@
 package modeler
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/hecatoncheir/Configuration"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
 
 func TestModelerCanSetLanguageModel(test *testing.T) {}
 func TestModelerCanSetCityModel(test *testing.T)     {}
 func TestModelerCanSetCompanyModel(test *testing.T)  {}
+
+func TestPageInstructionChangedComparesOnlyFilledFields(test *testing.T) {
+	exist := storage.PageInstruction{
+		Path:          "/test/",
+		ItemSelector:  ".product-tile",
+		PageParamPath: "/f/page="}
+
+	if pageInstructionChanged(exist, storage.PageInstruction{Path: "/test/", ItemSelector: ".product-tile"}) {
+		test.Fail()
+	}
+
+	if !pageInstructionChanged(exist, storage.PageInstruction{Path: "/test/", ItemSelector: ".c-product-tile"}) {
+		test.Fail()
+	}
+}
+
+func TestCompanyChangedKeepsActivityWhichIsNotSetInSeed(test *testing.T) {
+	exist := storage.Company{Name: "Test company", IRI: "http://test.com/", IsActive: false}
+
+	if companyChanged(exist, SeedCompany{Name: "Test company", IRI: "http://test.com/"}) {
+		test.Fail()
+	}
+
+	if companyChanged(exist, SeedCompany{Name: "Test company"}) {
+		test.Fail()
+	}
+
+	active := true
+	if !companyChanged(exist, SeedCompany{Name: "Test company", Active: &active}) {
+		test.Fail()
+	}
+
+	if !companyChanged(exist, SeedCompany{Name: "Test company", IRI: "http://test.ru/"}) {
+		test.Fail()
+	}
+}
+
+func TestReportCanBePrinted(test *testing.T) {
+	report := Report{}
+	if report.String() != "nothing changed" {
+		test.Fail()
+	}
+
+	report.add("created", "city", "Москва")
+	report.add("updated", "company", "М.Видео")
+
+	if report.String() != "created city: Москва\nupdated company: М.Видео" {
+		test.Error(report.String())
+	}
+}
+
+func TestIntegrationSeedCanBeAppliedManyTimes(test *testing.T) {
+	config := configuration.New()
+	store := storage.New(config.Development.Database.Host, config.Development.Database.Port)
+
+	_, err := store.Migrate(context.Background())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	err = store.SetUp(context.Background())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	seed := Seed{
+		Language:   "en",
+		Cities:     []SeedCity{{Name: "Test city"}},
+		Categories: []SeedCategory{{Name: "Test category"}},
+		Companies: []SeedCompany{{
+			Name:       "Test company",
+			IRI:        "http://test.com/",
+			Categories: []string{"Test category"}}},
+		Instructions: []SeedInstruction{{
+			Company:    "Test company",
+			Categories: []string{"Test category"},
+			Cities:     []string{"Test city"},
+			Pages: []SeedPageInstruction{{
+				Path:         "/test/",
+				ItemSelector: ".product-tile"}}}}}
+
+	modeler := New(store)
+
+	report, err := modeler.Apply(context.Background(), seed)
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		companies, _ := store.Companies.ReadCompaniesByName(context.Background(), "Test company", "en")
+		for _, company := range companies {
+			instructions, _ := store.Instructions.ReadInstructionsOfCompany(context.Background(), company.ID, "en")
+			for _, instruction := range instructions {
+				for _, page := range instruction.PagesInstruction {
+					store.Instructions.DeletePageInstruction(context.Background(), page)
+				}
+				store.Instructions.DeleteInstruction(context.Background(), instruction)
+			}
+			store.Companies.DeleteCompany(context.Background(), company)
+		}
+
+		categories, _ := store.Categories.ReadCategoriesByName(context.Background(), "Test category", "en")
+		for _, category := range categories {
+			store.Categories.DeleteCategory(context.Background(), category)
+		}
+
+		cities, _ := store.Cities.ReadCitiesByName(context.Background(), "Test city", "en")
+		for _, city := range cities {
+			store.Cities.DeleteCity(context.Background(), city)
+		}
+	}()
+
+	if len(report.Changes) == 0 {
+		test.Fail()
+	}
+
+	report, err = modeler.Apply(context.Background(), seed)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if len(report.Changes) != 0 {
+		test.Error(report)
+	}
+
+	seed.Instructions[0].Pages[0].ItemSelector = ".c-product-tile"
+
+	report, err = modeler.Apply(context.Background(), seed)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if len(report.Changes) != 1 || report.Changes[0].Action != "updated" {
+		test.Error(report)
+	}
+}
@@ -0,0 +1,169 @@
+package modeler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hecatoncheir/Sproot/engine/storage"
+	"gopkg.in/yaml.v2"
+)
+
+// Seed is a description of data which must be in storage before engine starts
+type Seed struct {
+	Language     string            `yaml:"language" json:"language"`
+	Cities       []SeedCity        `yaml:"cities" json:"cities"`
+	Categories   []SeedCategory    `yaml:"categories" json:"categories"`
+	Companies    []SeedCompany     `yaml:"companies" json:"companies"`
+	Instructions []SeedInstruction `yaml:"instructions" json:"instructions"`
+}
+
+// SeedCity is a city of seed, city is found by name
+type SeedCity struct {
+	Name string `yaml:"name" json:"name"`
+}
+
+// SeedCategory is a category of seed, category is found by name
+type SeedCategory struct {
+	Name string `yaml:"name" json:"name"`
+}
+
+// SeedCompany is a company of seed, company is found by name.
+// Categories is a list of names of categories of company.
+// Activity of company in storage is changed only when Active is set.
+type SeedCompany struct {
+	Name       string   `yaml:"name" json:"name"`
+	IRI        string   `yaml:"iri" json:"iri"`
+	Active     *bool    `yaml:"active" json:"active"`
+	Categories []string `yaml:"categories" json:"categories"`
+}
+
+// SeedInstruction is an instruction of company for parser, instruction is found by company name and language.
+// Categories and Cities are lists of names.
+type SeedInstruction struct {
+	Company    string                `yaml:"company" json:"company"`
+	Language   string                `yaml:"language" json:"language"`
+	Categories []string              `yaml:"categories" json:"categories"`
+	Cities     []string              `yaml:"cities" json:"cities"`
+	Pages      []SeedPageInstruction `yaml:"pages" json:"pages"`
+}
+
+// SeedPageInstruction is a page instruction of seed, page instruction is found by path inside of instruction
+type SeedPageInstruction struct {
+	Path                       string `yaml:"path" json:"path"`
+	PageInPaginationSelector   string `yaml:"pageInPaginationSelector" json:"pageInPaginationSelector"`
+	PreviewImageOfItemSelector string `yaml:"previewImageOfItemSelector" json:"previewImageOfItemSelector"`
+	PageParamPath              string `yaml:"pageParamPath" json:"pageParamPath"`
+	CityParamPath              string `yaml:"cityParamPath" json:"cityParamPath"`
+	ItemSelector               string `yaml:"itemSelector" json:"itemSelector"`
+	NameOfItemSelector         string `yaml:"nameOfItemSelector" json:"nameOfItemSelector"`
+	LinkOfItemSelector         string `yaml:"linkOfItemSelector" json:"linkOfItemSelector"`
+	CityInCookieKey            string `yaml:"cityInCookieKey" json:"cityInCookieKey"`
+	CityIDForCookie            string `yaml:"cityIdForCookie" json:"cityIdForCookie"`
+	PriceOfItemSelector        string `yaml:"priceOfItemSelector" json:"priceOfItemSelector"`
+}
+
+// PageInstruction converts page instruction of seed to page instruction of storage
+func (page SeedPageInstruction) PageInstruction() storage.PageInstruction {
+	return storage.PageInstruction{
+		Path:                       page.Path,
+		PageInPaginationSelector:   page.PageInPaginationSelector,
+		PreviewImageOfItemSelector: page.PreviewImageOfItemSelector,
+		PageParamPath:              page.PageParamPath,
+		CityParamPath:              page.CityParamPath,
+		ItemSelector:               page.ItemSelector,
+		NameOfItemSelector:         page.NameOfItemSelector,
+		LinkOfItemSelector:         page.LinkOfItemSelector,
+		CityInCookieKey:            page.CityInCookieKey,
+		CityIDForCookie:            page.CityIDForCookie,
+		PriceOfItemSelector:        page.PriceOfItemSelector,
+		IsActive:                   true}
+}
+
+var (
+	// ErrSeedCanNotBeRead means that the seed file can't be read
+	ErrSeedCanNotBeRead = errors.New("seed can not be read")
+
+	// ErrSeedWithoutLanguage means that the seed has no default language
+	ErrSeedWithoutLanguage = errors.New("seed can not be without language")
+
+	// ErrSeedCompanyWithoutName means that a company of seed has no name
+	ErrSeedCompanyWithoutName = errors.New("company of seed can not be without name")
+
+	// ErrSeedInstructionWithoutCompany means that an instruction of seed refers to company which is not in seed
+	ErrSeedInstructionWithoutCompany = errors.New("instruction of seed must refer to company of seed")
+
+	// ErrSeedPageInstructionWithoutPath means that a page instruction of seed has no path
+	ErrSeedPageInstructionWithoutPath = errors.New("page instruction of seed can not be without path")
+)
+
+// ReadSeedFile parse seed from YAML or JSON file, format is selected by extension of file
+func ReadSeedFile(path string) (Seed, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Seed{}, err
+	}
+
+	format := "yaml"
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		format = "json"
+	}
+
+	return ParseSeed(data, format)
+}
+
+// ParseSeed parse seed from data in "yaml" or "json" format and validate it
+func ParseSeed(data []byte, format string) (Seed, error) {
+	seed := Seed{}
+
+	var err error
+	if format == "json" {
+		err = json.Unmarshal(data, &seed)
+	} else {
+		err = yaml.Unmarshal(data, &seed)
+	}
+
+	if err != nil {
+		return seed, fmt.Errorf("%w: %v", ErrSeedCanNotBeRead, err)
+	}
+
+	err = seed.Validate()
+	if err != nil {
+		return seed, err
+	}
+
+	return seed, nil
+}
+
+// Validate checks references between parts of seed
+func (seed *Seed) Validate() error {
+	if seed.Language == "" {
+		return ErrSeedWithoutLanguage
+	}
+
+	companies := map[string]bool{}
+	for _, company := range seed.Companies {
+		if company.Name == "" {
+			return ErrSeedCompanyWithoutName
+		}
+
+		companies[company.Name] = true
+	}
+
+	for _, instruction := range seed.Instructions {
+		if !companies[instruction.Company] {
+			return ErrSeedInstructionWithoutCompany
+		}
+
+		for _, page := range instruction.Pages {
+			if page.Path == "" {
+				return ErrSeedPageInstructionWithoutPath
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package modeler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSeedCanBeParsedFromYAML(test *testing.T) {
+	data := []byte(`
+language: ru
+cities:
+  - name: Москва
+categories:
+  - name: Смартфоны
+companies:
+  - name: М.Видео
+    iri: http://www.mvideo.ru/
+    categories:
+      - Смартфоны
+instructions:
+  - company: М.Видео
+    cities:
+      - Москва
+    pages:
+      - path: smartfony-i-svyaz/smartfony-205
+        itemSelector: .c-product-tile
+        cityIdForCookie: "1"
+`)
+
+	seed, err := ParseSeed(data, "yaml")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if seed.Language != "ru" {
+		test.Fail()
+	}
+
+	if len(seed.Cities) != 1 || seed.Cities[0].Name != "Москва" {
+		test.Fail()
+	}
+
+	if len(seed.Companies) != 1 || seed.Companies[0].IRI != "http://www.mvideo.ru/" {
+		test.Fail()
+	}
+
+	if len(seed.Companies[0].Categories) != 1 || seed.Companies[0].Categories[0] != "Смартфоны" {
+		test.Fail()
+	}
+
+	if len(seed.Instructions) != 1 || len(seed.Instructions[0].Pages) != 1 {
+		test.Fatal()
+	}
+
+	page := seed.Instructions[0].Pages[0].PageInstruction()
+
+	if page.Path != "smartfony-i-svyaz/smartfony-205" {
+		test.Fail()
+	}
+
+	if page.ItemSelector != ".c-product-tile" {
+		test.Fail()
+	}
+
+	if page.CityIDForCookie != "1" {
+		test.Fail()
+	}
+
+	if !page.IsActive {
+		test.Fail()
+	}
+}
+
+func TestSeedCanBeParsedFromJSON(test *testing.T) {
+	data := []byte(`{
+		"language": "en",
+		"companies": [{"name": "Test company", "iri": "http://test.com/"}],
+		"instructions": [{"company": "Test company", "pages": [{"path": "/test/"}]}]
+	}`)
+
+	seed, err := ParseSeed(data, "json")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if seed.Language != "en" {
+		test.Fail()
+	}
+
+	if len(seed.Instructions) != 1 || seed.Instructions[0].Pages[0].Path != "/test/" {
+		test.Fail()
+	}
+}
+
+func TestSeedMustBeValid(test *testing.T) {
+	_, err := ParseSeed([]byte(`companies: [{name: Test company}]`), "yaml")
+	if err != ErrSeedWithoutLanguage {
+		test.Error(err)
+	}
+
+	_, err = ParseSeed([]byte(`{"language": "en", "companies": [{"iri": "http://test.com/"}]}`), "json")
+	if err != ErrSeedCompanyWithoutName {
+		test.Error(err)
+	}
+
+	_, err = ParseSeed([]byte(`
+language: en
+instructions:
+  - company: Test company
+`), "yaml")
+	if err != ErrSeedInstructionWithoutCompany {
+		test.Error(err)
+	}
+
+	_, err = ParseSeed([]byte(`
+language: en
+companies:
+  - name: Test company
+instructions:
+  - company: Test company
+    pages:
+      - itemSelector: .c-product-tile
+`), "yaml")
+	if err != ErrSeedPageInstructionWithoutPath {
+		test.Error(err)
+	}
+
+	_, err = ParseSeed([]byte(`{"language": `), "json")
+	if !errors.Is(err, ErrSeedCanNotBeRead) {
+		test.Error(err)
+	}
+
+	_, err = ParseSeed([]byte("language: en\ncompanies: [name: Test company\n"), "yaml")
+	if !errors.Is(err, ErrSeedCanNotBeRead) || !strings.Contains(err.Error(), "line") {
+		test.Error(err)
+	}
+}
+
+func TestSeedFileOfRepositoryIsValid(test *testing.T) {
+	seed, err := ReadSeedFile("../../seed.yaml")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(seed.Companies) == 0 || len(seed.Instructions) == 0 {
+		test.Fail()
+	}
+}
@@ -0,0 +1,243 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrCurrencyIsNotValid means that code of currency is not three capital letters of ISO 4217
+	ErrCurrencyIsNotValid = errors.New("currency is not valid")
+
+	// ErrAmountIsNotValid means that text is not a decimal number or it has more digits than minor units of currency
+	ErrAmountIsNotValid = errors.New("amount is not valid")
+
+	// ErrRateIsNotValid means that rate of currency is not a positive decimal number
+	ErrRateIsNotValid = errors.New("rate is not valid")
+
+	// ErrRateDoesNotExist means that rate of currency to reference currency is not in table of rates
+	ErrRateDoesNotExist = errors.New("rate does not exist")
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// exponents are counts of digits of minor units of currencies which are not 2
+var exponents = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// ValidateCurrency is a function for check that currency is a code like RUB
+func ValidateCurrency(currency string) error {
+	if !currencyPattern.MatchString(currency) {
+		return fmt.Errorf("%w: code must be like RUB, got %q", ErrCurrencyIsNotValid, currency)
+	}
+
+	return nil
+}
+
+// Exponent is a function for get count of digits of minor units of currency
+func Exponent(currency string) int {
+	exponent, ok := exponents[currency]
+	if !ok {
+		return 2
+	}
+
+	return exponent
+}
+
+// Money is an exact amount of minor units of currency, like kopecks of RUB
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// Parse is a function for read money from decimal text of parser like "1 299,90" or "1,299.90".
+// The last of dot and comma is a decimal separator when text has both of them.
+func Parse(text, currency string) (Money, error) {
+	err := ValidateCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	amount, err := parseDecimal(text, Exponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+
+	if amount.Sign() < 0 || !amount.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q must be positive and not too big", ErrAmountIsNotValid, text)
+	}
+
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+// FromFloat is a function for get money from value of price saved before minor units, value is rounded
+func FromFloat(value float64, currency string) Money {
+	scale := math.Pow10(Exponent(currency))
+	return Money{Amount: int64(math.Round(value * scale)), Currency: currency}
+}
+
+// parseDecimal return decimal text multiplied by 10^exponent, digits after exponent must be zeros
+func parseDecimal(text string, exponent int) (*big.Int, error) {
+	cleaned := strings.Map(func(symbol rune) rune {
+		if unicode.IsSpace(symbol) || symbol == '\'' {
+			return -1
+		}
+
+		return symbol
+	}, text)
+
+	separator := strings.LastIndexAny(cleaned, ".,")
+	whole, fraction := cleaned, ""
+	if separator >= 0 && strings.Count(cleaned, cleaned[separator:separator+1]) > 1 {
+		whole = strings.Replace(cleaned, cleaned[separator:separator+1], "", -1)
+	} else if separator >= 0 {
+		whole, fraction = cleaned[:separator], cleaned[separator+1:]
+
+		grouping := ","
+		if cleaned[separator] == ',' {
+			grouping = "."
+		}
+
+		whole = strings.Replace(whole, grouping, "", -1)
+	}
+
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("%w: %q is not a decimal number", ErrAmountIsNotValid, text)
+	}
+
+	if len(fraction) > exponent {
+		if strings.Trim(fraction[exponent:], "0") != "" {
+			return nil, fmt.Errorf("%w: %q has more than %v digits after separator", ErrAmountIsNotValid, text, exponent)
+		}
+
+		fraction = fraction[:exponent]
+	}
+
+	digits := whole + fraction + strings.Repeat("0", exponent-len(fraction))
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a decimal number", ErrAmountIsNotValid, text)
+	}
+
+	return amount, nil
+}
+
+func isDigits(text string) bool {
+	for _, symbol := range text {
+		if symbol < '0' || symbol > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Decimal is a method for get amount of major units of currency, like 1299.90
+func (money Money) Decimal() string {
+	exponent := Exponent(money.Currency)
+	digits := strconv.FormatInt(money.Amount, 10)
+	if exponent == 0 {
+		return digits
+	}
+
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	return digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// Float is a method for get amount of major units of currency for indexes and aggregates of database
+func (money Money) Float() float64 {
+	value, _ := strconv.ParseFloat(money.Decimal(), 64)
+	return value
+}
+
+func (money Money) String() string {
+	return money.Decimal() + " " + money.Currency
+}
+
+// ParseRate is a function for read positive decimal rate like 92.5
+func ParseRate(text string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(text))
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q must be a positive decimal number", ErrRateIsNotValid, text)
+	}
+
+	return rate, nil
+}
+
+// Rates is a table of prices of one major unit of currencies in major units of reference currency
+type Rates struct {
+	Reference   string
+	ToReference map[string]*big.Rat
+}
+
+// rateOf return rate of currency to reference currency, rate of reference currency is 1
+func (rates Rates) rateOf(currency string) (*big.Rat, error) {
+	if currency == rates.Reference {
+		return big.NewRat(1, 1), nil
+	}
+
+	rate, ok := rates.ToReference[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v to %v", ErrRateDoesNotExist, currency, rates.Reference)
+	}
+
+	return rate, nil
+}
+
+// Convert is a method for get money in other currency by rates to reference currency.
+// Result is rounded half away from zero to minor units of currency.
+func (rates Rates) Convert(money Money, currency string) (Money, error) {
+	if money.Currency == currency {
+		return money, nil
+	}
+
+	err := ValidateCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	from, err := rates.rateOf(money.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	to, err := rates.rateOf(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	amount := new(big.Rat).SetInt64(money.Amount)
+	amount.Mul(amount, from)
+	amount.Quo(amount, to)
+	amount.Mul(amount, new(big.Rat).SetFrac(pow10(Exponent(currency)), pow10(Exponent(money.Currency))))
+
+	return Money{Amount: round(amount), Currency: currency}, nil
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// round return rational number rounded half away from zero
+func round(value *big.Rat) int64 {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+
+	if remainder.Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+
+	return quotient.Int64()
+}
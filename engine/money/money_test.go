@@ -0,0 +1,97 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestMoneyIsParsedFromTextOfParser(test *testing.T) {
+	cases := []struct {
+		text     string
+		currency string
+		amount   int64
+	}{
+		{"1299", "RUB", 129900},
+		{"1 299,90", "RUB", 129990},
+		{"1 299.9", "RUB", 129990},
+		{"1,299.90", "USD", 129990},
+		{"1.299,90", "EUR", 129990},
+		{"1.299.000", "RUB", 129900000},
+		{"1299.900", "KZT", 129990},
+		{"1299", "JPY", 1299},
+		{"1.250", "KWD", 1250},
+	}
+
+	for _, testCase := range cases {
+		money, err := Parse(testCase.text, testCase.currency)
+		if err != nil || money.Amount != testCase.amount || money.Currency != testCase.currency {
+			test.Error(testCase.text, money, err)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "12.345", "-10", "1,2.3.4"} {
+		_, err := Parse(text, "RUB")
+		if !errors.Is(err, ErrAmountIsNotValid) {
+			test.Error(text, err)
+		}
+	}
+
+	_, err := Parse("10", "rub")
+	if !errors.Is(err, ErrCurrencyIsNotValid) {
+		test.Error(err)
+	}
+}
+
+func TestMoneyIsFormattedByMinorUnitsOfCurrency(test *testing.T) {
+	if (Money{Amount: 129990, Currency: "RUB"}).String() != "1299.90 RUB" {
+		test.Error(Money{Amount: 129990, Currency: "RUB"})
+	}
+
+	if (Money{Amount: 5, Currency: "USD"}).Decimal() != "0.05" || (Money{Amount: 1299, Currency: "JPY"}).Decimal() != "1299" {
+		test.Error("Minor units must be written after separator")
+	}
+
+	if (Money{Amount: 129990, Currency: "RUB"}).Float() != 1299.9 || FromFloat(1299.9, "RUB").Amount != 129990 {
+		test.Error("Float must be the same value as decimal")
+	}
+}
+
+func TestMoneyIsConvertedByRatesToReferenceCurrency(test *testing.T) {
+	rates := Rates{Reference: "RUB", ToReference: map[string]*big.Rat{
+		"USD": big.NewRat(925, 10),
+		"EUR": big.NewRat(100, 1),
+		"JPY": big.NewRat(6, 10)}}
+
+	cases := []struct {
+		money    Money
+		currency string
+		amount   int64
+	}{
+		{Money{Amount: 1000, Currency: "USD"}, "RUB", 92500},
+		{Money{Amount: 92500, Currency: "RUB"}, "USD", 1000},
+		{Money{Amount: 1000, Currency: "EUR"}, "USD", 1081},
+		{Money{Amount: 100, Currency: "JPY"}, "RUB", 6000},
+		{Money{Amount: 6050, Currency: "RUB"}, "JPY", 101},
+		{Money{Amount: 1000, Currency: "USD"}, "USD", 1000},
+	}
+
+	for _, testCase := range cases {
+		converted, err := rates.Convert(testCase.money, testCase.currency)
+		if err != nil || converted.Amount != testCase.amount || converted.Currency != testCase.currency {
+			test.Error(testCase.money, testCase.currency, converted, err)
+		}
+	}
+
+	_, err := rates.Convert(Money{Amount: 100, Currency: "GBP"}, "RUB")
+	if !errors.Is(err, ErrRateDoesNotExist) {
+		test.Error(err)
+	}
+
+	for _, text := range []string{"0", "-1", "rate"} {
+		_, err = ParseRate(text)
+		if !errors.Is(err, ErrRateIsNotValid) {
+			test.Error(text, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hecatoncheir/Broker"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/sheet"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+// PricesReportRequest is a data of broker event for make flat report of prices
+type PricesReportRequest struct {
+	Language string              `json:"language"`
+	Format   string              `json:"format"`
+	Filter   storage.PriceFilter `json:"filter"`
+}
+
+// PricesReport is a data of broker event with made report of prices.
+// Content of CSV report is a text, content of XLSX report is encoded by base64.
+type PricesReport struct {
+	Format  string `json:"format"`
+	Rows    int    `json:"rows"`
+	Content string `json:"content"`
+}
+
+// PriceRowsHeader is a first row of report of prices
+var PriceRowsHeader = []interface{}{"product", "category", "company", "city", "value", "currency", "datetime", "product IRI"}
+
+// WritePrices is a method for write report of prices matched by filter in format of sheet package.
+// Rows are written as they are read from storage. Count of rows without header is returned.
+func (engine *Engine) WritePrices(ctx context.Context, filter storage.PriceFilter, language, format string, writer io.Writer) (int, error) {
+	rows, err := sheet.NewWriter(format, writer)
+	if err != nil {
+		return 0, err
+	}
+
+	err = rows.WriteRow(PriceRowsHeader...)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = engine.Storage.Prices.ReadPriceRows(ctx, filter, language, func(row storage.PriceRow) error {
+		count++
+		return rows.WriteRow(
+			row.ProductName, row.CategoryName, row.CompanyName, row.CityName, row.Value, row.Currency, row.DateTime, row.ProductIRI)
+	})
+	if err != nil {
+		return count, err
+	}
+
+	return count, rows.Close()
+}
+
+func (engine *Engine) pricesReportHandler(ctx context.Context, data, clientID, APIVersion string) {
+	eventLog := logOf(ctx, logging.Fields{"event": "Need prices report", "clientID": clientID})
+
+	request := PricesReportRequest{Format: sheet.FormatCSV}
+	err := json.Unmarshal([]byte(data), &request)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrRequestIsNotValid, err)
+		eventLog.Error(err.Error())
+		engine.replyWithError(ctx, "Prices report can not be made", err, clientID, APIVersion)
+
+		return
+	}
+
+	eventLog = eventLog.With(logging.Fields{"format": request.Format, "filter": fmt.Sprintf("%+v", request.Filter)})
+	eventLog.Info("Input event of prices report")
+
+	content := bytes.Buffer{}
+	rows, err := engine.WritePrices(storage.WithConsistency(ctx, storage.BestEffort), request.Filter, request.Language, request.Format, &content)
+	if err != nil {
+		eventLog.With(logging.Fields{"kind": KindOf(err)}).Error(err.Error())
+		engine.replyWithError(ctx, "Prices report can not be made", err, clientID, APIVersion)
+
+		return
+	}
+
+	report := PricesReport{Format: request.Format, Rows: rows, Content: content.String()}
+	if request.Format == sheet.FormatXLSX {
+		report.Content = base64.StdEncoding.EncodeToString(content.Bytes())
+	}
+
+	encodedReport, err := json.Marshal(report)
+	if err != nil {
+		eventLog.Error(err.Error())
+		return
+	}
+
+	eventLog.With(logging.Fields{"rows": rows}).Info("Output event prices report ready")
+
+	event := broker.EventData{
+		Message:    "Prices report ready",
+		Data:       string(encodedReport),
+		APIVersion: APIVersion,
+		ClientID:   clientID}
+
+	go publish(ctx, engine.Broker, event)
+}
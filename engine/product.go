@@ -1,9 +1,11 @@
 package engine
 
 import (
-	"strconv"
+	"context"
+	"errors"
 	"time"
 
+	"github.com/hecatoncheir/Sproot/engine/money"
 	"github.com/hecatoncheir/Sproot/engine/storage"
 )
 
@@ -21,13 +23,16 @@ type ProductOfCompany struct {
 // PriceOfProduct part of structure of ProductOfCompany
 type PriceOfProduct struct {
 	Value    string
+	Currency string
 	DateTime time.Time
 	City     CityData
 }
 
-// UpdateInStorage method for create product if it needed or add price to product
-func (product *ProductOfCompany) UpdateInStorage(store *storage.Storage) (storage.Product, error) {
-	products, err := store.Products.ReadProductsByName(product.Name, product.Language)
+// UpdateInStorage method for create product if it needed, add price to product and update offer of product
+// in city of company by the price. Price with the same value as the latest one only marks it as seen
+// when policy records changes.
+func (product *ProductOfCompany) UpdateInStorage(ctx context.Context, store *storage.Storage, policy storage.IngestPolicy) (storage.Product, error) {
+	products, err := store.Products.ReadProductsByName(ctx, product.Name, product.Language, storage.Projection{PricesLimit: 1})
 
 	productFromStorage := storage.Product{
 		Name:             product.Name,
@@ -46,17 +51,17 @@ func (product *ProductOfCompany) UpdateInStorage(store *storage.Storage) (storag
 		}
 	}
 
-	if err != nil && err != storage.ErrProductsByNameNotFound {
+	if err != nil && !errors.Is(err, storage.ErrProductsByNameNotFound) {
 		return productFromStorage, err
 	}
 
-	if err == storage.ErrProductsByNameNotFound || products == nil {
+	if errors.Is(err, storage.ErrProductsByNameNotFound) || products == nil {
 		productForStorage := storage.Product{
 			Name:             product.Name,
 			IRI:              product.IRI,
 			PreviewImageLink: product.PreviewImageLink}
 
-		productInStorage, err := store.Products.CreateProduct(productForStorage, product.Language)
+		productInStorage, err := store.Products.CreateProduct(ctx, productForStorage, product.Language)
 		if err != nil {
 			return productInStorage, err
 		}
@@ -64,45 +69,91 @@ func (product *ProductOfCompany) UpdateInStorage(store *storage.Storage) (storag
 		productFromStorage.ID = productInStorage.ID
 		productFromStorage.IsActive = productInStorage.IsActive
 
-		err = store.Products.AddCategoryToProduct(productInStorage.ID, product.Category.ID)
+		err = store.Products.AddCategoryToProduct(ctx, productInStorage.ID, product.Category.ID)
 		if err != nil {
 			return productInStorage, err
 		}
 
-		err = store.Products.AddCompanyToProduct(productInStorage.ID, product.Company.ID)
+		err = store.Products.AddCompanyToProduct(ctx, productInStorage.ID, product.Company.ID)
 		if err != nil {
 			return productInStorage, err
 		}
+
+		productsUpdated.Inc("created")
+	} else {
+		productsUpdated.Inc("matched")
+	}
+
+	currency := product.Price.Currency
+	if currency == "" {
+		currency = store.PricesCurrency()
+	}
+
+	amount, err := money.Parse(product.Price.Value, currency)
+	if err != nil {
+		return productFromStorage, err
+	}
+
+	priceForStorage := storage.PriceOf(amount, product.Price.DateTime)
+
+	if policy.Mode == storage.RecordChanges {
+		if policy.DefaultCurrency == "" {
+			policy.DefaultCurrency = store.PricesCurrency()
+		}
+
+		latest, err := store.Prices.ReadLatestPrice(ctx, productFromStorage.ID, product.Company.ID, product.Price.City.ID)
+		if err != nil && !errors.Is(err, storage.ErrPriceDoesNotExist) {
+			return productFromStorage, err
+		}
+
+		if !policy.MustRecord(latest, priceForStorage) {
+			if priceForStorage.DateTime.After(latest.LastSeenAt) {
+				err = store.Prices.MarkPriceAsSeen(ctx, latest.ID, priceForStorage.DateTime)
+				if err != nil {
+					return productFromStorage, err
+				}
+
+				err = store.Offers.MarkOfferAsSeen(ctx, productFromStorage.ID, product.Company.ID,
+					product.Price.City.ID, priceForStorage.DateTime)
+				if err != nil {
+					return productFromStorage, err
+				}
+			}
+
+			pricesDeduplicated.Inc()
+
+			return store.Products.ReadProductByID(ctx, productFromStorage.ID, product.Language)
+		}
 	}
 
-	priceValue, err := strconv.ParseFloat(product.Price.Value, 64)
+	priceFromStorage, err := store.Prices.CreatePrice(ctx, priceForStorage)
 	if err != nil {
 		return productFromStorage, err
 	}
 
-	priceForStorage := storage.Price{Value: priceValue, DateTime: product.Price.DateTime}
+	pricesInserted.Inc()
 
-	priceFromStorage, err := store.Prices.CreatePrice(priceForStorage)
+	err = store.Prices.AddCompanyToPrice(ctx, priceFromStorage.ID, product.Company.ID)
 	if err != nil {
 		return productFromStorage, err
 	}
 
-	err = store.Prices.AddCompanyToPrice(priceFromStorage.ID, product.Company.ID)
+	err = store.Prices.AddProductToPrice(ctx, priceFromStorage.ID, productFromStorage.ID)
 	if err != nil {
 		return productFromStorage, err
 	}
 
-	err = store.Prices.AddProductToPrice(priceFromStorage.ID, productFromStorage.ID)
+	err = store.Prices.AddCityToPrice(ctx, priceFromStorage.ID, product.Price.City.ID)
 	if err != nil {
 		return productFromStorage, err
 	}
 
-	err = store.Prices.AddCityToPrice(priceFromStorage.ID, product.Price.City.ID)
+	_, err = store.Offers.UpdateOffer(ctx, productFromStorage.ID, product.Company.ID, product.Price.City.ID, priceForStorage)
 	if err != nil {
 		return productFromStorage, err
 	}
 
-	productFromStorage, err = store.Products.ReadProductByID(productFromStorage.ID, product.Language)
+	productFromStorage, err = store.Products.ReadProductByID(ctx, productFromStorage.ID, product.Language)
 	if err != nil {
 		return productFromStorage, err
 	}
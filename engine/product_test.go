@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -15,51 +16,59 @@ func TestIntegrationNewPriceWithNewProductCanBeCreated(test *testing.T) {
 	test.Skip()
 
 	config := configuration.New()
-	engine := New(config)
+	engine, err := New(config)
+	if err != nil {
+		test.Fatal(err)
+	}
 
-	err := engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
+	_, err = engine.MigrateStorage(config.Development.Database.Host, config.Development.Database.Port)
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
 	if err != nil {
 		test.Error(err)
 	}
 
 	companyForTest := storage.Company{Name: "М.Видео", IRI: "http://www.mvideo.ru/"}
-	createdCompany, err := engine.Storage.Companies.CreateCompany(companyForTest, "ru")
+	createdCompany, err := engine.Storage.Companies.CreateCompany(context.Background(), companyForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Companies.DeleteCompany(createdCompany)
+		_, err := engine.Storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	categoryForTest := storage.Category{Name: "Смартфоны"}
-	createdCategory, err := engine.Storage.Categories.CreateCategory(categoryForTest, "ru")
+	createdCategory, err := engine.Storage.Categories.CreateCategory(context.Background(), categoryForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Categories.DeleteCategory(createdCategory)
+		_, err := engine.Storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = engine.Storage.Categories.AddCompanyToCategory(createdCategory.ID, createdCompany.ID)
+	err = engine.Storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdCity, err := engine.Storage.Cities.CreateCity(storage.City{Name: "Москва"}, "ru")
+	createdCity, err := engine.Storage.Cities.CreateCity(context.Background(), storage.City{Name: "Москва"}, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Cities.DeleteCity(createdCity)
+		_, err := engine.Storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
@@ -91,7 +100,7 @@ func TestIntegrationNewPriceWithNewProductCanBeCreated(test *testing.T) {
 			Name: createdCategory.Name},
 	}
 
-	productFromStorage, err := product.UpdateInStorage(engine.Storage)
+	productFromStorage, err := product.UpdateInStorage(context.Background(), engine.Storage, storage.IngestPolicy{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -101,20 +110,20 @@ func TestIntegrationNewPriceWithNewProductCanBeCreated(test *testing.T) {
 	}
 
 	defer func() {
-		_, err := engine.Storage.Prices.DeletePrice(productFromStorage.Prices[0])
+		_, err := engine.Storage.Prices.DeletePrice(context.Background(), productFromStorage.Prices[0])
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	defer func() {
-		_, err := engine.Storage.Products.DeleteProduct(productFromStorage)
+		_, err := engine.Storage.Products.DeleteProduct(context.Background(), productFromStorage)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	products, err := engine.Storage.Products.ReadProductsByName(product.Name, "ru")
+	products, err := engine.Storage.Products.ReadProductsByName(context.Background(), product.Name, "ru", storage.Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -154,51 +163,59 @@ func TestIntegrationNewPriceWithExistedProductCanBeCreated(test *testing.T) {
 	test.Parallel()
 
 	config := configuration.New()
-	engine := New(config)
+	engine, err := New(config)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = engine.MigrateStorage(config.Development.Database.Host, config.Development.Database.Port)
+	if err != nil {
+		test.Error(err)
+	}
 
-	err := engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
+	err = engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
 	if err != nil {
 		test.Error(err)
 	}
 
 	companyForTest := storage.Company{Name: "М.ВИДЕО", IRI: "http://www.mvideo.ru/"}
-	createdCompany, err := engine.Storage.Companies.CreateCompany(companyForTest, "ru")
+	createdCompany, err := engine.Storage.Companies.CreateCompany(context.Background(), companyForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Companies.DeleteCompany(createdCompany)
+		_, err := engine.Storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	categoryForTest := storage.Category{Name: "Смартфоны"}
-	createdCategory, err := engine.Storage.Categories.CreateCategory(categoryForTest, "ru")
+	createdCategory, err := engine.Storage.Categories.CreateCategory(context.Background(), categoryForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Categories.DeleteCategory(createdCategory)
+		_, err := engine.Storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = engine.Storage.Categories.AddCompanyToCategory(createdCategory.ID, createdCompany.ID)
+	err = engine.Storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdCity, err := engine.Storage.Cities.CreateCity(storage.City{Name: "Москва"}, "ru")
+	createdCity, err := engine.Storage.Cities.CreateCity(context.Background(), storage.City{Name: "Москва"}, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Cities.DeleteCity(createdCity)
+		_, err := engine.Storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
@@ -209,24 +226,24 @@ func TestIntegrationNewPriceWithExistedProductCanBeCreated(test *testing.T) {
 		IRI:              "http://www.mvideo.ru//products/smartfon-samsung-galaxy-s8-64gb-chernyi-brilliant-30027818",
 		PreviewImageLink: "img.mvideo.ru/Pdb/30027818m.jpg"}
 
-	createdProduct, err := engine.Storage.Products.CreateProduct(productForTest, "ru")
+	createdProduct, err := engine.Storage.Products.CreateProduct(context.Background(), productForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Products.DeleteProduct(createdProduct)
+		_, err := engine.Storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = engine.Storage.Products.AddCategoryToProduct(createdProduct.ID, createdCategory.ID)
+	err = engine.Storage.Products.AddCategoryToProduct(context.Background(), createdProduct.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = engine.Storage.Products.AddCompanyToProduct(createdProduct.ID, createdCompany.ID)
+	err = engine.Storage.Products.AddCompanyToProduct(context.Background(), createdProduct.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
@@ -257,7 +274,7 @@ func TestIntegrationNewPriceWithExistedProductCanBeCreated(test *testing.T) {
 			Name: createdCategory.Name},
 	}
 
-	productFromStorage, err := product.UpdateInStorage(engine.Storage)
+	productFromStorage, err := product.UpdateInStorage(context.Background(), engine.Storage, storage.IngestPolicy{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -267,13 +284,13 @@ func TestIntegrationNewPriceWithExistedProductCanBeCreated(test *testing.T) {
 	}
 
 	defer func() {
-		_, err := engine.Storage.Prices.DeletePrice(productFromStorage.Prices[0])
+		_, err := engine.Storage.Prices.DeletePrice(context.Background(), productFromStorage.Prices[0])
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	products, err := engine.Storage.Products.ReadProductsByName(product.Name, "ru")
+	products, err := engine.Storage.Products.ReadProductsByName(context.Background(), product.Name, "ru", storage.Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -320,53 +337,61 @@ func TestIntegrationNewPriceWithExistedProductsCanBeCreatedForRightProduct(test
 	test.Parallel()
 
 	config := configuration.New()
-	engine := New(config)
+	engine, err := New(config)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = engine.MigrateStorage(config.Development.Database.Host, config.Development.Database.Port)
+	if err != nil {
+		test.Error(err)
+	}
 
-	err := engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
+	err = engine.SetUpStorage(config.Development.Database.Host, config.Development.Database.Port)
 	if err != nil {
 		test.Error(err)
 	}
 
 	companyForTest := storage.Company{Name: "М.Видео", IRI: "http://www.mvideo.ru/"}
-	createdCompany, err := engine.Storage.Companies.CreateCompany(companyForTest, "ru")
+	createdCompany, err := engine.Storage.Companies.CreateCompany(context.Background(), companyForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Companies.DeleteCompany(createdCompany)
+		_, err := engine.Storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	categoryForTest := storage.Category{Name: "Смартфоны"}
-	createdCategory, err := engine.Storage.Categories.CreateCategory(categoryForTest, "ru")
+	createdCategory, err := engine.Storage.Categories.CreateCategory(context.Background(), categoryForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Categories.DeleteCategory(createdCategory)
+		_, err := engine.Storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = engine.Storage.Categories.AddCompanyToCategory(createdCategory.ID, createdCompany.ID)
+	err = engine.Storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
 	cityForTest := storage.City{Name: "Москва - тестовый город"}
 
-	createdCity, err := engine.Storage.Cities.CreateCity(cityForTest, "ru")
+	createdCity, err := engine.Storage.Cities.CreateCity(context.Background(), cityForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Cities.DeleteCity(createdCity)
+		_, err := engine.Storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
@@ -377,24 +402,24 @@ func TestIntegrationNewPriceWithExistedProductsCanBeCreatedForRightProduct(test
 		IRI:              "http://www.mvideo.ru//products/smartfon-samsung-galaxy-s8-64gb-chernyi-brilliant-30027818",
 		PreviewImageLink: "img.mvideo.ru/Pdb/30027818m.jpg"}
 
-	createdProduct, err := engine.Storage.Products.CreateProduct(productForTest, "ru")
+	createdProduct, err := engine.Storage.Products.CreateProduct(context.Background(), productForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Products.DeleteProduct(createdProduct)
+		_, err := engine.Storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = engine.Storage.Products.AddCategoryToProduct(createdProduct.ID, createdCategory.ID)
+	err = engine.Storage.Products.AddCategoryToProduct(context.Background(), createdProduct.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = engine.Storage.Products.AddCompanyToProduct(createdProduct.ID, createdCompany.ID)
+	err = engine.Storage.Products.AddCompanyToProduct(context.Background(), createdProduct.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
@@ -404,18 +429,18 @@ func TestIntegrationNewPriceWithExistedProductsCanBeCreatedForRightProduct(test
 		IRI:              "http://www.mvideo.ru//products/smartfon-samsung-galaxy-s8-64gb-chernyi-brilliant-30027818",
 		PreviewImageLink: "img.mvideo.ru/Pdb/30027818m.jpg"}
 
-	otherCreatedProduct, err := engine.Storage.Products.CreateProduct(otherProductForTest, "ru")
+	otherCreatedProduct, err := engine.Storage.Products.CreateProduct(context.Background(), otherProductForTest, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = engine.Storage.Products.AddCategoryToProduct(otherCreatedProduct.ID, createdCategory.ID)
+	err = engine.Storage.Products.AddCategoryToProduct(context.Background(), otherCreatedProduct.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := engine.Storage.Products.DeleteProduct(otherCreatedProduct)
+		_, err := engine.Storage.Products.DeleteProduct(context.Background(), otherCreatedProduct)
 		if err != nil {
 			test.Error(err)
 		}
@@ -447,7 +472,7 @@ func TestIntegrationNewPriceWithExistedProductsCanBeCreatedForRightProduct(test
 			Name: createdCategory.Name},
 	}
 
-	productFromStorage, err := product.UpdateInStorage(engine.Storage)
+	productFromStorage, err := product.UpdateInStorage(context.Background(), engine.Storage, storage.IngestPolicy{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -457,13 +482,13 @@ func TestIntegrationNewPriceWithExistedProductsCanBeCreatedForRightProduct(test
 	}
 
 	defer func() {
-		_, err := engine.Storage.Prices.DeletePrice(productFromStorage.Prices[0])
+		_, err := engine.Storage.Prices.DeletePrice(context.Background(), productFromStorage.Prices[0])
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	categoryWithProducts, err := engine.Storage.Categories.ReadCategoryByID(createdCategory.ID, "ru")
+	categoryWithProducts, err := engine.Storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -472,7 +497,7 @@ func TestIntegrationNewPriceWithExistedProductsCanBeCreatedForRightProduct(test
 		test.Fail()
 	}
 
-	productWithoutPrice, err := engine.Storage.Products.ReadProductByID(otherCreatedProduct.ID, "ru")
+	productWithoutPrice, err := engine.Storage.Products.ReadProductByID(context.Background(), otherCreatedProduct.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -481,7 +506,7 @@ func TestIntegrationNewPriceWithExistedProductsCanBeCreatedForRightProduct(test
 		test.Fail()
 	}
 
-	products, err := engine.Storage.Products.ReadProductsByName(product.Name, "ru")
+	products, err := engine.Storage.Products.ReadProductsByName(context.Background(), product.Name, "ru", storage.Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+// RetentionRequest is a policy of retention of prices, archive is a path of file for removed prices
+type RetentionRequest struct {
+	KeepDays    int
+	Per         string
+	ArchivePath string
+	DryRun      bool
+}
+
+// RetentionRequestOfSettings is a method for get retention request with policy of settings of engine
+func (engine *Engine) RetentionRequestOfSettings() RetentionRequest {
+	return RetentionRequest{
+		KeepDays:    engine.Settings.RetentionDays,
+		Per:         engine.Settings.RetentionPer,
+		ArchivePath: engine.Settings.RetentionArchive}
+}
+
+// ApplyRetention is a method for downsample old prices of storage by request,
+// removed prices are appended to file of archive when it is set
+func (engine *Engine) ApplyRetention(ctx context.Context, request RetentionRequest) (storage.RetentionReport, error) {
+	policy := storage.RetentionPolicy{KeepDays: request.KeepDays, Resolution: request.Per, DryRun: request.DryRun}
+
+	if request.ArchivePath != "" && !request.DryRun {
+		archive, err := os.OpenFile(request.ArchivePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return storage.RetentionReport{}, err
+		}
+
+		defer archive.Close()
+		policy.Archive = archive
+	}
+
+	report, err := engine.Storage.Prices.ApplyRetention(ctx, policy, time.Now())
+
+	logOf(ctx, logging.Fields{"products": report.Products, "kept": report.Kept, "removed": report.Removed,
+		"dryRun": request.DryRun}).Info("Retention of prices applied")
+
+	return report, err
+}
+
+// RetentionJob is a method for get job of retention of prices by settings of engine
+func (engine *Engine) RetentionJob() Job {
+	return Job{
+		Name:     "retention",
+		Interval: engine.Settings.RetentionInterval,
+		Run: func(ctx context.Context) error {
+			_, err := engine.ApplyRetention(ctx, engine.RetentionRequestOfSettings())
+			return err
+		}}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/tracing"
+)
+
+// Job is a work which is run by scheduler at every interval, zero interval disables it
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Schedule is a method for run job at every interval of it until context is done.
+// Runs of one job do not overlap, next run waits for the previous one.
+func (engine *Engine) Schedule(ctx context.Context, job Job) {
+	if job.Interval <= 0 {
+		logging.Default.With(logging.Fields{"job": job.Name}).Info("Job is disabled")
+		return
+	}
+
+	logging.Default.With(logging.Fields{"job": job.Name, "interval": job.Interval}).Info("Job is scheduled")
+
+	go func() {
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				RunJob(ctx, job)
+			}
+		}
+	}()
+}
+
+// RunJob is a function for run job once with span, log and metrics of its result
+func RunJob(ctx context.Context, job Job) error {
+	ctx, span := tracing.Start(ctx, "job "+job.Name)
+	span.SetAttribute("job", job.Name)
+	defer span.Finish()
+
+	started := time.Now()
+	err := job.Run(ctx)
+	duration := time.Since(started)
+	jobDuration.Observe(duration.Seconds(), job.Name)
+
+	if err != nil {
+		jobRuns.Inc(job.Name, "failed")
+		span.SetError(err)
+		logOf(ctx, logging.Fields{"job": job.Name, "duration": duration, "kind": KindOf(err)}).Error(err.Error())
+
+		return err
+	}
+
+	jobRuns.Inc(job.Name, "done")
+	logOf(ctx, logging.Fields{"job": job.Name, "duration": duration}).Info("Job is done")
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hecatoncheir/Configuration"
+)
+
+func TestJobIsRunAtEveryIntervalUntilContextIsDone(test *testing.T) {
+	engine, err := New(configuration.New())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	runs := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	engine.Schedule(ctx, Job{Name: "test", Interval: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	}})
+
+	for index := 0; index < 2; index++ {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			test.Fatal("Job must be run at every interval")
+		}
+	}
+
+	cancel()
+
+	done := jobRuns.Value("test", "done")
+	if done < 2 {
+		test.Error(done)
+	}
+}
+
+func TestFailedJobIsCounted(test *testing.T) {
+	failed := jobRuns.Value("failing", "failed")
+
+	err := RunJob(context.Background(), Job{Name: "failing", Run: func(ctx context.Context) error {
+		return errors.New("job failed")
+	}})
+
+	if err == nil || jobRuns.Value("failing", "failed") != failed+1 {
+		test.Error(err)
+	}
+
+	engine, err := New(configuration.New())
+	if err != nil {
+		test.Fatal(err)
+	}
+	engine.Schedule(context.Background(), Job{Name: "disabled", Run: func(ctx context.Context) error {
+		test.Error("Job without interval must not be run")
+		return nil
+	}})
+}
@@ -0,0 +1,526 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hecatoncheir/Configuration"
+	"github.com/hecatoncheir/Sproot/engine/cache"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/money"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+const (
+	// Production is a name of production environment of configuration
+	Production = "production"
+
+	// Development is a name of development environment of configuration, it is used by tests
+	Development = "development"
+
+	// EnvironmentVariable is a name of environment variable with selected environment
+	EnvironmentVariable = "SPROOT_ENVIRONMENT"
+
+	// DefaultRetentionDays is a count of days of prices with full resolution
+	DefaultRetentionDays = 90
+
+	// DefaultPriceHeartbeat is an interval after which the same parsed price is recorded
+	DefaultPriceHeartbeat = 24 * time.Hour
+)
+
+var (
+	// ErrEnvironmentIsNotSupported means that selected environment is not production or development
+	ErrEnvironmentIsNotSupported = errors.New("environment is not supported")
+
+	// ErrSettingsCanNotBeRead means that environment of configuration can not be copied to settings
+	ErrSettingsCanNotBeRead = errors.New("settings can not be read")
+)
+
+// InvalidSettingsError is a list of problems of settings found by validation
+type InvalidSettingsError struct {
+	Problems []string
+}
+
+func (err *InvalidSettingsError) Error() string {
+	return fmt.Sprintf("settings are not valid: %v", strings.Join(err.Problems, "; "))
+}
+
+// Address of service
+type Address struct {
+	Host string
+	Port int
+}
+
+// TLS is a configuration of TLS of connections to database, TLS is used when any of files or server name is set
+type TLS struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// IsEnabled is a method for check that connections to database must use TLS
+func (tls TLS) IsEnabled() bool {
+	return tls.CAFile != "" || tls.CertFile != "" || tls.KeyFile != "" || tls.ServerName != ""
+}
+
+// Settings is an effective configuration of selected environment with overrides
+type Settings struct {
+	Environment       string
+	APIVersion        string
+	ServiceName       string
+	SprootTopic       string
+	HecatoncheirTopic string
+	InitialTopic      string
+	LogunaTopic       string
+	LogLevel          string
+	TraceExporter     string
+	TraceEndpoint     string
+	EventBus          Address
+	Database          Address
+	DatabaseEndpoints string
+	DatabaseTLS       TLS
+	DatabaseKeepalive time.Duration
+	QueryTimeout      time.Duration
+	MutationTimeout   time.Duration
+	CacheSize         int
+	CacheTTL          time.Duration
+	RetentionDays     int
+	RetentionPer      string
+	RetentionInterval time.Duration
+	RetentionArchive  string
+	PriceIngest       string
+	PriceHeartbeat    time.Duration
+	DefaultCurrency   string
+	ReferenceCurrency string
+}
+
+// environment is the same as Production and Development of configuration
+type environment struct {
+	SprootTopic       string
+	HecatoncheirTopic string
+	InitialTopic      string
+	LogunaTopic       string
+	EventBus          Address
+	Database          Address
+}
+
+// variable is a setting which can be overridden by environment variable and flag,
+// optional setting can be empty
+type variable struct {
+	Flag     string
+	Env      string
+	Usage    string
+	optional bool
+	value    func(settings *Settings) string
+	override func(settings *Settings, value string) error
+}
+
+func text(field func(settings *Settings) *string) (func(*Settings) string, func(*Settings, string) error) {
+	value := func(settings *Settings) string { return *field(settings) }
+	override := func(settings *Settings, value string) error {
+		*field(settings) = value
+		return nil
+	}
+
+	return value, override
+}
+
+func number(field func(settings *Settings) *int) (func(*Settings) string, func(*Settings, string) error) {
+	value := func(settings *Settings) string { return strconv.Itoa(*field(settings)) }
+	override := func(settings *Settings, value string) error {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("value must be a number: %v", value)
+		}
+
+		*field(settings) = number
+
+		return nil
+	}
+
+	return value, override
+}
+
+func duration(field func(settings *Settings) *time.Duration) (func(*Settings) string, func(*Settings, string) error) {
+	value := func(settings *Settings) string { return field(settings).String() }
+	override := func(settings *Settings, value string) error {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("value must be a duration like 10s: %v", value)
+		}
+
+		*field(settings) = duration
+
+		return nil
+	}
+
+	return value, override
+}
+
+var variables []variable
+
+func init() {
+	add := func(flagName, env, usage string, value func(*Settings) string, override func(*Settings, string) error) {
+		variables = append(variables, variable{Flag: flagName, Env: env, Usage: usage, value: value, override: override})
+	}
+
+	addOptional := func(flagName, env, usage string, value func(*Settings) string, override func(*Settings, string) error) {
+		add(flagName, env, usage, value, override)
+		variables[len(variables)-1].optional = true
+	}
+
+	apiVersion, setAPIVersion := text(func(settings *Settings) *string { return &settings.APIVersion })
+	add("api-version", "SPROOT_API_VERSION", "version of API of events", apiVersion, setAPIVersion)
+
+	serviceName, setServiceName := text(func(settings *Settings) *string { return &settings.ServiceName })
+	add("service-name", "SPROOT_SERVICE_NAME", "name of service in events and logs", serviceName, setServiceName)
+
+	databaseHost, setDatabaseHost := text(func(settings *Settings) *string { return &settings.Database.Host })
+	add("database-host", "SPROOT_DATABASE_HOST", "host of database", databaseHost, setDatabaseHost)
+
+	databasePort, setDatabasePort := number(func(settings *Settings) *int { return &settings.Database.Port })
+	add("database-port", "SPROOT_DATABASE_PORT", "port of database", databasePort, setDatabasePort)
+
+	databaseEndpoints, setDatabaseEndpoints := text(func(settings *Settings) *string { return &settings.DatabaseEndpoints })
+	addOptional("database-endpoints", "SPROOT_DATABASE_ENDPOINTS", "other Alphas of database like alpha-2:9080,alpha-3:9080",
+		databaseEndpoints, setDatabaseEndpoints)
+
+	databaseCA, setDatabaseCA := text(func(settings *Settings) *string { return &settings.DatabaseTLS.CAFile })
+	addOptional("database-tls-ca", "SPROOT_DATABASE_TLS_CA", "file of CA certificates for TLS of database",
+		databaseCA, setDatabaseCA)
+
+	databaseCert, setDatabaseCert := text(func(settings *Settings) *string { return &settings.DatabaseTLS.CertFile })
+	addOptional("database-tls-cert", "SPROOT_DATABASE_TLS_CERT", "file of client certificate for mTLS of database",
+		databaseCert, setDatabaseCert)
+
+	databaseKey, setDatabaseKey := text(func(settings *Settings) *string { return &settings.DatabaseTLS.KeyFile })
+	addOptional("database-tls-key", "SPROOT_DATABASE_TLS_KEY", "file of key of client certificate for mTLS of database",
+		databaseKey, setDatabaseKey)
+
+	databaseServerName, setDatabaseServerName := text(func(settings *Settings) *string { return &settings.DatabaseTLS.ServerName })
+	addOptional("database-tls-server-name", "SPROOT_DATABASE_TLS_SERVER_NAME", "name of server in certificate of database",
+		databaseServerName, setDatabaseServerName)
+
+	databaseKeepalive, setDatabaseKeepalive := duration(func(settings *Settings) *time.Duration { return &settings.DatabaseKeepalive })
+	add("database-keepalive", "SPROOT_DATABASE_KEEPALIVE", "time without activity after which connection to database is checked",
+		databaseKeepalive, setDatabaseKeepalive)
+
+	queryTimeout, setQueryTimeout := duration(func(settings *Settings) *time.Duration { return &settings.QueryTimeout })
+	add("database-query-timeout", "SPROOT_DATABASE_QUERY_TIMEOUT", "default timeout of queries of database",
+		queryTimeout, setQueryTimeout)
+
+	mutationTimeout, setMutationTimeout := duration(func(settings *Settings) *time.Duration { return &settings.MutationTimeout })
+	add("database-mutation-timeout", "SPROOT_DATABASE_MUTATION_TIMEOUT", "default timeout of mutations of database",
+		mutationTimeout, setMutationTimeout)
+
+	cacheSize, setCacheSize := number(func(settings *Settings) *int { return &settings.CacheSize })
+	add("cache-size", "SPROOT_CACHE_SIZE", "count of cached results of reads of database, 0 disables cache",
+		cacheSize, setCacheSize)
+
+	cacheTTL, setCacheTTL := duration(func(settings *Settings) *time.Duration { return &settings.CacheTTL })
+	add("cache-ttl", "SPROOT_CACHE_TTL", "time to live of cached results of reads of database", cacheTTL, setCacheTTL)
+
+	retentionDays, setRetentionDays := number(func(settings *Settings) *int { return &settings.RetentionDays })
+	add("retention-days", "SPROOT_RETENTION_DAYS", "count of days of prices with full resolution",
+		retentionDays, setRetentionDays)
+
+	retentionPer, setRetentionPer := text(func(settings *Settings) *string { return &settings.RetentionPer })
+	add("retention-per", "SPROOT_RETENTION_PER", "resolution of older prices: day or change", retentionPer, setRetentionPer)
+
+	retentionInterval, setRetentionInterval := duration(func(settings *Settings) *time.Duration { return &settings.RetentionInterval })
+	add("retention-interval", "SPROOT_RETENTION_INTERVAL", "interval of retention of prices by serve, 0s disables it",
+		retentionInterval, setRetentionInterval)
+
+	retentionArchive, setRetentionArchive := text(func(settings *Settings) *string { return &settings.RetentionArchive })
+	addOptional("retention-archive", "SPROOT_RETENTION_ARCHIVE", "file for JSON lines of removed prices",
+		retentionArchive, setRetentionArchive)
+
+	priceIngest, setPriceIngest := text(func(settings *Settings) *string { return &settings.PriceIngest })
+	add("price-ingest", "SPROOT_PRICE_INGEST", "ingest of parsed prices: all or changes", priceIngest, setPriceIngest)
+
+	priceHeartbeat, setPriceHeartbeat := duration(func(settings *Settings) *time.Duration { return &settings.PriceHeartbeat })
+	add("price-heartbeat", "SPROOT_PRICE_HEARTBEAT", "interval after which the same parsed price is recorded, 0s records only changes",
+		priceHeartbeat, setPriceHeartbeat)
+
+	defaultCurrency, setDefaultCurrency := text(func(settings *Settings) *string { return &settings.DefaultCurrency })
+	add("default-currency", "SPROOT_DEFAULT_CURRENCY", "currency of parsed and saved prices without currency",
+		defaultCurrency, setDefaultCurrency)
+
+	referenceCurrency, setReferenceCurrency := text(func(settings *Settings) *string { return &settings.ReferenceCurrency })
+	add("reference-currency", "SPROOT_REFERENCE_CURRENCY", "currency which rates of other currencies are set to",
+		referenceCurrency, setReferenceCurrency)
+
+	eventBusHost, setEventBusHost := text(func(settings *Settings) *string { return &settings.EventBus.Host })
+	add("event-bus-host", "SPROOT_EVENT_BUS_HOST", "host of event bus", eventBusHost, setEventBusHost)
+
+	eventBusPort, setEventBusPort := number(func(settings *Settings) *int { return &settings.EventBus.Port })
+	add("event-bus-port", "SPROOT_EVENT_BUS_PORT", "port of event bus", eventBusPort, setEventBusPort)
+
+	sprootTopic, setSprootTopic := text(func(settings *Settings) *string { return &settings.SprootTopic })
+	add("sproot-topic", "SPROOT_TOPIC", "topic of events for sproot", sprootTopic, setSprootTopic)
+
+	hecatoncheirTopic, setHecatoncheirTopic := text(func(settings *Settings) *string { return &settings.HecatoncheirTopic })
+	add("hecatoncheir-topic", "SPROOT_HECATONCHEIR_TOPIC", "topic of events for parsers", hecatoncheirTopic, setHecatoncheirTopic)
+
+	initialTopic, setInitialTopic := text(func(settings *Settings) *string { return &settings.InitialTopic })
+	add("initial-topic", "SPROOT_INITIAL_TOPIC", "topic of answers for clients", initialTopic, setInitialTopic)
+
+	logunaTopic, setLogunaTopic := text(func(settings *Settings) *string { return &settings.LogunaTopic })
+	add("loguna-topic", "SPROOT_LOGUNA_TOPIC", "topic of logs", logunaTopic, setLogunaTopic)
+
+	logLevel, setLogLevel := text(func(settings *Settings) *string { return &settings.LogLevel })
+	add("log-level", "SPROOT_LOG_LEVEL", "level of log: debug, info, warning or error", logLevel, setLogLevel)
+
+	traceExporter, setTraceExporter := text(func(settings *Settings) *string { return &settings.TraceExporter })
+	add("trace-exporter", "SPROOT_TRACE_EXPORTER", "exporter of traces: none, stdout or otlp", traceExporter, setTraceExporter)
+
+	traceEndpoint, setTraceEndpoint := text(func(settings *Settings) *string { return &settings.TraceEndpoint })
+	add("trace-endpoint", "SPROOT_TRACE_ENDPOINT", "OTLP HTTP endpoint of collector of traces", traceEndpoint, setTraceEndpoint)
+}
+
+// Overrides are values of flags of settings, empty value is not applied
+type Overrides struct {
+	Environment *string
+	values      map[string]*string
+}
+
+// Flags is a function for register flags of environment and of each setting
+func Flags(flags *flag.FlagSet) Overrides {
+	overrides := Overrides{
+		Environment: flags.String("environment", "",
+			fmt.Sprintf("environment of configuration: %v or %v, %v by default", Production, Development, EnvironmentVariable)),
+		values: map[string]*string{}}
+
+	for _, variable := range variables {
+		overrides.values[variable.Flag] = flags.String(variable.Flag, "",
+			fmt.Sprintf("%v, overrides %v", variable.Usage, variable.Env))
+	}
+
+	return overrides
+}
+
+// Load is a constructor for Settings of selected environment of configuration
+// with values of environment variables and then of flags over it
+func Load(config *configuration.Configuration, lookup func(string) (string, bool), overrides Overrides) (Settings, error) {
+	selected := Production
+	if value, ok := lookup(EnvironmentVariable); ok && value != "" {
+		selected = value
+	}
+
+	if overrides.Environment != nil && *overrides.Environment != "" {
+		selected = *overrides.Environment
+	}
+
+	settings, err := New(config, selected)
+	if err != nil {
+		return settings, err
+	}
+
+	for _, variable := range variables {
+		value, ok := lookup(variable.Env)
+		if !ok || value == "" {
+			continue
+		}
+
+		err = variable.override(&settings, value)
+		if err != nil {
+			return settings, fmt.Errorf("%v: %v", variable.Env, err)
+		}
+	}
+
+	for _, variable := range variables {
+		value := overrides.values[variable.Flag]
+		if value == nil || *value == "" {
+			continue
+		}
+
+		err = variable.override(&settings, *value)
+		if err != nil {
+			return settings, fmt.Errorf("-%v: %v", variable.Flag, err)
+		}
+	}
+
+	return settings, nil
+}
+
+// New is a constructor for Settings of environment of configuration without overrides
+func New(config *configuration.Configuration, selected string) (Settings, error) {
+	settings := Settings{
+		Environment:       selected,
+		APIVersion:        config.APIVersion,
+		ServiceName:       config.ServiceName,
+		LogLevel:          logging.Info.String(),
+		TraceExporter:     "none",
+		TraceEndpoint:     "http://localhost:4318/v1/traces",
+		QueryTimeout:      storage.DefaultQueryTimeout,
+		MutationTimeout:   storage.DefaultMutationTimeout,
+		DatabaseKeepalive: storage.DefaultKeepaliveTime,
+		CacheSize:         cache.DefaultCapacity,
+		CacheTTL:          cache.DefaultTTL,
+		RetentionDays:     DefaultRetentionDays,
+		RetentionPer:      storage.PerDay,
+		PriceIngest:       storage.RecordAll,
+		PriceHeartbeat:    DefaultPriceHeartbeat,
+		DefaultCurrency:   storage.DefaultCurrency,
+		ReferenceCurrency: storage.DefaultCurrency}
+
+	if settings.ServiceName == "" {
+		settings.ServiceName = "Sproot"
+	}
+
+	var source interface{}
+
+	switch selected {
+	case Production:
+		source = config.Production
+	case Development:
+		source = config.Development
+	default:
+		return settings, ErrEnvironmentIsNotSupported
+	}
+
+	encoded, err := json.Marshal(source)
+	if err != nil {
+		logging.Default.With(logging.Fields{"environment": selected}).Error(err.Error())
+		return settings, ErrSettingsCanNotBeRead
+	}
+
+	copied := environment{}
+	err = json.Unmarshal(encoded, &copied)
+	if err != nil {
+		logging.Default.With(logging.Fields{"environment": selected}).Error(err.Error())
+		return settings, ErrSettingsCanNotBeRead
+	}
+
+	settings.SprootTopic = copied.SprootTopic
+	settings.HecatoncheirTopic = copied.HecatoncheirTopic
+	settings.InitialTopic = copied.InitialTopic
+	settings.LogunaTopic = copied.LogunaTopic
+	settings.EventBus = copied.EventBus
+	settings.Database = copied.Database
+
+	return settings, nil
+}
+
+// Validate is a method for check that all settings are set and ports are in range
+func (settings Settings) Validate() error {
+	var problems []string
+
+	for _, variable := range variables {
+		if !variable.optional && variable.value(&settings) == "" {
+			problems = append(problems, fmt.Sprintf("%v is not set, use %v or -%v", variable.Usage, variable.Env, variable.Flag))
+		}
+	}
+
+	for _, address := range []struct {
+		name string
+		Address
+	}{{"database", settings.Database}, {"event bus", settings.EventBus}} {
+		if address.Port < 1 || address.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("port of %v must be between 1 and 65535, got %v", address.name, address.Port))
+		}
+	}
+
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{{"timeout of queries", settings.QueryTimeout}, {"timeout of mutations", settings.MutationTimeout},
+		{"keepalive", settings.DatabaseKeepalive}} {
+		if timeout.value <= 0 {
+			problems = append(problems, fmt.Sprintf("%v of database must be positive, got %v", timeout.name, timeout.value))
+		}
+	}
+
+	if settings.CacheSize < 0 {
+		problems = append(problems, fmt.Sprintf("size of cache must not be negative, got %v", settings.CacheSize))
+	}
+
+	if settings.CacheTTL <= 0 {
+		problems = append(problems, fmt.Sprintf("time to live of cache must be positive, got %v", settings.CacheTTL))
+	}
+
+	if settings.RetentionDays <= 0 {
+		problems = append(problems, fmt.Sprintf("days of retention must be positive, got %v", settings.RetentionDays))
+	}
+
+	if settings.RetentionPer != storage.PerDay && settings.RetentionPer != storage.PerChange {
+		problems = append(problems, fmt.Sprintf("resolution of retention must be %v or %v, got %v",
+			storage.PerDay, storage.PerChange, settings.RetentionPer))
+	}
+
+	if settings.RetentionInterval < 0 {
+		problems = append(problems, fmt.Sprintf("interval of retention must not be negative, got %v", settings.RetentionInterval))
+	}
+
+	if settings.PriceIngest != storage.RecordAll && settings.PriceIngest != storage.RecordChanges {
+		problems = append(problems, fmt.Sprintf("ingest of prices must be %v or %v, got %v",
+			storage.RecordAll, storage.RecordChanges, settings.PriceIngest))
+	}
+
+	if settings.PriceHeartbeat < 0 {
+		problems = append(problems, fmt.Sprintf("heartbeat of prices must not be negative, got %v", settings.PriceHeartbeat))
+	}
+
+	for _, currency := range []string{settings.DefaultCurrency, settings.ReferenceCurrency} {
+		if err := money.ValidateCurrency(currency); err != nil && currency != "" {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if _, err := storage.ParseEndpoints(settings.DatabaseEndpoints, settings.Database.Port); err != nil {
+		problems = append(problems, fmt.Sprintf("endpoints of database must be like alpha-2:9080,alpha-3:9080, got %v",
+			settings.DatabaseEndpoints))
+	}
+
+	if (settings.DatabaseTLS.CertFile == "") != (settings.DatabaseTLS.KeyFile == "") {
+		problems = append(problems, "certificate and key of client for mTLS of database must be set together")
+	}
+
+	if _, err := logging.ParseLevel(settings.LogLevel); settings.LogLevel != "" && err != nil {
+		problems = append(problems, fmt.Sprintf("level of log must be debug, info, warning or error, got %v", settings.LogLevel))
+	}
+
+	switch settings.TraceExporter {
+	case "", "none", "stdout", "otlp":
+	default:
+		problems = append(problems, fmt.Sprintf("exporter of traces must be none, stdout or otlp, got %v", settings.TraceExporter))
+	}
+
+	if len(problems) > 0 {
+		return &InvalidSettingsError{Problems: problems}
+	}
+
+	return nil
+}
+
+// String is a printout of effective settings with redacted credentials of hosts
+func (settings Settings) String() string {
+	lines := []string{fmt.Sprintf("environment: %v", settings.Environment)}
+
+	for _, variable := range variables {
+		lines = append(lines, fmt.Sprintf("%v: %v", variable.Flag, redact(variable.value(&settings))))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// redact hide user and password in values like user:password@host
+func redact(value string) string {
+	at := strings.LastIndex(value, "@")
+	if at < 0 {
+		return value
+	}
+
+	scheme := strings.Index(value, "://")
+	if scheme >= 0 && scheme < at {
+		return value[:scheme+3] + "******" + value[at:]
+	}
+
+	return "******" + value[at:]
+}
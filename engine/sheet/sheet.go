@@ -0,0 +1,194 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Writer is a table written row by row, Close must be called after last row
+type Writer interface {
+	WriteRow(cells ...interface{}) error
+	Close() error
+}
+
+const (
+	// FormatCSV is a text with comma separated values
+	FormatCSV = "csv"
+
+	// FormatXLSX is an Office Open XML workbook with one sheet
+	FormatXLSX = "xlsx"
+)
+
+// ErrFormatIsNotSupported means that the table can't be written in requested format
+var ErrFormatIsNotSupported = errors.New("format of sheet is not supported")
+
+// NewWriter is a constructor of Writer for format
+func NewWriter(format string, writer io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(writer), nil
+	case FormatXLSX:
+		xlsxWriter, err := NewXLSXWriter(writer, "Sheet1")
+		if err != nil {
+			return nil, err
+		}
+
+		return xlsxWriter, nil
+	default:
+		return nil, ErrFormatIsNotSupported
+	}
+}
+
+func cellText(cell interface{}) string {
+	switch value := cell.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(value)
+	case time.Time:
+		return value.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// CSVWriter is a Writer of comma separated values
+type CSVWriter struct {
+	csv *csv.Writer
+}
+
+// NewCSVWriter is a constructor of CSVWriter
+func NewCSVWriter(writer io.Writer) *CSVWriter {
+	return &CSVWriter{csv: csv.NewWriter(writer)}
+}
+
+// WriteRow is a method for write one line of values
+func (writer *CSVWriter) WriteRow(cells ...interface{}) error {
+	record := make([]string, len(cells))
+	for index, cell := range cells {
+		record[index] = cellText(cell)
+	}
+
+	return writer.csv.Write(record)
+}
+
+// Close is a method for flush written lines
+func (writer *CSVWriter) Close() error {
+	writer.csv.Flush()
+	return writer.csv.Error()
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	xlsxRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// XLSXWriter is a Writer of workbook with one sheet, rows are written to archive as they come
+type XLSXWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+}
+
+// NewXLSXWriter is a constructor of XLSXWriter, it writes all parts of workbook before rows of sheet
+func NewXLSXWriter(writer io.Writer, sheetName string) (*XLSXWriter, error) {
+	archive := zip.NewWriter(writer)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRelationships},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapeXML(sheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRelationships}}
+
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = io.WriteString(file, part.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(sheet, xlsxSheetStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &XLSXWriter{archive: archive, sheet: sheet}, nil
+}
+
+func escapeXML(value string) string {
+	escaped := bytes.Buffer{}
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
+
+// WriteRow is a method for write one row of sheet, numbers are written as numeric cells
+func (writer *XLSXWriter) WriteRow(cells ...interface{}) error {
+	row := bytes.Buffer{}
+	row.WriteString("<row>")
+
+	for _, cell := range cells {
+		switch cell.(type) {
+		case float64, int:
+			fmt.Fprintf(&row, "<c><v>%s</v></c>", cellText(cell))
+		default:
+			fmt.Fprintf(&row, `<c t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, escapeXML(cellText(cell)))
+		}
+	}
+
+	row.WriteString("</row>")
+
+	_, err := writer.sheet.Write(row.Bytes())
+	return err
+}
+
+// Close is a method for finish sheet and archive of workbook
+func (writer *XLSXWriter) Close() error {
+	_, err := io.WriteString(writer.sheet, xlsxSheetEnd)
+	if err != nil {
+		return err
+	}
+
+	return writer.archive.Close()
+}
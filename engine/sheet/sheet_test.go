@@ -0,0 +1,112 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRowsCanBeWrittenToCSV(test *testing.T) {
+	buffer := bytes.Buffer{}
+
+	writer, err := NewWriter(FormatCSV, &buffer)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	dateTime := time.Date(2018, 5, 1, 16, 27, 18, 0, time.UTC)
+
+	err = writer.WriteRow("product", "value", "datetime")
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = writer.WriteRow("Phone, black", 12999.5, dateTime)
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		test.Error(err)
+	}
+
+	expected := "product,value,datetime\n\"Phone, black\",12999.5,2018-05-01T16:27:18Z\n"
+	if buffer.String() != expected {
+		test.Errorf("Expected %q, actual %q", expected, buffer.String())
+	}
+}
+
+func TestRowsCanBeWrittenToXLSX(test *testing.T) {
+	buffer := bytes.Buffer{}
+
+	writer, err := NewWriter(FormatXLSX, &buffer)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	err = writer.WriteRow("product", "value")
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = writer.WriteRow("Phone <black> & white", 12999.5)
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		test.Error(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		files[file.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if _, ok := files[name]; !ok {
+			test.Errorf("Part %v of workbook is not written", name)
+		}
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+
+	if !strings.Contains(sheet, "<t xml:space=\"preserve\">Phone &lt;black&gt; &amp; white</t>") {
+		test.Fail()
+	}
+
+	if !strings.Contains(sheet, "<c><v>12999.5</v></c>") {
+		test.Fail()
+	}
+
+	if !strings.HasSuffix(sheet, "</sheetData></worksheet>") {
+		test.Fail()
+	}
+}
+
+func TestUnknownFormatIsNotSupported(test *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{})
+	if err != ErrFormatIsNotSupported {
+		test.Fail()
+	}
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hecatoncheir/Broker"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+	"github.com/hecatoncheir/Sproot/engine/storage"
+)
+
+// PriceStatisticsRequest is a data of broker events for statistics of prices of product or of categories,
+// zero dates of window are not limited, empty currency is currency of prices which are saved without currency
+type PriceStatisticsRequest struct {
+	ProductID string    `json:"productID,omitempty"`
+	Language  string    `json:"language,omitempty"`
+	Currency  string    `json:"currency,omitempty"`
+	From      time.Time `json:"from,omitempty"`
+	To        time.Time `json:"to,omitempty"`
+}
+
+// ReadPriceStatistics is a method for read statistics of prices of product of request
+func (engine *Engine) ReadPriceStatistics(ctx context.Context, request PriceStatisticsRequest) (interface{}, error) {
+	return engine.Storage.Prices.ReadProductPriceStatistics(ctx, request.ProductID, request.From, request.To, request.Currency)
+}
+
+// ReadCategoriesPriceStatistics is a method for read averages of prices of all categories
+func (engine *Engine) ReadCategoriesPriceStatistics(ctx context.Context, request PriceStatisticsRequest) (interface{}, error) {
+	return engine.Storage.Prices.ReadCategoriesPriceStatistics(ctx, request.From, request.To, request.Language, request.Currency)
+}
+
+// priceStatisticsHandler read statistics of request by read and answer with readyMessage event
+// or with "Price statistics can not be made" event
+func (engine *Engine) priceStatisticsHandler(ctx context.Context, message, readyMessage, data, clientID, APIVersion string,
+	read func(ctx context.Context, request PriceStatisticsRequest) (interface{}, error)) {
+
+	eventLog := logOf(ctx, logging.Fields{"event": message, "clientID": clientID})
+
+	request := PriceStatisticsRequest{Language: "ru"}
+	err := json.Unmarshal([]byte(data), &request)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrRequestIsNotValid, err)
+		eventLog.Error(err.Error())
+		engine.replyWithError(ctx, "Price statistics can not be made", err, clientID, APIVersion)
+
+		return
+	}
+
+	eventLog = eventLog.With(logging.Fields{"productID": request.ProductID, "from": request.From, "to": request.To,
+		"currency": request.Currency})
+	eventLog.Info("Input event of price statistics")
+
+	statistics, err := read(storage.WithConsistency(ctx, storage.BestEffort), request)
+	if err != nil {
+		eventLog.With(logging.Fields{"kind": KindOf(err)}).Error(err.Error())
+		engine.replyWithError(ctx, "Price statistics can not be made", err, clientID, APIVersion)
+
+		return
+	}
+
+	encodedStatistics, err := json.Marshal(statistics)
+	if err != nil {
+		eventLog.Error(err.Error())
+		return
+	}
+
+	eventLog.Info("Output event price statistics ready")
+
+	event := broker.EventData{
+		Message:    readyMessage,
+		Data:       string(encodedStatistics),
+		APIVersion: APIVersion,
+		ClientID:   clientID}
+
+	go publish(ctx, engine.Broker, event)
+}
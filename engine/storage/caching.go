@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Tags of cached lists of resources, entries of cache are tagged by uid of each node of their value too
+const (
+	productsTag   = "products"
+	companiesTag  = "companies"
+	categoriesTag = "categories"
+	citiesTag     = "cities"
+)
+
+// cacheKey join parts of key of cached value of method
+func cacheKey(parts ...interface{}) string {
+	texts := make([]string, len(parts))
+	for index, part := range parts {
+		texts[index] = fmt.Sprint(part)
+	}
+
+	return strings.Join(texts, "/")
+}
+
+// lookup return cached value of key and generation of cache for remember value read from database.
+// Cache is not used for linearizable reads.
+func (storage *Storage) lookup(ctx context.Context, key string) (interface{}, uint64) {
+	generation := storage.Cache.Generation()
+	if ConsistencyOf(ctx) == Linearizable {
+		return nil, generation
+	}
+
+	value, ok := storage.Cache.Get(key)
+	if !ok {
+		return nil, generation
+	}
+
+	return value, generation
+}
+
+// remember save value read from database to cache with tags and uid of each node of value
+func (storage *Storage) remember(key string, generation uint64, value interface{}, tags ...string) {
+	if storage.Cache == nil {
+		return
+	}
+
+	storage.Cache.Set(key, value, generation, append(tags, uidsOf(value)...)...)
+}
+
+// invalidate remove cached values with nodes of uids or lists of tags, it is called after mutations
+func (storage *Storage) invalidate(tags ...string) {
+	storage.Cache.Invalidate(tags...)
+}
+
+// uidsOf return uid of each node of products, companies, categories, prices and cities of value
+func uidsOf(value interface{}) []string {
+	var uids []string
+
+	var add func(value interface{})
+	add = func(value interface{}) {
+		switch node := value.(type) {
+		case ProductsByNameForPage:
+			add(node.Products)
+		case []Product:
+			for _, product := range node {
+				add(product)
+			}
+		case Product:
+			uids = append(uids, node.ID)
+			add(node.Categories)
+			add(node.Companies)
+			add(node.Prices)
+			add(node.Offers)
+		case []Offer:
+			for _, offer := range node {
+				add(offer)
+			}
+		case Offer:
+			uids = append(uids, node.ID)
+			add(node.Products)
+			add(node.Companies)
+			add(node.Cities)
+		case []Company:
+			for _, company := range node {
+				add(company)
+			}
+		case Company:
+			uids = append(uids, node.ID)
+			add(node.Categories)
+		case []Category:
+			for _, category := range node {
+				add(category)
+			}
+		case Category:
+			uids = append(uids, node.ID)
+			add(node.Companies)
+			add(node.Products)
+		case []Price:
+			for _, price := range node {
+				add(price)
+			}
+		case Price:
+			uids = append(uids, node.ID)
+			add(node.Cities)
+			add(node.Products)
+			add(node.Companies)
+		case []City:
+			for _, city := range node {
+				add(city)
+			}
+		case City:
+			uids = append(uids, node.ID)
+		}
+	}
+
+	add(value)
+
+	return uids
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/cache"
+)
+
+func TestCachedValuesAreInvalidatedByNodes(test *testing.T) {
+	storage := &Storage{Cache: cache.New("test-storage", 10, time.Minute)}
+	ctx := context.Background()
+
+	product := Product{ID: "0x1", Companies: []Company{{ID: "0x2"}},
+		Prices: []Price{{ID: "0x3", Cities: []City{{ID: "0x4"}}}}}
+
+	key := cacheKey("product", product.ID, "ru")
+	_, generation := storage.lookup(ctx, key)
+	storage.remember(key, generation, product)
+
+	cached, _ := storage.lookup(ctx, key)
+	if cached == nil || cached.(Product).ID != "0x1" {
+		test.Fatal(cached)
+	}
+
+	if cached, _ := storage.lookup(WithConsistency(ctx, Linearizable), key); cached != nil {
+		test.Error("Cache must not be used for linearizable reads")
+	}
+
+	storage.invalidate("0x4")
+	if cached, _ := storage.lookup(ctx, key); cached != nil {
+		test.Error("Product must be invalidated by city of its price")
+	}
+}
+
+func TestStorageWithoutCacheReadsDatabase(test *testing.T) {
+	storage := &Storage{}
+	ctx := context.Background()
+
+	_, generation := storage.lookup(ctx, "key")
+	storage.remember("key", generation, City{ID: "0x1"}, citiesTag)
+	storage.invalidate(citiesTag)
+
+	if cached, _ := storage.lookup(ctx, "key"); cached != nil {
+		test.Error(cached)
+	}
+}
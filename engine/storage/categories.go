@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"text/template"
 
 	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
@@ -46,25 +45,6 @@ func NewCategoriesResourceForStorage(storage *Storage) *Categories {
 	return &Categories{storage: storage}
 }
 
-// SetUp is a method of Categories resource for prepare database client and schema.
-func (categories *Categories) SetUp() (err error) {
-	schema := `
-		categoryName: string @lang @index(term).
-		categoryIsActive: bool @index(bool) .
-		belongs_to_company: uid .
-		has_product: uid .
-	`
-	operation := &dataBaseAPI.Operation{Schema: schema}
-
-	err = categories.storage.Client.Alter(context.Background(), operation)
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-
-	return nil
-}
-
 var (
 	// ErrCategoriesByNameNotFound means than the categories does not exist in database
 	ErrCategoriesByNameNotFound = errors.New("categories by name not found")
@@ -77,46 +57,49 @@ var (
 )
 
 // CreateCategory make category and save it to storage
-func (categories *Categories) CreateCategory(category Category, language string) (Category, error) {
-	existsCategories, err := categories.ReadCategoriesByName(category.Name, language)
-	if err != nil && err != ErrCategoriesByNameNotFound {
-		log.Println(err)
-		return category, ErrCategoryCanNotBeCreated
+func (categories *Categories) CreateCategory(ctx context.Context, category Category, language string) (Category, error) {
+	defer categories.storage.invalidate(categoriesTag)
+
+	existsCategories, err := categories.ReadCategoriesByName(ctx, category.Name, language)
+	if err != nil && !errors.Is(err, ErrCategoriesByNameNotFound) {
+		logError(err)
+		return category, wrap(ErrCategoryCanNotBeCreated, err)
 	}
 	if existsCategories != nil {
-		return existsCategories[0], ErrCategoryAlreadyExist
+		return existsCategories[0], fail(ErrCategoryAlreadyExist)
 	}
 
-	transaction := categories.storage.Client.NewTxn()
+	transaction := categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	category.IsActive = true
 	encodedCategory, err := json.Marshal(category)
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryCanNotBeCreated
+		logError(err)
+		return category, wrap(ErrCategoryCanNotBeCreated, err)
 	}
 
 	mutation := &dataBaseAPI.Mutation{
 		SetJson:   encodedCategory,
 		CommitNow: true}
 
-	assigned, err := transaction.Mutate(context.Background(), mutation)
+	assigned, err := transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryCanNotBeCreated
+		logError(err)
+		return category, wrap(ErrCategoryCanNotBeCreated, err)
 	}
 
 	category.ID = assigned.Uids["blank-0"]
 	if category.ID == "" {
-		return category, ErrCategoryCanNotBeCreated
+		return category, fail(ErrCategoryCanNotBeCreated)
 	}
 
-	err = categories.AddLanguageOfCategoryName(category.ID, category.Name, language)
+	err = categories.AddLanguageOfCategoryName(ctx, category.ID, category.Name, language)
 	if err != nil {
 		return category, err
 	}
 
-	createdCategory, err := categories.ReadCategoryByID(category.ID, language)
+	createdCategory, err := categories.ReadCategoryByID(ctx, category.ID, language)
 	if err != nil {
 		return category, err
 	}
@@ -125,15 +108,18 @@ func (categories *Categories) CreateCategory(category Category, language string)
 }
 
 // AddLanguageOfCategoryName is a method for add predicate "categoryName" for companyName value with new language
-func (categories *Categories) AddLanguageOfCategoryName(categoryID, name, language string) error {
+func (categories *Categories) AddLanguageOfCategoryName(ctx context.Context, categoryID, name, language string) error {
+	defer categories.storage.invalidate(categoryID, categoriesTag)
+
 	forCategoryNamePredicate := fmt.Sprintf(`<%s> <categoryName> %s .`, categoryID, "\""+name+"\""+"@"+language)
 
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(forCategoryNamePredicate),
 		CommitNow: true}
 
-	transaction := categories.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
 		return err
 	}
@@ -147,7 +133,12 @@ var (
 )
 
 // ReadCategoriesByName is a method for get all nodes by categories name
-func (categories *Categories) ReadCategoriesByName(categoryName, language string) ([]Category, error) {
+func (categories *Categories) ReadCategoriesByName(ctx context.Context, categoryName, language string) ([]Category, error) {
+	key := cacheKey("categories-by-name", language, categoryName)
+	cached, generation := categories.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.([]Category), nil
+	}
 
 	variables := struct {
 		CategoryName string
@@ -170,7 +161,7 @@ func (categories *Categories) ReadCategoriesByName(categoryName, language string
 							uid
 							categoryName: categoryName@{{.Language}}
 							categoryIsActive
-							belong_to_company @filter(eq(companyIsActive, true)) {
+							belongs_to_company @filter(eq(companyIsActive, true)) {
 								uid
 								companyName: companyName@{{.Language}}
 								companyIsActive
@@ -198,22 +189,23 @@ func (categories *Categories) ReadCategoriesByName(categoryName, language string
 			}`)
 
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCategoriesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCategoriesByNameCanNotBeFound, err)
 	}
 
 	queryBuf := bytes.Buffer{}
 	err = queryTemplate.Execute(&queryBuf, variables)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCategoriesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCategoriesByNameCanNotBeFound, err)
 	}
 
-	transaction := categories.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := categories.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCategoriesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCategoriesByNameCanNotBeFound, err)
 	}
 
 	type categoriesInStorage struct {
@@ -223,17 +215,68 @@ func (categories *Categories) ReadCategoriesByName(categoryName, language string
 	var foundedCategories categoriesInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedCategories)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCategoriesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCategoriesByNameCanNotBeFound, err)
 	}
 
 	if len(foundedCategories.AllCategoriesFoundedByName) == 0 {
-		return nil, ErrCategoriesByNameNotFound
+		return nil, fail(ErrCategoriesByNameNotFound)
 	}
 
+	categories.storage.remember(key, generation, foundedCategories.AllCategoriesFoundedByName, categoriesTag)
+
 	return foundedCategories.AllCategoriesFoundedByName, nil
 }
 
+// ReadAllCategories is a method for get all active categories with their companies
+func (categories *Categories) ReadAllCategories(ctx context.Context, language string) ([]Category, error) {
+	key := cacheKey("categories", language)
+	cached, generation := categories.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.([]Category), nil
+	}
+
+	query := fmt.Sprintf(`{
+				categories(func: eq(categoryIsActive, true)) @filter(has(categoryName)) {
+					uid
+					categoryName: categoryName@%v
+					categoryIsActive
+					belongs_to_company @filter(eq(companyIsActive, true)) {
+						uid
+						companyName: companyName@%v
+						companyIsActive
+					}
+				}
+			}`, language, language)
+
+	transaction := categories.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrCategoriesByNameCanNotBeFound, err)
+	}
+
+	type categoriesInStorage struct {
+		AllCategories []Category `json:"categories"`
+	}
+
+	var foundedCategories categoriesInStorage
+	err = json.Unmarshal(response.GetJson(), &foundedCategories)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrCategoriesByNameCanNotBeFound, err)
+	}
+
+	if len(foundedCategories.AllCategories) == 0 {
+		return nil, fail(ErrCategoriesByNameNotFound)
+	}
+
+	categories.storage.remember(key, generation, foundedCategories.AllCategories, categoriesTag)
+
+	return foundedCategories.AllCategories, nil
+}
+
 var (
 	// ErrCategoryByIDCanNotBeFound means that the category can't be found in database
 	ErrCategoryByIDCanNotBeFound = errors.New("category by id can not be found")
@@ -243,7 +286,12 @@ var (
 )
 
 // ReadCategoryByID is a method for get all nodes of categories by ID
-func (categories *Categories) ReadCategoryByID(categoryID, language string) (Category, error) {
+func (categories *Categories) ReadCategoryByID(ctx context.Context, categoryID, language string) (Category, error) {
+	key := cacheKey("category", categoryID, language)
+	cached, generation := categories.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.(Category), nil
+	}
 
 	variables := struct {
 		CategoryID string
@@ -265,7 +313,7 @@ func (categories *Categories) ReadCategoryByID(categoryID, language string) (Cat
 							uid
 							categoryName: categoryName@{{.Language}}
 							categoryIsActive
-							belong_to_company @filter(eq(companyIsActive, true)) {
+							belongs_to_company @filter(eq(companyIsActive, true)) {
 								uid
 								companyName: companyName@{{.Language}}
 								companyIsActive
@@ -295,22 +343,23 @@ func (categories *Categories) ReadCategoryByID(categoryID, language string) (Cat
 	category := Category{ID: categoryID}
 
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryByIDCanNotBeFound
+		logError(err)
+		return category, wrap(ErrCategoryByIDCanNotBeFound, err)
 	}
 
 	queryBuf := bytes.Buffer{}
 	err = queryTemplate.Execute(&queryBuf, variables)
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryByIDCanNotBeFound
+		logError(err)
+		return category, wrap(ErrCategoryByIDCanNotBeFound, err)
 	}
 
-	transaction := categories.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := categories.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryByIDCanNotBeFound
+		logError(err)
+		return category, wrap(ErrCategoryByIDCanNotBeFound, err)
 	}
 
 	type categoriesInStore struct {
@@ -321,54 +370,61 @@ func (categories *Categories) ReadCategoryByID(categoryID, language string) (Cat
 
 	err = json.Unmarshal(response.GetJson(), &foundedCategories)
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryByIDCanNotBeFound
+		logError(err)
+		return category, wrap(ErrCategoryByIDCanNotBeFound, err)
 	}
 
 	if len(foundedCategories.Categories) == 0 {
-		return category, ErrCategoryDoesNotExist
+		return category, fail(ErrCategoryDoesNotExist)
 	}
 
+	categories.storage.remember(key, generation, foundedCategories.Categories[0])
+
 	return foundedCategories.Categories[0], nil
 }
 
 // UpdateCategory method for change category in storage
-func (categories *Categories) UpdateCategory(category Category) (Category, error) {
+func (categories *Categories) UpdateCategory(ctx context.Context, category Category) (Category, error) {
+	defer categories.storage.invalidate(category.ID, categoriesTag)
+
 	if category.ID == "" {
-		return category, ErrCategoryCanNotBeWithoutID
+		return category, fail(ErrCategoryCanNotBeWithoutID)
 	}
 
-	transaction := categories.storage.Client.NewTxn()
+	transaction := categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	encodedCategory, err := json.Marshal(category)
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryCanNotBeUpdated
+		logError(err)
+		return category, wrap(ErrCategoryCanNotBeUpdated, err)
 	}
 
 	mutation := &dataBaseAPI.Mutation{
 		SetJson:   encodedCategory,
 		CommitNow: true}
 
-	_, err = transaction.Mutate(context.Background(), mutation)
+	_, err = transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryCanNotBeUpdated
+		logError(err)
+		return category, wrap(ErrCategoryCanNotBeUpdated, err)
 	}
 
-	updatedCategory, err := categories.ReadCategoryByID(category.ID, ".")
+	updatedCategory, err := categories.ReadCategoryByID(ctx, category.ID, ".")
 	if err != nil {
-		log.Println(err)
-		return category, ErrCategoryCanNotBeUpdated
+		logError(err)
+		return category, wrap(ErrCategoryCanNotBeUpdated, err)
 	}
 
 	return updatedCategory, nil
 }
 
 // DeactivateCategory method for remove categories from database
-func (categories *Categories) DeactivateCategory(category Category) (string, error) {
+func (categories *Categories) DeactivateCategory(ctx context.Context, category Category) (string, error) {
+	defer categories.storage.invalidate(category.ID, categoriesTag)
+
 	if category.ID == "" {
-		return "", ErrCategoryCanNotBeWithoutID
+		return "", fail(ErrCategoryCanNotBeWithoutID)
 	}
 
 	categoryForUpdate := Category{
@@ -377,10 +433,10 @@ func (categories *Categories) DeactivateCategory(category Category) (string, err
 		Companies: category.Companies,
 		IsActive:  false}
 
-	updatedCategory, err := categories.UpdateCategory(categoryForUpdate)
+	updatedCategory, err := categories.UpdateCategory(ctx, categoryForUpdate)
 	if err != nil {
-		log.Println(err)
-		return "", ErrCategoryCanNotBeDeactivate
+		logError(err)
+		return "", wrap(ErrCategoryCanNotBeDeactivate, err)
 	}
 
 	return updatedCategory.ID, nil
@@ -395,10 +451,11 @@ var (
 )
 
 // DeleteCategory method for remove category from database
-func (categories *Categories) DeleteCategory(category Category) (string, error) {
+func (categories *Categories) DeleteCategory(ctx context.Context, category Category) (string, error) {
+	defer categories.storage.invalidate(category.ID, categoriesTag)
 
 	if category.ID == "" {
-		return "", ErrCategoryCanNotBeWithoutID
+		return "", fail(ErrCategoryCanNotBeWithoutID)
 	}
 
 	deleteCategoryData, _ := json.Marshal(map[string]string{"uid": category.ID})
@@ -408,13 +465,14 @@ func (categories *Categories) DeleteCategory(category Category) (string, error)
 		CommitNow:           true,
 		IgnoreIndexConflict: true}
 
-	transaction := categories.storage.Client.NewTxn()
+	transaction := categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	var err error
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
-		return category.ID, ErrCategoryCanNotBeDeleted
+		logError(err)
+		return category.ID, wrap(ErrCategoryCanNotBeDeleted, err)
 	}
 
 	return category.ID, nil
@@ -426,7 +484,9 @@ var (
 )
 
 // AddCompanyToCategory method for set quad of predicate about category and company
-func (categories *Categories) AddCompanyToCategory(categoryID, companyID string) error {
+func (categories *Categories) AddCompanyToCategory(ctx context.Context, categoryID, companyID string) error {
+	defer categories.storage.invalidate(categoryID, companyID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -436,10 +496,11 @@ func (categories *Categories) AddCompanyToCategory(categoryID, companyID string)
 		SetNquads: []byte(forCompanyPredicate),
 		CommitNow: true}
 
-	transaction := categories.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCategoryCanNotBeAddedToCompany
+		return wrap(ErrCategoryCanNotBeAddedToCompany, err)
 	}
 
 	forCategoryPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, categoryID, "belongs_to_company", companyID)
@@ -447,17 +508,20 @@ func (categories *Categories) AddCompanyToCategory(categoryID, companyID string)
 		SetNquads: []byte(forCategoryPredicate),
 		CommitNow: true}
 
-	transaction = categories.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCompanyCanNotBeAddedToCategory
+		return wrap(ErrCompanyCanNotBeAddedToCategory, err)
 	}
 
 	return nil
 }
 
 // RemoveCompanyFromCategory method for delete quad of predicate about category and company
-func (categories *Categories) RemoveCompanyFromCategory(categoryID, companyID string) error {
+func (categories *Categories) RemoveCompanyFromCategory(ctx context.Context, categoryID, companyID string) error {
+	defer categories.storage.invalidate(categoryID, companyID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -467,10 +531,11 @@ func (categories *Categories) RemoveCompanyFromCategory(categoryID, companyID st
 		DelNquads: []byte(forCompanyPredicate),
 		CommitNow: true}
 
-	transaction := categories.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCategoryCanNotBeRemovedFromCompany
+		return wrap(ErrCategoryCanNotBeRemovedFromCompany, err)
 	}
 
 	forCategoryPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, categoryID, "belongs_to_company", companyID)
@@ -478,17 +543,20 @@ func (categories *Categories) RemoveCompanyFromCategory(categoryID, companyID st
 		DelNquads: []byte(forCategoryPredicate),
 		CommitNow: true}
 
-	transaction = categories.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCompanyCanNotBeRemovedFromCategory
+		return wrap(ErrCompanyCanNotBeRemovedFromCategory, err)
 	}
 
 	return nil
 }
 
 // AddProductToCategory method for set quad of predicate about category and product
-func (categories *Categories) AddProductToCategory(categoryID, productID string) error {
+func (categories *Categories) AddProductToCategory(ctx context.Context, categoryID, productID string) error {
+	defer categories.storage.invalidate(categoryID, productID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -498,10 +566,11 @@ func (categories *Categories) AddProductToCategory(categoryID, productID string)
 		SetNquads: []byte(forProductPredicate),
 		CommitNow: true}
 
-	transaction := categories.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrProductCanNotBeAddedToCategory
+		return wrap(ErrProductCanNotBeAddedToCategory, err)
 	}
 
 	forCategoryPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, categoryID, "has_product", productID)
@@ -509,10 +578,11 @@ func (categories *Categories) AddProductToCategory(categoryID, productID string)
 		SetNquads: []byte(forCategoryPredicate),
 		CommitNow: true}
 
-	transaction = categories.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = categories.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrProductCanNotBeAddedToCategory
+		return wrap(ErrProductCanNotBeAddedToCategory, err)
 	}
 
 	return nil
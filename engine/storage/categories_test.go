@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -8,13 +10,13 @@ func TestIntegrationCategoryCanBeCreated(test *testing.T) {
 	once.Do(prepareStorage)
 
 	categoryForCreate := Category{Name: "Test category"}
-	createdCategory, err := storage.Categories.CreateCategory(categoryForCreate, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), categoryForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
@@ -24,8 +26,8 @@ func TestIntegrationCategoryCanBeCreated(test *testing.T) {
 		test.Fail()
 	}
 
-	existCategory, err := storage.Categories.CreateCategory(categoryForCreate, "en")
-	if err == nil || err != ErrCategoryAlreadyExist {
+	existCategory, err := storage.Categories.CreateCategory(context.Background(), categoryForCreate, "en")
+	if err == nil || !errors.Is(err, ErrCategoryAlreadyExist) {
 		test.Error(err)
 	}
 
@@ -39,8 +41,8 @@ func TestIntegrationCategoriesCanBeReadByName(test *testing.T) {
 
 	categoryForSearch := Category{Name: "Test category"}
 
-	categoriesFromStore, err := storage.Categories.ReadCategoriesByName(categoryForSearch.Name, ".")
-	if err != ErrCategoriesByNameNotFound {
+	categoriesFromStore, err := storage.Categories.ReadCategoriesByName(context.Background(), categoryForSearch.Name, ".")
+	if !errors.Is(err, ErrCategoriesByNameNotFound) {
 		test.Fail()
 	}
 
@@ -48,19 +50,19 @@ func TestIntegrationCategoriesCanBeReadByName(test *testing.T) {
 		test.Fail()
 	}
 
-	createdCategory, err := storage.Categories.CreateCategory(categoryForSearch, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), categoryForSearch, "en")
 	if err != nil || createdCategory.ID == "" {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	categoriesFromStore, err = storage.Categories.ReadCategoriesByName(createdCategory.Name, ".")
+	categoriesFromStore, err = storage.Categories.ReadCategoriesByName(context.Background(), createdCategory.Name, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -79,13 +81,13 @@ func TestIntegrationCategoryCanBeReadById(test *testing.T) {
 
 	categoryForSearch := Category{Name: "Test category"}
 
-	categoriesFromStore, err := storage.Categories.ReadCategoriesByName(categoryForSearch.Name, "en")
-	if err != ErrCategoriesByNameNotFound {
+	categoriesFromStore, err := storage.Categories.ReadCategoriesByName(context.Background(), categoryForSearch.Name, "en")
+	if !errors.Is(err, ErrCategoriesByNameNotFound) {
 		test.Fail()
 	}
 
-	categoryFromStore, err := storage.Categories.ReadCategoryByID("0", ".")
-	if err != ErrCategoryDoesNotExist {
+	categoryFromStore, err := storage.Categories.ReadCategoryByID(context.Background(), "0", ".")
+	if !errors.Is(err, ErrCategoryDoesNotExist) {
 		test.Fail()
 	}
 
@@ -93,19 +95,19 @@ func TestIntegrationCategoryCanBeReadById(test *testing.T) {
 		test.Fail()
 	}
 
-	createdCategory, err := storage.Categories.CreateCategory(categoryForSearch, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), categoryForSearch, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	categoryFromStore, err = storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	categoryFromStore, err = storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -126,21 +128,21 @@ func TestIntegrationCategoryCanBeReadById(test *testing.T) {
 func TestIntegrationCategoryCanBeUpdated(test *testing.T) {
 	once.Do(prepareStorage)
 
-	updatedCategory, err := storage.Categories.UpdateCategory(Category{Name: "Updated test category"})
+	updatedCategory, err := storage.Categories.UpdateCategory(context.Background(), Category{Name: "Updated test category"})
 	if err != nil {
-		if err != ErrCategoryCanNotBeWithoutID {
+		if !errors.Is(err, ErrCategoryCanNotBeWithoutID) {
 			test.Error(err)
 		}
 	}
 
 	categoryForCreate := Category{Name: "Test category"}
-	createdCategory, err := storage.Categories.CreateCategory(categoryForCreate, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), categoryForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
@@ -151,7 +153,7 @@ func TestIntegrationCategoryCanBeUpdated(test *testing.T) {
 		Name:     "Updated test category",
 		IsActive: createdCategory.IsActive}
 
-	updatedCategory, err = storage.Categories.UpdateCategory(categoryForUpdate)
+	updatedCategory, err = storage.Categories.UpdateCategory(context.Background(), categoryForUpdate)
 	if err != nil {
 		test.Error(err)
 	}
@@ -160,7 +162,7 @@ func TestIntegrationCategoryCanBeUpdated(test *testing.T) {
 		test.Fail()
 	}
 
-	categoryInStore, err := storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	categoryInStore, err := storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -178,19 +180,19 @@ func TestIntegrationCategoryCanBeDeactivate(test *testing.T) {
 	once.Do(prepareStorage)
 
 	categoryForCreate := Category{Name: "Test category"}
-	createdCategory, err := storage.Categories.CreateCategory(categoryForCreate, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), categoryForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	categoryInStore, err := storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	categoryInStore, err := storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -199,12 +201,12 @@ func TestIntegrationCategoryCanBeDeactivate(test *testing.T) {
 		test.Fail()
 	}
 
-	updatedCategoryID, err := storage.Categories.DeactivateCategory(categoryInStore)
+	updatedCategoryID, err := storage.Categories.DeactivateCategory(context.Background(), categoryInStore)
 	if err != nil {
 		test.Error(err)
 	}
 
-	categoryInStore, err = storage.Categories.ReadCategoryByID(updatedCategoryID, ".")
+	categoryInStore, err = storage.Categories.ReadCategoryByID(context.Background(), updatedCategoryID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -220,12 +222,12 @@ func TestIntegrationCategoryCanBeDeleted(test *testing.T) {
 	var err error
 
 	categoryForCreate := Category{Name: "Test category"}
-	createdCategory, err := storage.Categories.CreateCategory(categoryForCreate, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), categoryForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	deletedCategoryID, err := storage.Categories.DeleteCategory(createdCategory)
+	deletedCategoryID, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 	if err != nil {
 		test.Error(err)
 	}
@@ -234,8 +236,8 @@ func TestIntegrationCategoryCanBeDeleted(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = storage.Categories.ReadCategoryByID(deletedCategoryID, ".")
-	if err != ErrCategoryDoesNotExist {
+	_, err = storage.Categories.ReadCategoryByID(context.Background(), deletedCategoryID, ".")
+	if !errors.Is(err, ErrCategoryDoesNotExist) {
 		test.Error(err)
 	}
 }
@@ -244,33 +246,33 @@ func TestIntegrationCompanyCanBeAddedToCategory(test *testing.T) {
 	once.Do(prepareStorage)
 
 	createdCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdFirstCompany, _ := storage.Companies.CreateCompany(Company{Name: "First test company for category"}, "en")
+	createdFirstCompany, _ := storage.Companies.CreateCompany(context.Background(), Company{Name: "First test company for category"}, "en")
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdFirstCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdFirstCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddCompanyToCategory(createdCategory.ID, createdFirstCompany.ID)
+	err = storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdFirstCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCategory, _ := storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	updatedCategory, _ := storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 
 	if len(updatedCategory.Companies) != 1 {
 		test.Fatal()
@@ -284,24 +286,24 @@ func TestIntegrationCompanyCanBeAddedToCategory(test *testing.T) {
 		test.Fail()
 	}
 
-	createdSecondCompany, err := storage.Companies.CreateCompany(Company{Name: "Second test company for category"}, "en")
+	createdSecondCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Second test company for category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdSecondCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdSecondCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddCompanyToCategory(createdCategory.ID, createdSecondCompany.ID)
+	err = storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdSecondCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCategory, err = storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	updatedCategory, err = storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -333,53 +335,53 @@ func TestIntegrationCompanyCanBeRemovedFromCategory(test *testing.T) {
 	var err error
 
 	createdCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdFirstCompany, err := storage.Companies.CreateCompany(Company{Name: "First test company for category"}, "en")
+	createdFirstCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "First test company for category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdFirstCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdFirstCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddCompanyToCategory(createdCategory.ID, createdFirstCompany.ID)
+	err = storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdFirstCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdSecondCompany, err := storage.Companies.CreateCompany(Company{Name: "Second test company for category"}, "en")
+	createdSecondCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Second test company for category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdSecondCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdSecondCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddCompanyToCategory(createdCategory.ID, createdSecondCompany.ID)
+	err = storage.Categories.AddCompanyToCategory(context.Background(), createdCategory.ID, createdSecondCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCategory, err := storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	updatedCategory, err := storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -392,12 +394,12 @@ func TestIntegrationCompanyCanBeRemovedFromCategory(test *testing.T) {
 		test.Fail()
 	}
 
-	err = storage.Categories.RemoveCompanyFromCategory(createdCategory.ID, createdFirstCompany.ID)
+	err = storage.Categories.RemoveCompanyFromCategory(context.Background(), createdCategory.ID, createdFirstCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCategory, err = storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	updatedCategory, err = storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -416,24 +418,24 @@ func TestIntegrationCategoryCanHasNameWithManyLanguages(test *testing.T) {
 
 	var err error
 
-	createdCategory, err := storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddLanguageOfCategoryName(createdCategory.ID, "Тестовая категория", "ru")
+	err = storage.Categories.AddLanguageOfCategoryName(context.Background(), createdCategory.ID, "Тестовая категория", "ru")
 	if err != nil {
 		test.Fail()
 	}
 
-	categoryWithEnName, err := storage.Categories.ReadCategoryByID(createdCategory.ID, "en")
+	categoryWithEnName, err := storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -442,7 +444,7 @@ func TestIntegrationCategoryCanHasNameWithManyLanguages(test *testing.T) {
 		test.Fail()
 	}
 
-	categoryWithRuName, err := storage.Categories.ReadCategoryByID(createdCategory.ID, "ru")
+	categoryWithRuName, err := storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -456,36 +458,36 @@ func TestIntegrationProductCanBeAddedToCategory(test *testing.T) {
 	once.Do(prepareStorage)
 
 	createdCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "First test product for category"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "First test product for category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddProductToCategory(createdCategory.ID, createdProduct.ID)
+	err = storage.Categories.AddProductToCategory(context.Background(), createdCategory.ID, createdProduct.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCategory, err := storage.Categories.ReadCategoryByID(createdCategory.ID, ".")
+	updatedCategory, err := storage.Categories.ReadCategoryByID(context.Background(), createdCategory.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -498,3 +500,30 @@ func TestIntegrationProductCanBeAddedToCategory(test *testing.T) {
 		test.Fail()
 	}
 }
+
+func TestIntegrationAllCategoriesCanBeRead(test *testing.T) {
+	once.Do(prepareStorage)
+
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Listed category"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Categories.DeleteCategory(context.Background(), createdCategory)
+
+	allCategories, err := storage.Categories.ReadAllCategories(context.Background(), "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	found := false
+	for _, category := range allCategories {
+		if category.ID == createdCategory.ID && category.Name == createdCategory.Name {
+			found = true
+		}
+	}
+
+	if !found {
+		test.Fail()
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 
 	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
 )
@@ -27,23 +26,6 @@ type Cities struct {
 	storage *Storage
 }
 
-// SetUp is a method of Cities resource for prepare database client and schema.
-func (cities *Cities) SetUp() (err error) {
-	schema := `
-		cityName: string @lang @index(term) .
-		cityIsActive: bool @index(bool) .
-	`
-	operation := &dataBaseAPI.Operation{Schema: schema}
-
-	err = cities.storage.Client.Alter(context.Background(), operation)
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-
-	return nil
-}
-
 var (
 	// ErrCityCanNotBeCreated means that the city can't be added to database
 	ErrCityCanNotBeCreated = errors.New("city can't be created")
@@ -53,43 +35,46 @@ var (
 )
 
 // CreateCity make category and save it to storage
-func (cities *Cities) CreateCity(city City, language string) (City, error) {
-	existsCities, err := cities.ReadCitiesByName(city.Name, language)
+func (cities *Cities) CreateCity(ctx context.Context, city City, language string) (City, error) {
+	defer cities.storage.invalidate(citiesTag)
 
-	if err != nil && err != ErrCitiesByNameNotFound {
-		log.Println(err)
-		return city, ErrCityCanNotBeCreated
+	existsCities, err := cities.ReadCitiesByName(ctx, city.Name, language)
+
+	if err != nil && !errors.Is(err, ErrCitiesByNameNotFound) {
+		logError(err)
+		return city, wrap(ErrCityCanNotBeCreated, err)
 	}
 
 	if existsCities != nil {
-		return existsCities[0], ErrCityAlreadyExist
+		return existsCities[0], fail(ErrCityAlreadyExist)
 	}
 
-	transaction := cities.storage.Client.NewTxn()
+	transaction := cities.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	city.IsActive = true
 	encodedCity, err := json.Marshal(city)
 	if err != nil {
-		log.Println(err)
-		return city, ErrCityCanNotBeCreated
+		logError(err)
+		return city, wrap(ErrCityCanNotBeCreated, err)
 	}
 
 	mutation := &dataBaseAPI.Mutation{
 		SetJson:   encodedCity,
 		CommitNow: true}
 
-	assigned, err := transaction.Mutate(context.Background(), mutation)
+	assigned, err := transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
-		return city, ErrCityCanNotBeCreated
+		logError(err)
+		return city, wrap(ErrCityCanNotBeCreated, err)
 	}
 
 	city.ID = assigned.Uids["blank-0"]
 	if city.ID == "" {
-		return city, ErrCityCanNotBeCreated
+		return city, fail(ErrCityCanNotBeCreated)
 	}
 
-	err = cities.AddLanguageOfCityName(city.ID, city.Name, language)
+	err = cities.AddLanguageOfCityName(ctx, city.ID, city.Name, language)
 	if err != nil {
 		return city, err
 	}
@@ -98,15 +83,18 @@ func (cities *Cities) CreateCity(city City, language string) (City, error) {
 }
 
 // AddLanguageOfCityName is a method for add predicate "cityName" for cityName value with new language
-func (cities *Cities) AddLanguageOfCityName(cityID, name, language string) error {
+func (cities *Cities) AddLanguageOfCityName(ctx context.Context, cityID, name, language string) error {
+	defer cities.storage.invalidate(cityID, citiesTag)
+
 	forCityNamePredicate := fmt.Sprintf(`<%s> <cityName> %s .`, cityID, "\""+name+"\""+"@"+language)
 
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(forCityNamePredicate),
 		CommitNow: true}
 
-	transaction := cities.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := cities.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
 		return err
 	}
@@ -123,7 +111,13 @@ var (
 )
 
 // ReadAllCities is a method for get all nodes
-func (cities *Cities) ReadAllCities(language string) ([]City, error) {
+func (cities *Cities) ReadAllCities(ctx context.Context, language string) ([]City, error) {
+	key := cacheKey("cities", language)
+	cached, generation := cities.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.([]City), nil
+	}
+
 	query := fmt.Sprintf(`{
 				cities(func: eq(cityIsActive, true)) @filter(has(cityName)) {
 					uid
@@ -132,11 +126,12 @@ func (cities *Cities) ReadAllCities(language string) ([]City, error) {
 				}
 			}`, language)
 
-	transaction := cities.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), query)
+	transaction := cities.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCitiesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCitiesByNameCanNotBeFound, err)
 	}
 
 	type citiesInStorage struct {
@@ -146,19 +141,27 @@ func (cities *Cities) ReadAllCities(language string) ([]City, error) {
 	var foundedCities citiesInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedCities)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCitiesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCitiesByNameCanNotBeFound, err)
 	}
 
 	if len(foundedCities.AllCitiesFoundedByName) == 0 {
-		return nil, ErrCitiesByNameNotFound
+		return nil, fail(ErrCitiesByNameNotFound)
 	}
 
+	cities.storage.remember(key, generation, foundedCities.AllCitiesFoundedByName, citiesTag)
+
 	return foundedCities.AllCitiesFoundedByName, nil
 }
 
 // ReadCitiesByName is a method for get all nodes by city name
-func (cities *Cities) ReadCitiesByName(cityName, language string) ([]City, error) {
+func (cities *Cities) ReadCitiesByName(ctx context.Context, cityName, language string) ([]City, error) {
+	key := cacheKey("cities-by-name", language, cityName)
+	cached, generation := cities.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.([]City), nil
+	}
+
 	query := fmt.Sprintf(`{
 				cities(func: eq(cityName@%v, "%v")) @filter(eq(cityIsActive, true)) {
 					uid
@@ -167,11 +170,12 @@ func (cities *Cities) ReadCitiesByName(cityName, language string) ([]City, error
 				}
 			}`, language, cityName, language)
 
-	transaction := cities.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), query)
+	transaction := cities.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCitiesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCitiesByNameCanNotBeFound, err)
 	}
 
 	type citiesInStorage struct {
@@ -181,14 +185,16 @@ func (cities *Cities) ReadCitiesByName(cityName, language string) ([]City, error
 	var foundedCities citiesInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedCities)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCitiesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCitiesByNameCanNotBeFound, err)
 	}
 
 	if len(foundedCities.AllCitiesFoundedByName) == 0 {
-		return nil, ErrCitiesByNameNotFound
+		return nil, fail(ErrCitiesByNameNotFound)
 	}
 
+	cities.storage.remember(key, generation, foundedCities.AllCitiesFoundedByName, citiesTag)
+
 	return foundedCities.AllCitiesFoundedByName, nil
 }
 
@@ -204,11 +210,17 @@ var (
 )
 
 // ReadCityByID is a method for get all nodes of categories by ID
-func (cities *Cities) ReadCityByID(cityID, language string) (City, error) {
+func (cities *Cities) ReadCityByID(ctx context.Context, cityID, language string) (City, error) {
+	key := cacheKey("city", cityID, language)
+	cached, generation := cities.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.(City), nil
+	}
+
 	city := City{ID: cityID}
 
 	if cityID == "" {
-		return city, ErrCityCanNotBeWithoutID
+		return city, fail(ErrCityCanNotBeWithoutID)
 	}
 
 	query := fmt.Sprintf(`{
@@ -219,11 +231,12 @@ func (cities *Cities) ReadCityByID(cityID, language string) (City, error) {
 				}
 			}`, cityID, language)
 
-	transaction := cities.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), query)
+	transaction := cities.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
 	if err != nil {
-		log.Println(err)
-		return city, ErrCityByIDCanNotBeFound
+		logError(err)
+		return city, wrap(ErrCityByIDCanNotBeFound, err)
 	}
 
 	type citiesInStore struct {
@@ -234,25 +247,56 @@ func (cities *Cities) ReadCityByID(cityID, language string) (City, error) {
 
 	err = json.Unmarshal(response.GetJson(), &foundedCities)
 	if err != nil {
-		log.Println(err)
-		return city, ErrCityByIDCanNotBeFound
+		logError(err)
+		return city, wrap(ErrCityByIDCanNotBeFound, err)
 	}
 
 	if len(foundedCities.Cities) == 0 {
-		return city, ErrCityDoesNotExist
+		return city, fail(ErrCityDoesNotExist)
 	}
 
+	cities.storage.remember(key, generation, foundedCities.Cities[0])
+
 	return foundedCities.Cities[0], nil
 }
 
+// ErrCityCanNotBeDeactivate means that the city can't be deactivated in database
+var ErrCityCanNotBeDeactivate = errors.New("city can't be deactivate")
+
+// DeactivateCity method for exclude city from parse and search without removing it from database
+func (cities *Cities) DeactivateCity(ctx context.Context, city City) (string, error) {
+	defer cities.storage.invalidate(city.ID, citiesTag)
+
+	if city.ID == "" {
+		return "", fail(ErrCityCanNotBeWithoutID)
+	}
+
+	forCityPredicate := fmt.Sprintf(`<%s> <cityIsActive> "false" .`, city.ID)
+
+	mutation := dataBaseAPI.Mutation{
+		SetNquads: []byte(forCityPredicate),
+		CommitNow: true}
+
+	transaction := cities.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrCityCanNotBeDeactivate, err)
+	}
+
+	return city.ID, nil
+}
+
 // ErrCityCanNotBeDeleted means that the city can't be removed from database
 var ErrCityCanNotBeDeleted = errors.New("city can't be deleted")
 
 // DeleteCity method for remove category from database
-func (cities *Cities) DeleteCity(city City) (string, error) {
+func (cities *Cities) DeleteCity(ctx context.Context, city City) (string, error) {
+	defer cities.storage.invalidate(city.ID, citiesTag)
 
 	if city.ID == "" {
-		return "", ErrCityCanNotBeWithoutID
+		return "", fail(ErrCityCanNotBeWithoutID)
 	}
 
 	deleteCategoryData, _ := json.Marshal(map[string]string{"uid": city.ID})
@@ -261,14 +305,68 @@ func (cities *Cities) DeleteCity(city City) (string, error) {
 		DeleteJson: deleteCategoryData,
 		CommitNow:  true}
 
-	transaction := cities.storage.Client.NewTxn()
+	transaction := cities.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	var err error
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
-		return city.ID, ErrCityCanNotBeDeleted
+		logError(err)
+		return city.ID, wrap(ErrCityCanNotBeDeleted, err)
 	}
 
 	return city.ID, nil
 }
+
+// ExportedCity is a city in exported JSON, city is found by name on import
+type ExportedCity struct {
+	Name string `json:"cityName"`
+}
+
+type allExportedCities struct {
+	Language string         `json:"language"`
+	Cities   []ExportedCity `json:"cities"`
+}
+
+// ExportJSON is a method for export names of all active cities without uids of database
+func (cities *Cities) ExportJSON(ctx context.Context, language string) ([]byte, error) {
+	exportedCities := allExportedCities{Language: language, Cities: []ExportedCity{}}
+
+	citiesInStorage, err := cities.ReadAllCities(ctx, language)
+	if err != nil && !errors.Is(err, ErrCitiesByNameNotFound) {
+		return nil, err
+	}
+
+	for _, city := range citiesInStorage {
+		exportedCities.Cities = append(exportedCities.Cities, ExportedCity{Name: city.Name})
+	}
+
+	jsonForExport, err := json.Marshal(exportedCities)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonForExport, nil
+}
+
+// ImportJSON is a method for add exported cities which are not in database yet.
+// Cities are found by name, so import can be repeated.
+func (cities *Cities) ImportJSON(ctx context.Context, exportedCities []byte) error {
+	defer cities.storage.Cache.Purge()
+
+	var allCitiesInJSON allExportedCities
+
+	err := json.Unmarshal(exportedCities, &allCitiesInJSON)
+	if err != nil {
+		return err
+	}
+
+	for _, exportedCity := range allCitiesInJSON.Cities {
+		_, err = cities.CreateCity(ctx, City{Name: exportedCity.Name}, allCitiesInJSON.Language)
+		if err != nil && !errors.Is(err, ErrCityAlreadyExist) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -8,13 +10,13 @@ func TestIntegrationCityCanBeCreated(test *testing.T) {
 	once.Do(prepareStorage)
 
 	cityForCreate := City{Name: "Moscow"}
-	createdCity, err := storage.Cities.CreateCity(cityForCreate, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), cityForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Fail()
 		}
@@ -31,32 +33,32 @@ func TestIntegrationAllCitiesCanBeRead(test *testing.T) {
 	once.Do(prepareStorage)
 
 	cityForTest := City{Name: "Test city"}
-	createdCity, err := storage.Cities.CreateCity(cityForTest, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), cityForTest, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
 	otherCityForTest := City{Name: "Other test city"}
-	otherCreatedCity, err := storage.Cities.CreateCity(otherCityForTest, "en")
+	otherCreatedCity, err := storage.Cities.CreateCity(context.Background(), otherCityForTest, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(otherCreatedCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), otherCreatedCity)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	citiesFromStore, err := storage.Cities.ReadAllCities("en")
+	citiesFromStore, err := storage.Cities.ReadAllCities(context.Background(), "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -71,8 +73,8 @@ func TestIntegrationCitiesCanBeReadByName(test *testing.T) {
 
 	cityForTest := City{Name: "Test city"}
 
-	citiesFromStore, err := storage.Cities.ReadCitiesByName(cityForTest.Name, ".")
-	if err != ErrCitiesByNameNotFound {
+	citiesFromStore, err := storage.Cities.ReadCitiesByName(context.Background(), cityForTest.Name, ".")
+	if !errors.Is(err, ErrCitiesByNameNotFound) {
 		test.Fail()
 	}
 
@@ -80,19 +82,19 @@ func TestIntegrationCitiesCanBeReadByName(test *testing.T) {
 		test.Fail()
 	}
 
-	createdCity, err := storage.Cities.CreateCity(cityForTest, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), cityForTest, "en")
 	if err != nil || createdCity.ID == "" {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	citiesFromStore, err = storage.Cities.ReadCitiesByName(createdCity.Name, "en")
+	citiesFromStore, err = storage.Cities.ReadCitiesByName(context.Background(), createdCity.Name, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -113,26 +115,26 @@ func TestIntegrationCitiesCanBeReadByName(test *testing.T) {
 func TestIntegrationCityCanBeReadById(test *testing.T) {
 	once.Do(prepareStorage)
 
-	cityFromStore, err := storage.Cities.ReadCityByID("0", ".")
-	if err != ErrCityDoesNotExist {
+	cityFromStore, err := storage.Cities.ReadCityByID(context.Background(), "0", ".")
+	if !errors.Is(err, ErrCityDoesNotExist) {
 		test.Fail()
 	}
 
 	cityForCreate := City{Name: "Moscow"}
 
-	createdCity, err := storage.Cities.CreateCity(cityForCreate, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), cityForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	cityFromStore, err = storage.Cities.ReadCityByID(createdCity.ID, ".")
+	cityFromStore, err = storage.Cities.ReadCityByID(context.Background(), createdCity.ID, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -151,12 +153,12 @@ func TestIntegrationCityCanBeDeleted(test *testing.T) {
 
 	cityForCreate := City{Name: "Moscow"}
 
-	createdCity, err := storage.Cities.CreateCity(cityForCreate, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), cityForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	deletedCityID, err := storage.Cities.DeleteCity(createdCity)
+	deletedCityID, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 	if err != nil {
 		test.Error(err)
 	}
@@ -165,8 +167,8 @@ func TestIntegrationCityCanBeDeleted(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = storage.Cities.ReadCityByID(deletedCityID, ".")
-	if err != ErrCityDoesNotExist {
+	_, err = storage.Cities.ReadCityByID(context.Background(), deletedCityID, ".")
+	if !errors.Is(err, ErrCityDoesNotExist) {
 		test.Error(err)
 	}
 }
@@ -177,24 +179,24 @@ func TestIntegrationCityCanHasNameWithManyLanguages(test *testing.T) {
 	testCityName := "Test city"
 	testCityRuName := "Тестовый город"
 
-	createdCity, err := storage.Cities.CreateCity(City{Name: testCityName}, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: testCityName}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Cities.AddLanguageOfCityName(createdCity.ID, testCityRuName, "ru")
+	err = storage.Cities.AddLanguageOfCityName(context.Background(), createdCity.ID, testCityRuName, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
-	cityWithEnName, err := storage.Cities.ReadCityByID(createdCity.ID, "en")
+	cityWithEnName, err := storage.Cities.ReadCityByID(context.Background(), createdCity.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -203,7 +205,7 @@ func TestIntegrationCityCanHasNameWithManyLanguages(test *testing.T) {
 		test.Fail()
 	}
 
-	cityWithRuName, err := storage.Cities.ReadCityByID(createdCity.ID, "ru")
+	cityWithRuName, err := storage.Cities.ReadCityByID(context.Background(), createdCity.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -212,3 +214,68 @@ func TestIntegrationCityCanHasNameWithManyLanguages(test *testing.T) {
 		test.Fail()
 	}
 }
+
+func TestIntegrationCitiesCanBeExportedAndImportedManyTimes(test *testing.T) {
+	once.Do(prepareStorage)
+
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Exported city"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	exportedJSON, err := storage.Cities.ExportJSON(context.Background(), "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = storage.Cities.DeleteCity(context.Background(), createdCity)
+	if err != nil {
+		test.Error(err)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err = storage.Cities.ImportJSON(context.Background(), exportedJSON)
+		if err != nil {
+			test.Error(err)
+		}
+	}
+
+	importedCities, err := storage.Cities.ReadCitiesByName(context.Background(), "Exported city", "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(importedCities) != 1 {
+		test.Fail()
+	}
+
+	for _, city := range importedCities {
+		storage.Cities.DeleteCity(context.Background(), city)
+	}
+}
+
+func TestIntegrationCityCanBeDeactivated(test *testing.T) {
+	once.Do(prepareStorage)
+
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Deactivated city"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Cities.DeleteCity(context.Background(), createdCity)
+
+	_, err = storage.Cities.DeactivateCity(context.Background(), createdCity)
+	if err != nil {
+		test.Error(err)
+	}
+
+	_, err = storage.Cities.ReadCitiesByName(context.Background(), "Deactivated city", "en")
+	if !errors.Is(err, ErrCitiesByNameNotFound) {
+		test.Fail()
+	}
+
+	_, err = storage.Cities.DeactivateCity(context.Background(), City{})
+	if !errors.Is(err, ErrCityCanNotBeWithoutID) {
+		test.Fail()
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 
 	"bytes"
 	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
@@ -33,24 +32,6 @@ func NewCompaniesResourceForStorage(storage *Storage) *Companies {
 	return &Companies{storage: storage}
 }
 
-// SetUp is a method of Companies resource for prepare database client and schema.
-func (companies *Companies) SetUp() (err error) {
-	schema := `
-		companyName: string @lang @index(term) .
-		companyIsActive: bool @index(bool) .
-		has_category: uid @count .
-	`
-	operation := &dataBaseAPI.Operation{Schema: schema}
-
-	err = companies.storage.Client.Alter(context.Background(), operation)
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-
-	return nil
-}
-
 var (
 	// ErrCompaniesByNameNotFound means than the companies does not exist in database
 	ErrCompaniesByNameNotFound = errors.New("companies by name not found")
@@ -63,41 +44,44 @@ var (
 )
 
 // CreateCompany make category and save it to storage
-func (companies *Companies) CreateCompany(company Company, language string) (Company, error) {
-	existsCompanies, err := companies.ReadCompaniesByName(company.Name, language)
-	if err != nil && err != ErrCompaniesByNameNotFound {
-		log.Println(err)
-		return company, ErrCompanyCanNotBeCreated
+func (companies *Companies) CreateCompany(ctx context.Context, company Company, language string) (Company, error) {
+	defer companies.storage.invalidate(companiesTag)
+
+	existsCompanies, err := companies.ReadCompaniesByName(ctx, company.Name, language)
+	if err != nil && !errors.Is(err, ErrCompaniesByNameNotFound) {
+		logError(err)
+		return company, wrap(ErrCompanyCanNotBeCreated, err)
 	}
 	if existsCompanies != nil {
-		return existsCompanies[0], ErrCompanyAlreadyExist
+		return existsCompanies[0], fail(ErrCompanyAlreadyExist)
 	}
 
-	transaction := companies.storage.Client.NewTxn()
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	company.IsActive = true
 	encodedCompany, err := json.Marshal(company)
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyCanNotBeCreated
+		logError(err)
+		return company, wrap(ErrCompanyCanNotBeCreated, err)
 	}
 
 	mutation := &dataBaseAPI.Mutation{
 		SetJson:   encodedCompany,
 		CommitNow: true}
 
-	assigned, err := transaction.Mutate(context.Background(), mutation)
+	assigned, err := transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyCanNotBeCreated
+		logError(err)
+		return company, wrap(ErrCompanyCanNotBeCreated, err)
 	}
 
 	company.ID = assigned.Uids["blank-0"]
 	if company.ID == "" {
-		return company, ErrCompanyCanNotBeCreated
+		return company, fail(ErrCompanyCanNotBeCreated)
 	}
 
-	err = companies.AddLanguageOfCompanyName(company.ID, company.Name, language)
+	err = companies.AddLanguageOfCompanyName(ctx, company.ID, company.Name, language)
 	if err != nil {
 		return company, err
 	}
@@ -106,15 +90,18 @@ func (companies *Companies) CreateCompany(company Company, language string) (Com
 }
 
 // AddLanguageOfCompanyName is a method for add predicate "companyName" for companyName value with new language
-func (companies *Companies) AddLanguageOfCompanyName(companyID, name, language string) error {
+func (companies *Companies) AddLanguageOfCompanyName(ctx context.Context, companyID, name, language string) error {
+	defer companies.storage.invalidate(companyID, companiesTag)
+
 	forCompanyNamePredicate := fmt.Sprintf(`<%s> <companyName> %s .`, companyID, "\""+name+"\""+"@"+language)
 
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(forCompanyNamePredicate),
 		CommitNow: true}
 
-	transaction := companies.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
 		return err
 	}
@@ -128,7 +115,13 @@ var (
 )
 
 // ReadAllCompanies is a method for get all nodes
-func (companies *Companies) ReadAllCompanies(language string) ([]Company, error) {
+func (companies *Companies) ReadAllCompanies(ctx context.Context, language string) ([]Company, error) {
+	key := cacheKey("companies", language)
+	cached, generation := companies.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.([]Company), nil
+	}
+
 	query := fmt.Sprintf(`{
 				companies(func: eq(companyIsActive, true)) @filter(has(companyName)) {
 					uid
@@ -143,11 +136,12 @@ func (companies *Companies) ReadAllCompanies(language string) ([]Company, error)
 				}
 			}`, language, language)
 
-	transaction := companies.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), query)
+	transaction := companies.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCompaniesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCompaniesByNameCanNotBeFound, err)
 	}
 
 	type companiesInStorage struct {
@@ -157,19 +151,27 @@ func (companies *Companies) ReadAllCompanies(language string) ([]Company, error)
 	var foundedCompanies companiesInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedCompanies)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCompaniesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCompaniesByNameCanNotBeFound, err)
 	}
 
 	if len(foundedCompanies.AllCompaniesFoundedByName) == 0 {
-		return nil, ErrCompaniesByNameNotFound
+		return nil, fail(ErrCompaniesByNameNotFound)
 	}
 
+	companies.storage.remember(key, generation, foundedCompanies.AllCompaniesFoundedByName, companiesTag)
+
 	return foundedCompanies.AllCompaniesFoundedByName, nil
 }
 
 // ReadCompaniesByName is a method for get all nodes by categories name
-func (companies *Companies) ReadCompaniesByName(companyName, language string) ([]Company, error) {
+func (companies *Companies) ReadCompaniesByName(ctx context.Context, companyName, language string) ([]Company, error) {
+	key := cacheKey("companies-by-name", language, companyName)
+	cached, generation := companies.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.([]Company), nil
+	}
+
 	variables := struct {
 		CompanyName string
 		Language    string
@@ -216,8 +218,10 @@ func (companies *Companies) ReadCompaniesByName(companyName, language string) ([
 							has_price @filter(eq(priceIsActive, true)) {
 								uid
 								priceValue
+								priceAmount
+								priceCurrency
 								priceDateTime
-								priceCity
+								priceLastSeenAt
 								priceIsActive
 								belongs_to_product @filter(eq(productIsActive, true)) {
 									uid
@@ -229,7 +233,6 @@ func (companies *Companies) ReadCompaniesByName(companyName, language string) ([
 										uid
 										priceValue
 										priceDateTime
-										priceCity
 										priceIsActive
 									}
 								}
@@ -238,6 +241,12 @@ func (companies *Companies) ReadCompaniesByName(companyName, language string) ([
 									cityName: cityName@{{.Language}}
 									cityIsActive
 								}
+								belongs_to_company {
+									uid
+									companyName: companyName@{{.Language}}
+									companyIri
+									companyIsActive
+								}
 							}
 						}
 					}
@@ -245,22 +254,23 @@ func (companies *Companies) ReadCompaniesByName(companyName, language string) ([
 			}`)
 
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCompaniesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCompaniesByNameCanNotBeFound, err)
 	}
 
 	queryBuf := bytes.Buffer{}
 	err = queryTemplate.Execute(&queryBuf, variables)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
-	transaction := companies.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := companies.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCompaniesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCompaniesByNameCanNotBeFound, err)
 	}
 
 	type companiesInStorage struct {
@@ -270,14 +280,16 @@ func (companies *Companies) ReadCompaniesByName(companyName, language string) ([
 	var foundedCompanies companiesInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedCompanies)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrCompaniesByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrCompaniesByNameCanNotBeFound, err)
 	}
 
 	if len(foundedCompanies.AllCompaniesFoundedByName) == 0 {
-		return nil, ErrCompaniesByNameNotFound
+		return nil, fail(ErrCompaniesByNameNotFound)
 	}
 
+	companies.storage.remember(key, generation, foundedCompanies.AllCompaniesFoundedByName, companiesTag)
+
 	return foundedCompanies.AllCompaniesFoundedByName, nil
 }
 
@@ -293,11 +305,17 @@ var (
 )
 
 // ReadCompanyByID is a method for get all nodes of categories by ID
-func (companies *Companies) ReadCompanyByID(companyID, language string) (Company, error) {
+func (companies *Companies) ReadCompanyByID(ctx context.Context, companyID, language string) (Company, error) {
+	key := cacheKey("company", companyID, language)
+	cached, generation := companies.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.(Company), nil
+	}
+
 	company := Company{ID: companyID}
 
 	if companyID == "" {
-		return company, ErrCompanyCanNotBeWithoutID
+		return company, fail(ErrCompanyCanNotBeWithoutID)
 	}
 
 	variables := struct {
@@ -346,8 +364,10 @@ func (companies *Companies) ReadCompanyByID(companyID, language string) (Company
 							has_price @filter(eq(priceIsActive, true)) {
 								uid
 								priceValue
+								priceAmount
+								priceCurrency
 								priceDateTime
-								priceCity
+								priceLastSeenAt
 								priceIsActive
 								belongs_to_product @filter(eq(productIsActive, true)) {
 									uid
@@ -359,7 +379,6 @@ func (companies *Companies) ReadCompanyByID(companyID, language string) (Company
 										uid
 										priceValue
 										priceDateTime
-										priceCity
 										priceIsActive
 									}
 								}
@@ -368,6 +387,12 @@ func (companies *Companies) ReadCompanyByID(companyID, language string) (Company
 									cityName: cityName@{{.Language}}
 									cityIsActive
 								}
+								belongs_to_company {
+									uid
+									companyName: companyName@{{.Language}}
+									companyIri
+									companyIsActive
+								}
 							}
 						}
 					}
@@ -375,22 +400,23 @@ func (companies *Companies) ReadCompanyByID(companyID, language string) (Company
 			}`)
 
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyByIDCanNotBeFound
+		logError(err)
+		return company, wrap(ErrCompanyByIDCanNotBeFound, err)
 	}
 
 	queryBuf := bytes.Buffer{}
 	err = queryTemplate.Execute(&queryBuf, variables)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return company, err
 	}
 
-	transaction := companies.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := companies.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyByIDCanNotBeFound
+		logError(err)
+		return company, wrap(ErrCompanyByIDCanNotBeFound, err)
 	}
 
 	type companiesInStore struct {
@@ -401,14 +427,16 @@ func (companies *Companies) ReadCompanyByID(companyID, language string) (Company
 
 	err = json.Unmarshal(response.GetJson(), &foundedCompanies)
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyByIDCanNotBeFound
+		logError(err)
+		return company, wrap(ErrCompanyByIDCanNotBeFound, err)
 	}
 
 	if len(foundedCompanies.Companies) == 0 {
-		return company, ErrCompanyDoesNotExist
+		return company, fail(ErrCompanyDoesNotExist)
 	}
 
+	companies.storage.remember(key, generation, foundedCompanies.Companies[0])
+
 	return foundedCompanies.Companies[0], nil
 }
 
@@ -418,32 +446,35 @@ var (
 )
 
 // UpdateCompany method for change company in storage
-func (companies *Companies) UpdateCompany(company Company) (Company, error) {
+func (companies *Companies) UpdateCompany(ctx context.Context, company Company) (Company, error) {
+	defer companies.storage.invalidate(company.ID, companiesTag)
+
 	if company.ID == "" {
-		return company, ErrCompanyCanNotBeWithoutID
+		return company, fail(ErrCompanyCanNotBeWithoutID)
 	}
 
 	encodedCompany, err := json.Marshal(company)
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyCanNotBeUpdated
+		logError(err)
+		return company, wrap(ErrCompanyCanNotBeUpdated, err)
 	}
 
 	mutation := dataBaseAPI.Mutation{
 		SetJson:   encodedCompany,
 		CommitNow: true}
 
-	transaction := companies.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyCanNotBeUpdated
+		logError(err)
+		return company, wrap(ErrCompanyCanNotBeUpdated, err)
 	}
 
-	updatedCompany, err := companies.ReadCompanyByID(company.ID, ".")
+	updatedCompany, err := companies.ReadCompanyByID(ctx, company.ID, ".")
 	if err != nil {
-		log.Println(err)
-		return company, ErrCompanyCanNotBeUpdated
+		logError(err)
+		return company, wrap(ErrCompanyCanNotBeUpdated, err)
 	}
 
 	return updatedCompany, nil
@@ -455,17 +486,19 @@ var (
 )
 
 // DeactivateCompany method for remove categories from database
-func (companies *Companies) DeactivateCompany(company Company) (string, error) {
+func (companies *Companies) DeactivateCompany(ctx context.Context, company Company) (string, error) {
+	defer companies.storage.invalidate(company.ID, companiesTag)
+
 	if company.ID == "" {
-		return "", ErrCompanyCanNotBeWithoutID
+		return "", fail(ErrCompanyCanNotBeWithoutID)
 	}
 
 	company.IsActive = false
 
 	encodedCompany, err := json.Marshal(company)
 	if err != nil {
-		log.Println(err)
-		return company.ID, ErrCompanyCanNotBeDeactivate
+		logError(err)
+		return company.ID, wrap(ErrCompanyCanNotBeDeactivate, err)
 	}
 
 	mutation := dataBaseAPI.Mutation{
@@ -473,12 +506,13 @@ func (companies *Companies) DeactivateCompany(company Company) (string, error) {
 		CommitNow:           true,
 		IgnoreIndexConflict: true}
 
-	transaction := companies.storage.Client.NewTxn()
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
-		return company.ID, ErrCompanyCanNotBeDeactivate
+		logError(err)
+		return company.ID, wrap(ErrCompanyCanNotBeDeactivate, err)
 	}
 
 	return company.ID, nil
@@ -493,10 +527,11 @@ var (
 )
 
 // DeleteCompany method for remove company from database
-func (companies *Companies) DeleteCompany(company Company) (string, error) {
+func (companies *Companies) DeleteCompany(ctx context.Context, company Company) (string, error) {
+	defer companies.storage.invalidate(company.ID, companiesTag)
 
 	if company.ID == "" {
-		return "", ErrCompanyCanNotBeWithoutID
+		return "", fail(ErrCompanyCanNotBeWithoutID)
 	}
 
 	deleteCompanyData, _ := json.Marshal(map[string]string{"uid": company.ID})
@@ -505,20 +540,23 @@ func (companies *Companies) DeleteCompany(company Company) (string, error) {
 		DeleteJson: deleteCompanyData,
 		CommitNow:  true}
 
-	transaction := companies.storage.Client.NewTxn()
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	var err error
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
-		return company.ID, ErrCompanyCanNotBeDeleted
+		logError(err)
+		return company.ID, wrap(ErrCompanyCanNotBeDeleted, err)
 	}
 
 	return company.ID, nil
 }
 
 // AddCategoryToCompany method for set quad of predicate about company and category
-func (companies *Companies) AddCategoryToCompany(companyID, categoryID string) error {
+func (companies *Companies) AddCategoryToCompany(ctx context.Context, companyID, categoryID string) error {
+	defer companies.storage.invalidate(companyID, categoryID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -527,10 +565,11 @@ func (companies *Companies) AddCategoryToCompany(companyID, categoryID string) e
 		SetNquads: []byte(forCategoryPredicate),
 		CommitNow: true}
 
-	transaction := companies.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCompanyCanNotBeAddedToCategory
+		return wrap(ErrCompanyCanNotBeAddedToCategory, err)
 	}
 
 	forCompanyPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, companyID, "has_category", categoryID)
@@ -539,10 +578,11 @@ func (companies *Companies) AddCategoryToCompany(companyID, categoryID string) e
 		SetNquads: []byte(forCompanyPredicate),
 		CommitNow: true}
 
-	transaction = companies.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCategoryCanNotBeAddedToCompany
+		return wrap(ErrCategoryCanNotBeAddedToCompany, err)
 	}
 
 	return nil
@@ -554,7 +594,9 @@ var (
 )
 
 // RemoveCategoryFromCompany method for delete quad of predicate about company and category
-func (companies *Companies) RemoveCategoryFromCompany(companyID, categoryID string) error {
+func (companies *Companies) RemoveCategoryFromCompany(ctx context.Context, companyID, categoryID string) error {
+	defer companies.storage.invalidate(companyID, categoryID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -563,10 +605,11 @@ func (companies *Companies) RemoveCategoryFromCompany(companyID, categoryID stri
 		DelNquads: []byte(forCategoryPredicate),
 		CommitNow: true}
 
-	transaction := companies.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCompanyCanNotBeRemovedFromCategory
+		return wrap(ErrCompanyCanNotBeRemovedFromCategory, err)
 	}
 
 	forCompanyPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, companyID, "has_category", categoryID)
@@ -575,10 +618,11 @@ func (companies *Companies) RemoveCategoryFromCompany(companyID, categoryID stri
 		DelNquads: []byte(forCompanyPredicate),
 		CommitNow: true}
 
-	transaction = companies.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCategoryCanNotBeRemovedFromCompany
+		return wrap(ErrCategoryCanNotBeRemovedFromCompany, err)
 	}
 
 	return nil
@@ -590,7 +634,9 @@ var (
 )
 
 // AddProductToCompany method for set quad of predicate about company and product
-func (companies *Companies) AddProductToCompany(companyID, productID string) error {
+func (companies *Companies) AddProductToCompany(ctx context.Context, companyID, productID string) error {
+	defer companies.storage.invalidate(companyID, productID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -599,10 +645,11 @@ func (companies *Companies) AddProductToCompany(companyID, productID string) err
 		SetNquads: []byte(forProductPredicate),
 		CommitNow: true}
 
-	transaction := companies.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := companies.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrProductCanNotBeAddedToCompany
+		return wrap(ErrProductCanNotBeAddedToCompany, err)
 	}
 
 	return nil
@@ -614,172 +661,120 @@ type allExportedCompanies struct {
 }
 
 // ImportJSON is a method for add companies, categories of companies, products of categories,
-// prices of products and cities of prices to database.
-func (companies *Companies) ImportJSON(exportedCompanies []byte) error {
+// prices of products and cities of prices to database and update offers by prices.
+// Exported uids are not written to database, nodes are found by natural keys or made as new ones,
+// so JSON exported from other database can be imported many times.
+func (companies *Companies) ImportJSON(ctx context.Context, exportedCompanies []byte) (ImportReport, error) {
+	defer companies.storage.Cache.Purge()
 
 	var allCompaniesInJSON allExportedCompanies
 
 	err := json.Unmarshal(exportedCompanies, &allCompaniesInJSON)
 	if err != nil {
-		return err
+		return ImportReport{}, err
 	}
 
-	language := allCompaniesInJSON.Language
+	mapping := newImportMapping(companies.storage, allCompaniesInJSON.Language)
 
 	for _, exportedCompany := range allCompaniesInJSON.Companies {
-
-		encodedCompany, err := json.Marshal(exportedCompany)
+		companyID, err := mapping.company(ctx, exportedCompany)
 		if err != nil {
-			log.Println(err)
-			return err
-		}
-
-		mutation := &dataBaseAPI.Mutation{
-			SetJson:   encodedCompany,
-			CommitNow: true}
-
-		transaction := companies.storage.Client.NewTxn()
-		_, err = transaction.Mutate(context.Background(), mutation)
-		if err != nil {
-			log.Println(err)
-			return err
-		}
-
-		err = companies.AddLanguageOfCompanyName(exportedCompany.ID, exportedCompany.Name, language)
-		if err != nil {
-			return err
+			return mapping.report, err
 		}
 
 		for _, exportedCategory := range exportedCompany.Categories {
-			encodedCategory, err := json.Marshal(exportedCategory)
+			categoryID, err := mapping.category(ctx, exportedCategory)
 			if err != nil {
-				log.Println(err)
-				return err
+				return mapping.report, err
 			}
 
-			mutation := &dataBaseAPI.Mutation{
-				SetJson:   encodedCategory,
-				CommitNow: true}
-
-			transaction := companies.storage.Client.NewTxn()
-			_, err = transaction.Mutate(context.Background(), mutation)
+			err = mapping.link(ctx, companyID, "has_category", categoryID)
 			if err != nil {
-				log.Println(err)
-				return err
+				return mapping.report, err
 			}
 
-			err = companies.storage.Categories.AddLanguageOfCategoryName(exportedCategory.ID, exportedCategory.Name, language)
+			err = mapping.link(ctx, categoryID, "belongs_to_company", companyID)
 			if err != nil {
-				log.Println(err)
-				return err
-			}
-
-			err = companies.storage.Categories.AddCompanyToCategory(exportedCategory.ID, exportedCompany.ID)
-			if err != nil {
-				return err
+				return mapping.report, err
 			}
 
 			for _, exportedProduct := range exportedCategory.Products {
-				encodedProduct, err := json.Marshal(exportedProduct)
+				productID, err := mapping.product(ctx, exportedProduct)
 				if err != nil {
-					log.Println(err)
-					return err
+					return mapping.report, err
 				}
 
-				mutation := &dataBaseAPI.Mutation{
-					SetJson:   encodedProduct,
-					CommitNow: true}
-
-				transaction := companies.storage.Client.NewTxn()
-				_, err = transaction.Mutate(context.Background(), mutation)
+				err = mapping.link(ctx, categoryID, "has_product", productID)
 				if err != nil {
-					log.Println(err)
-					return err
+					return mapping.report, err
 				}
 
-				err = companies.storage.Products.AddLanguageOfProductName(exportedProduct.ID, exportedProduct.Name, language)
+				err = mapping.link(ctx, productID, "belongs_to_category", categoryID, "belongs_to_company", companyID)
 				if err != nil {
-					return err
-				}
-
-				err = companies.storage.Products.AddCategoryToProduct(exportedProduct.ID, exportedCategory.ID)
-				if err != nil {
-					return err
-				}
-
-				err = companies.storage.Products.AddCompanyToProduct(exportedProduct.ID, exportedCompany.ID)
-				if err != nil {
-					return err
+					return mapping.report, err
 				}
 
 				for _, exportedPrice := range exportedProduct.Prices {
-					encodedPrice, err := json.Marshal(exportedPrice)
-					if err != nil {
-						log.Println(err)
-						return err
+					cityID := ""
+					if len(exportedPrice.Cities) > 0 {
+						cityID, err = mapping.city(ctx, exportedPrice.Cities[0])
+						if err != nil {
+							return mapping.report, err
+						}
 					}
 
-					mutation := &dataBaseAPI.Mutation{
-						SetJson:   encodedPrice,
-						CommitNow: true}
+					// Product can be sold by many companies, so price has its own company,
+					// prices exported without company belong to company of category
+					priceCompanyID := companyID
+					if len(exportedPrice.Companies) > 0 {
+						priceCompanyID, err = mapping.company(ctx, exportedPrice.Companies[0])
+						if err != nil {
+							return mapping.report, err
+						}
+					}
 
-					transaction := companies.storage.Client.NewTxn()
-					_, err = transaction.Mutate(context.Background(), mutation)
+					priceID, err := mapping.price(ctx, exportedPrice, productID, priceCompanyID, cityID)
 					if err != nil {
-						log.Println(err)
-						return err
+						return mapping.report, err
 					}
 
-					err = companies.storage.Products.AddPriceToProduct(exportedProduct.ID, exportedPrice.ID)
+					err = mapping.link(ctx, productID, "has_price", priceID)
 					if err != nil {
-						log.Println(err)
-						return err
+						return mapping.report, err
 					}
 
-					for _, exportedCity := range exportedPrice.Cities {
-						encodedCity, err := json.Marshal(exportedCity)
-						if err != nil {
-							log.Println(err)
-							return err
-						}
-
-						mutation := &dataBaseAPI.Mutation{
-							SetJson:   encodedCity,
-							CommitNow: true}
-
-						transaction := companies.storage.Client.NewTxn()
-						_, err = transaction.Mutate(context.Background(), mutation)
-						if err != nil {
-							log.Println(err)
-							return err
-						}
+					err = mapping.link(ctx, priceID,
+						"belongs_to_product", productID, "belongs_to_city", cityID, "belongs_to_company", priceCompanyID)
+					if err != nil {
+						return mapping.report, err
+					}
 
-						err = companies.storage.Cities.AddLanguageOfCityName(exportedCity.ID, exportedCity.Name, language)
-						if err != nil {
-							return err
-						}
+					err = mapping.offer(ctx, exportedPrice, productID, priceCompanyID, cityID)
+					if err != nil {
+						return mapping.report, err
 					}
 				}
 			}
 		}
 	}
 
-	return nil
+	return mapping.report, nil
 }
 
 // ExportJSON is a method for export companies, categories of companies, products of categories,
 // prices of products and cities of prices to database.
-func (companies *Companies) ExportJSON(language string) ([]byte, error) {
+func (companies *Companies) ExportJSON(ctx context.Context, language string) ([]byte, error) {
 	query := fmt.Sprintf(`{
 				companies(func: has(companyName)) {
 					uid
 				}
 			}`)
 
-	transaction := companies.storage.Client.NewTxn()
-	responseWithCompaniesIDs, err := transaction.Query(context.Background(), query)
+	transaction := companies.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	responseWithCompaniesIDs, err := transaction.Query(ctx, query)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
@@ -791,7 +786,7 @@ func (companies *Companies) ExportJSON(language string) ([]byte, error) {
 
 	err = json.Unmarshal(responseWithCompaniesIDs.GetJson(), &allCompaniesIDs)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
@@ -803,9 +798,9 @@ func (companies *Companies) ExportJSON(language string) ([]byte, error) {
 	foundedCompanies := allExportedCompanies{Language: language}
 
 	for _, companyID := range allCompaniesIDs.CompaniesWithIDOnly {
-		company, err := companies.ReadCompanyByID(companyID.ID, language)
+		company, err := companies.ReadCompanyByID(ctx, companyID.ID, language)
 		if err != nil {
-			log.Println(err)
+			logError(err)
 			return nil, err
 		}
 
@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 )
@@ -11,13 +13,13 @@ func TestIntegrationCompanyCanBeCreated(test *testing.T) {
 
 	companyForTest := Company{Name: "Test company"}
 
-	createdCompany, err := storage.Companies.CreateCompany(companyForTest, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), companyForTest, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Fail()
 		}
@@ -42,32 +44,32 @@ func TestIntegrationCompaniesAllCanBeRead(test *testing.T) {
 	once.Do(prepareStorage)
 
 	companyForTest := Company{Name: "Test company"}
-	createdCompany, err := storage.Companies.CreateCompany(companyForTest, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), companyForTest, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
 	otherCompanyForTest := Company{Name: "Other test company"}
-	otherCreatedCompany, err := storage.Companies.CreateCompany(otherCompanyForTest, "en")
+	otherCreatedCompany, err := storage.Companies.CreateCompany(context.Background(), otherCompanyForTest, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(otherCreatedCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), otherCreatedCompany)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	companiesFromStore, err := storage.Companies.ReadAllCompanies("en")
+	companiesFromStore, err := storage.Companies.ReadAllCompanies(context.Background(), "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -82,8 +84,8 @@ func TestIntegrationCompanyCanBeReadByName(test *testing.T) {
 
 	companyForTest := Company{Name: "Test company"}
 
-	companiesFromStore, err := storage.Companies.ReadCompaniesByName(companyForTest.Name, ".")
-	if err != ErrCompaniesByNameNotFound {
+	companiesFromStore, err := storage.Companies.ReadCompaniesByName(context.Background(), companyForTest.Name, ".")
+	if !errors.Is(err, ErrCompaniesByNameNotFound) {
 		test.Fail()
 	}
 
@@ -91,19 +93,19 @@ func TestIntegrationCompanyCanBeReadByName(test *testing.T) {
 		test.Fail()
 	}
 
-	createdCompany, err := storage.Companies.CreateCompany(companyForTest, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), companyForTest, "en")
 	if err != nil || createdCompany.ID == "" {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	companiesFromStore, err = storage.Companies.ReadCompaniesByName(createdCompany.Name, "en")
+	companiesFromStore, err = storage.Companies.ReadCompaniesByName(context.Background(), createdCompany.Name, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -126,24 +128,24 @@ func TestIntegrationCompanyCanBeReadById(test *testing.T) {
 
 	companyForSearch := Company{Name: "Test category"}
 
-	companyFromStore, err := storage.Companies.ReadCompanyByID("0", ".")
-	if err != ErrCompanyDoesNotExist {
+	companyFromStore, err := storage.Companies.ReadCompanyByID(context.Background(), "0", ".")
+	if !errors.Is(err, ErrCompanyDoesNotExist) {
 		test.Fail()
 	}
 
-	createdCompany, err := storage.Companies.CreateCompany(companyForSearch, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), companyForSearch, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	companyFromStore, err = storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	companyFromStore, err = storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -160,28 +162,28 @@ func TestIntegrationCompanyCanBeReadById(test *testing.T) {
 func TestIntegrationCompanyCanBeUpdated(test *testing.T) {
 	once.Do(prepareStorage)
 
-	updatedCompany, err := storage.Companies.UpdateCompany(Company{Name: "Updated test company"})
+	updatedCompany, err := storage.Companies.UpdateCompany(context.Background(), Company{Name: "Updated test company"})
 	if err != nil {
-		if err != ErrCompanyCanNotBeWithoutID {
+		if !errors.Is(err, ErrCompanyCanNotBeWithoutID) {
 			test.Error(err)
 		}
 	}
 
 	companyForCreate := Company{Name: "Test company"}
-	createdCompany, err := storage.Companies.CreateCompany(companyForCreate, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), companyForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
 	companyForUpdate := Company{ID: createdCompany.ID, Name: "Updated test company", IsActive: createdCompany.IsActive}
-	updatedCompany, err = storage.Companies.UpdateCompany(companyForUpdate)
+	updatedCompany, err = storage.Companies.UpdateCompany(context.Background(), companyForUpdate)
 	if err != nil {
 		test.Error(err)
 	}
@@ -194,7 +196,7 @@ func TestIntegrationCompanyCanBeUpdated(test *testing.T) {
 		test.Fail()
 	}
 
-	companyInStore, err := storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	companyInStore, err := storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -212,21 +214,21 @@ func TestIntegrationCompanyCanBeDeactivate(test *testing.T) {
 	once.Do(prepareStorage)
 
 	companyForTest := Company{Name: "Test company"}
-	createdCompany, err := storage.Companies.CreateCompany(companyForTest, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), companyForTest, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	deactivatedCompanyID, err := storage.Companies.DeactivateCompany(createdCompany)
+	deactivatedCompanyID, err := storage.Companies.DeactivateCompany(context.Background(), createdCompany)
 	if err != nil {
-		if err != ErrCompanyCanNotBeWithoutID {
+		if !errors.Is(err, ErrCompanyCanNotBeWithoutID) {
 			test.Error(err)
 		}
 	}
@@ -235,7 +237,7 @@ func TestIntegrationCompanyCanBeDeactivate(test *testing.T) {
 		test.Fail()
 	}
 
-	deactivatedCompany, err := storage.Companies.ReadCompanyByID(deactivatedCompanyID, ".")
+	deactivatedCompany, err := storage.Companies.ReadCompanyByID(context.Background(), deactivatedCompanyID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -251,14 +253,14 @@ func TestIntegrationCompanyCanBeDeleted(test *testing.T) {
 	var err error
 
 	companyForTest := Company{Name: "Test company"}
-	createdCompany, err := storage.Companies.CreateCompany(companyForTest, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), companyForTest, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	deletedCompanyID, err := storage.Companies.DeleteCompany(createdCompany)
+	deletedCompanyID, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 	if err != nil {
-		if err != ErrCompanyCanNotBeWithoutID {
+		if !errors.Is(err, ErrCompanyCanNotBeWithoutID) {
 			test.Error(err)
 		}
 	}
@@ -267,8 +269,8 @@ func TestIntegrationCompanyCanBeDeleted(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = storage.Companies.ReadCompanyByID(deletedCompanyID, ".")
-	if err != ErrCompanyDoesNotExist {
+	_, err = storage.Companies.ReadCompanyByID(context.Background(), deletedCompanyID, ".")
+	if !errors.Is(err, ErrCompanyDoesNotExist) {
 		test.Error(err)
 	}
 }
@@ -278,14 +280,14 @@ func TestIntegrationCategoryCanBeAddedToCompany(test *testing.T) {
 
 	var err error
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
 
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
@@ -293,24 +295,24 @@ func TestIntegrationCategoryCanBeAddedToCompany(test *testing.T) {
 	}()
 
 	createdFirstCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "First test category for company"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "First test category for company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdFirstCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdFirstCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(createdCompany.ID, createdFirstCategory.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), createdCompany.ID, createdFirstCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCompany, err := storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	updatedCompany, err := storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -328,24 +330,24 @@ func TestIntegrationCategoryCanBeAddedToCompany(test *testing.T) {
 	}
 
 	createdSecondCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "Second test category for company"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "Second test category for company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdSecondCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdSecondCategory)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(createdCompany.ID, createdSecondCategory.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), createdCompany.ID, createdSecondCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCompany, err = storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	updatedCompany, err = storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -372,55 +374,55 @@ func TestIntegrationCategoryCanBeRemovedFromCompany(test *testing.T) {
 
 	var err error
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	createdFirstCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "First test category for company"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "First test category for company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdFirstCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdFirstCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(createdCompany.ID, createdFirstCategory.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), createdCompany.ID, createdFirstCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
 	createdSecondCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "Second test category for company"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "Second test category for company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdSecondCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdSecondCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(createdCompany.ID, createdSecondCategory.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), createdCompany.ID, createdSecondCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCompany, err := storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	updatedCompany, err := storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -433,12 +435,12 @@ func TestIntegrationCategoryCanBeRemovedFromCompany(test *testing.T) {
 		test.Fail()
 	}
 
-	err = storage.Companies.RemoveCategoryFromCompany(createdCompany.ID, createdFirstCategory.ID)
+	err = storage.Companies.RemoveCategoryFromCompany(context.Background(), createdCompany.ID, createdFirstCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCompany, err = storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	updatedCompany, err = storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -457,24 +459,24 @@ func TestIntegrationCompanyCanHasNameWithManyLanguages(test *testing.T) {
 
 	var err error
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddLanguageOfCompanyName(createdCompany.ID, "Тестовая компания", "ru")
+	err = storage.Companies.AddLanguageOfCompanyName(context.Background(), createdCompany.ID, "Тестовая компания", "ru")
 	if err != nil {
 		test.Fail()
 	}
 
-	companyWithEnName, err := storage.Companies.ReadCompanyByID(createdCompany.ID, "en")
+	companyWithEnName, err := storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -483,7 +485,7 @@ func TestIntegrationCompanyCanHasNameWithManyLanguages(test *testing.T) {
 		test.Fail()
 	}
 
-	companyWithRuName, err := storage.Companies.ReadCompanyByID(createdCompany.ID, "ru")
+	companyWithRuName, err := storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -498,59 +500,59 @@ func TestIntegrationProductCanBeAddedToCompany(test *testing.T) {
 
 	var err error
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
 	createdCategory, err :=
-		storage.Categories.CreateCategory(Category{Name: "Test category for company"}, "en")
+		storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category for company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(createdCompany.ID, createdCategory.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), createdCompany.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdProductForCompany, err := storage.Products.CreateProduct(Product{Name: "Test product for company"}, "en")
+	createdProductForCompany, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product for company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProductForCompany)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProductForCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddProductToCategory(createdCategory.ID, createdProductForCompany.ID)
+	err = storage.Categories.AddProductToCategory(context.Background(), createdCategory.ID, createdProductForCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = storage.Companies.AddProductToCompany(createdCompany.ID, createdProductForCompany.ID)
+	err = storage.Companies.AddProductToCompany(context.Background(), createdCompany.ID, createdProductForCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCompany, err := storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	updatedCompany, err := storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -571,24 +573,24 @@ func TestIntegrationProductCanBeAddedToCompany(test *testing.T) {
 		test.Fail()
 	}
 
-	createdProductForCategory, err := storage.Products.CreateProduct(Product{Name: "Test product for category"}, "en")
+	createdProductForCategory, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product for category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProductForCategory)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProductForCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Categories.AddProductToCategory(createdCategory.ID, createdProductForCategory.ID)
+	err = storage.Categories.AddProductToCategory(context.Background(), createdCategory.ID, createdProductForCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCompany, err = storage.Companies.ReadCompanyByID(createdCompany.ID, ".")
+	updatedCompany, err = storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
@@ -605,53 +607,53 @@ func TestIntegrationProductCanBeAddedToCompany(test *testing.T) {
 func TestIntegrationCompaniesCanBeAddedFromExportedJSON(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdCategory, err := storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(createdCompany.ID, createdCategory.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), createdCompany.ID, createdCategory.ID)
 	if err != nil {
 		test.Fail()
 	}
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddCompanyToProduct(createdProduct.ID, createdCompany.ID)
+	err = storage.Products.AddCompanyToProduct(context.Background(), createdProduct.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = storage.Products.AddCategoryToProduct(createdProduct.ID, createdCategory.ID)
+	err = storage.Products.AddCategoryToProduct(context.Background(), createdProduct.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
@@ -662,41 +664,41 @@ func TestIntegrationCompaniesCanBeAddedFromExportedJSON(test *testing.T) {
 		test.Error(err)
 	}
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: 132.3, DateTime: priceData})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 132.3, DateTime: priceData})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddPriceToProduct(createdProduct.ID, createdPrice.ID)
+	err = storage.Products.AddPriceToProduct(context.Background(), createdProduct.ID, createdPrice.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdCity, err := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err = storage.Cities.DeleteCity(createdCity)
+		_, err = storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Prices.AddCityToPrice(createdPrice.ID, createdCity.ID)
+	err = storage.Prices.AddCityToPrice(context.Background(), createdPrice.ID, createdCity.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedCompany, err := storage.Companies.ReadCompanyByID(createdCompany.ID, "en")
+	updatedCompany, err := storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -709,38 +711,62 @@ func TestIntegrationCompaniesCanBeAddedFromExportedJSON(test *testing.T) {
 		test.Error(err)
 	}
 
-	_, err = storage.Categories.DeleteCategory(createdCategory)
+	_, err = storage.Categories.DeleteCategory(context.Background(), createdCategory)
 	if err != nil {
 		test.Error(err)
 	}
-	_, err = storage.Companies.DeleteCompany(createdCompany)
+	_, err = storage.Companies.DeleteCompany(context.Background(), createdCompany)
 	if err != nil {
 		test.Error(err)
 	}
-	_, err = storage.Products.DeleteProduct(createdProduct)
+	_, err = storage.Products.DeleteProduct(context.Background(), createdProduct)
 	if err != nil {
 		test.Error(err)
 	}
-	_, err = storage.Prices.DeletePrice(createdPrice)
+	_, err = storage.Prices.DeletePrice(context.Background(), createdPrice)
 	if err != nil {
 		test.Error(err)
 	}
-	_, err = storage.Cities.DeleteCity(createdCity)
+	_, err = storage.Cities.DeleteCity(context.Background(), createdCity)
 	if err != nil {
 		test.Error(err)
 	}
 
-	_, err = storage.Companies.ReadCompanyByID(createdCompany.ID, "en")
-	if err != ErrCompanyDoesNotExist {
+	_, err = storage.Companies.ReadCompanyByID(context.Background(), createdCompany.ID, "en")
+	if !errors.Is(err, ErrCompanyDoesNotExist) {
 		test.Error(err)
 	}
 
-	err = storage.Companies.ImportJSON(exportedJSON)
+	report, err := storage.Companies.ImportJSON(context.Background(), exportedJSON)
 	if err != nil {
 		test.Error(err)
 	}
 
-	importedCompany, _ := storage.Companies.ReadCompanyByID(createdCompany.ID, "en")
+	defer deleteImportedNodes(report)
+
+	if report.Created() != 5 || report.Matched() != 0 {
+		test.Fail()
+	}
+
+	importedCompanyID := report.ID("company", createdCompany.ID)
+	if importedCompanyID == "" || importedCompanyID == createdCompany.ID {
+		test.Fail()
+	}
+
+	repeatedReport, err := storage.Companies.ImportJSON(context.Background(), exportedJSON)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if repeatedReport.Created() != 0 || repeatedReport.Matched() != 5 {
+		test.Fail()
+	}
+
+	if repeatedReport.ID("company", createdCompany.ID) != importedCompanyID {
+		test.Fail()
+	}
+
+	importedCompany, _ := storage.Companies.ReadCompanyByID(context.Background(), importedCompanyID, "en")
 
 	if importedCompany.Name != createdCompany.Name {
 		test.Fail()
@@ -774,108 +800,108 @@ func TestIntegrationCompaniesCanBeAddedFromExportedJSON(test *testing.T) {
 func TestIntegrationCompaniesCanBeExportedToJSON(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdCategory, err := storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(createdCompany.ID, createdCategory.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), createdCompany.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddCompanyToProduct(createdProduct.ID, createdCompany.ID)
+	err = storage.Products.AddCompanyToProduct(context.Background(), createdProduct.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = storage.Products.AddCategoryToProduct(createdProduct.ID, createdCategory.ID)
+	err = storage.Products.AddCategoryToProduct(context.Background(), createdProduct.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdOtherProduct, err := storage.Products.CreateProduct(Product{Name: "Other test product"}, "en")
+	createdOtherProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Other test product"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdOtherProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdOtherProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddCategoryToProduct(createdOtherProduct.ID, createdCategory.ID)
+	err = storage.Products.AddCategoryToProduct(context.Background(), createdOtherProduct.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
 	exampleDateTime := "2017-05-01T16:27:18.543653798Z"
 	priceData, _ := time.Parse(time.RFC3339, exampleDateTime)
-	createdPrice, _ := storage.Prices.CreatePrice(Price{Value: 132.3, DateTime: priceData})
+	createdPrice, _ := storage.Prices.CreatePrice(context.Background(), Price{Value: 132.3, DateTime: priceData})
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddPriceToProduct(createdProduct.ID, createdPrice.ID)
+	err = storage.Products.AddPriceToProduct(context.Background(), createdProduct.ID, createdPrice.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdCity, err := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Prices.AddCityToPrice(createdPrice.ID, createdCity.ID)
+	err = storage.Prices.AddCityToPrice(context.Background(), createdPrice.ID, createdCity.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	exportedJSON, err := storage.Companies.ExportJSON("en")
+	exportedJSON, err := storage.Companies.ExportJSON(context.Background(), "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -931,3 +957,63 @@ func TestIntegrationCompaniesCanBeExportedToJSON(test *testing.T) {
 		test.Fail()
 	}
 }
+
+func TestIntegrationImportedPricesBelongToTheirOwnCompanies(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	dateTime := time.Date(2017, 5, 1, 16, 27, 18, 0, time.UTC)
+	city := City{ID: "0xc1", Name: "Import test city of companies", IsActive: true}
+	otherCompany := Company{ID: "0xb1", Name: "Import test other company", IsActive: true}
+
+	all := allExportedCompanies{Language: "en", Companies: []Company{{
+		ID: "0xa1", Name: "Import test company", IsActive: true,
+		Categories: []Category{{
+			ID: "0xa2", Name: "Import test category of companies", IsActive: true,
+			Products: []Product{{
+				ID: "0xa3", Name: "Import test product of companies", IsActive: true,
+				Prices: []Price{
+					{ID: "0xa4", Value: 10, DateTime: dateTime, IsActive: true, Cities: []City{city}},
+					{ID: "0xb4", Value: 10, DateTime: dateTime, IsActive: true, Cities: []City{city},
+						Companies: []Company{otherCompany}}}}}}}}}}
+
+	exportedJSON, err := json.Marshal(all)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	report, err := storage.Companies.ImportJSON(ctx, exportedJSON)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer deleteImportedNodes(report)
+
+	ownPriceID, otherPriceID := report.ID("price", "0xa4"), report.ID("price", "0xb4")
+	if ownPriceID == "" || otherPriceID == "" || ownPriceID == otherPriceID {
+		test.Fatal(report)
+	}
+
+	productID, cityID := report.ID("product", "0xa3"), report.ID("city", "0xc1")
+	for companyID, priceID := range map[string]string{
+		report.ID("company", "0xa1"): ownPriceID,
+		report.ID("company", "0xb1"): otherPriceID} {
+		latest, err := storage.Prices.ReadLatestPrice(ctx, productID, companyID, cityID)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		if latest.ID != priceID {
+			test.Errorf("price of company %v must be %v, not %v", companyID, priceID, latest.ID)
+		}
+	}
+
+	offers, err := storage.Offers.ReadOffers(ctx, OfferFilter{ProductID: productID}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(offers) != 2 {
+		test.Error(offers)
+	}
+}
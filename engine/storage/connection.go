@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+var (
+	// DefaultKeepaliveTime is a time without activity after which connection to database is checked by ping
+	DefaultKeepaliveTime = 30 * time.Second
+
+	// DefaultKeepaliveTimeout is a time of waiting for answer on ping after which connection is closed
+	DefaultKeepaliveTimeout = 10 * time.Second
+
+	// DefaultReconnectMaxDelay is a maximal delay between attempts to reconnect to database
+	DefaultReconnectMaxDelay = 15 * time.Second
+)
+
+var (
+	// ErrEndpointIsNotValid means that endpoint of database is not in host:port format
+	ErrEndpointIsNotValid = errors.New("endpoint of database is not valid")
+
+	// ErrCertificatesCanNotBeLoaded means that files of certificates for TLS can not be read or parsed
+	ErrCertificatesCanNotBeLoaded = errors.New("certificates can not be loaded")
+)
+
+// Endpoint is an address of Alpha of Dgraph cluster
+type Endpoint struct {
+	Host string
+	Port int
+}
+
+func (endpoint Endpoint) String() string {
+	return net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+}
+
+// ParseEndpoints is a function for read endpoints separated by comma like alpha-1:9080,alpha-2,
+// port is defaultPort when it is not set
+func ParseEndpoints(list string, defaultPort int) ([]Endpoint, error) {
+	var endpoints []Endpoint
+
+	for _, address := range strings.Split(list, ",") {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+
+		if !strings.Contains(address, ":") {
+			endpoints = append(endpoints, Endpoint{Host: address, Port: defaultPort})
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, wrap(ErrEndpointIsNotValid, err)
+		}
+
+		number, err := strconv.Atoi(port)
+		if err != nil || host == "" {
+			return nil, wrap(ErrEndpointIsNotValid, fmt.Errorf("host or port of %v is not set", address))
+		}
+
+		endpoints = append(endpoints, Endpoint{Host: host, Port: number})
+	}
+
+	return endpoints, nil
+}
+
+// TLSConfig is a configuration of TLS of connections to database.
+// Server is verified by certificates of CAFile or of system, CertFile and KeyFile are set for mTLS.
+type TLSConfig struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// config make configuration of crypto/tls from files of certificates
+func (config *TLSConfig) config() (*tls.Config, error) {
+	tlsConfig := &tls.Config{ServerName: config.ServerName, MinVersion: tls.VersionTLS12}
+
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, wrap(ErrCertificatesCanNotBeLoaded, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, wrap(ErrCertificatesCanNotBeLoaded, fmt.Errorf("no certificates in %v", config.CAFile))
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, wrap(ErrCertificatesCanNotBeLoaded, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// dialOptions make options of connections with TLS or without it, keepalive and delay of reconnection
+func (storage *Storage) dialOptions() ([]grpc.DialOption, error) {
+	options := []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                storage.KeepaliveTime,
+			Timeout:             storage.KeepaliveTimeout,
+			PermitWithoutStream: true})}
+
+	if storage.ReconnectMaxDelay > 0 {
+		options = append(options, grpc.WithBackoffMaxDelay(storage.ReconnectMaxDelay))
+	}
+
+	if storage.TLS == nil {
+		return append(options, grpc.WithInsecure()), nil
+	}
+
+	tlsConfig, err := storage.TLS.config()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))), nil
+}
+
+// Close is a method for close connections to all endpoints of database
+func (storage *Storage) Close() error {
+	var closeErr error
+	for _, connection := range storage.connections {
+		err := connection.Close()
+		if err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+
+	storage.connections = nil
+	storage.Client = nil
+
+	return closeErr
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEndpointsCanBeParsed(test *testing.T) {
+	endpoints, err := ParseEndpoints("alpha-2:9081, alpha-3,,[::1]:9082", 9080)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expected := []Endpoint{{"alpha-2", 9081}, {"alpha-3", 9080}, {"::1", 9082}}
+	if len(endpoints) != len(expected) {
+		test.Fatal(endpoints)
+	}
+
+	for index, endpoint := range endpoints {
+		if endpoint != expected[index] {
+			test.Errorf("Expected %v, actual: %v", expected[index], endpoint)
+		}
+	}
+
+	if endpoints[2].String() != "[::1]:9082" {
+		test.Error(endpoints[2])
+	}
+
+	_, err = ParseEndpoints("alpha-2:port", 9080)
+	if !errors.Is(err, ErrEndpointIsNotValid) || KindOf(err) != InvalidInput {
+		test.Error(err)
+	}
+}
+
+func TestStorageConnectsToAllEndpointsAndCloses(test *testing.T) {
+	store := New("localhost", 1, Endpoint{Host: "localhost", Port: 2})
+	if len(store.Endpoints) != 2 || store.GraphAddress != "localhost:1" {
+		test.Fatal(store.Endpoints)
+	}
+
+	err := store.Connect()
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(store.connections) != 2 || store.Client == nil {
+		test.Error(store.connections)
+	}
+
+	err = store.Close()
+	if err != nil || store.Client != nil || store.connections != nil {
+		test.Error(err)
+	}
+}
+
+func TestCertificatesOfTLSAreLoaded(test *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	directory, err := ioutil.TempDir("", "sproot-tls")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	caFile := filepath.Join(directory, "ca.crt")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	err = ioutil.WriteFile(caFile, certificate, 0600)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	config, err := (&TLSConfig{CAFile: caFile, ServerName: "alpha"}).config()
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if config.RootCAs == nil || config.ServerName != "alpha" || len(config.Certificates) != 0 {
+		test.Error(config)
+	}
+
+	store := New("localhost", 1)
+	store.TLS = &TLSConfig{CAFile: caFile, CertFile: filepath.Join(directory, "client.crt"), KeyFile: caFile}
+
+	err = store.Connect()
+	if !errors.Is(err, ErrCertificatesCanNotBeLoaded) || store.Client != nil {
+		test.Error(err)
+	}
+}
@@ -0,0 +1,514 @@
+package storage
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaIssue is a predicate which is used differently in schema, structures and queries
+type SchemaIssue struct {
+	Kind       string
+	Predicate  string
+	Suggestion string
+	Position   string
+}
+
+// String is a method for print schema issue
+func (issue SchemaIssue) String() string {
+	if issue.Kind == SchemaIssueNotIndexed {
+		return fmt.Sprintf("%v: %v predicate %v, it needs %v", issue.Position, issue.Kind, issue.Predicate, issue.Suggestion)
+	}
+
+	if issue.Suggestion != "" {
+		return fmt.Sprintf("%v: %v predicate %v, did you mean %v", issue.Position, issue.Kind, issue.Predicate, issue.Suggestion)
+	}
+
+	return fmt.Sprintf("%v: %v predicate %v", issue.Position, issue.Kind, issue.Predicate)
+}
+
+const (
+	// SchemaIssueNotInSchema means that the predicate is used but not declared in schema
+	SchemaIssueNotInSchema = "not in schema"
+
+	// SchemaIssueNotUsed means that the predicate is declared in schema but not used
+	SchemaIssueNotUsed = "not used"
+
+	// SchemaIssueMisspelled means that the predicate is used in query but looks like other known predicate
+	SchemaIssueMisspelled = "misspelled"
+
+	// SchemaIssueNotIndexed means that the predicate is used by function or sort of query which needs index
+	// but it has no suitable index in schema
+	SchemaIssueNotIndexed = "not indexed"
+)
+
+// schemaStructures are structures of database which json tags are predicates
+var schemaStructures = []interface{}{
+	Product{}, Price{}, Offer{}, Rate{}, Company{}, Category{}, City{}, Instruction{}, PageInstruction{}}
+
+// CheckSchemaConsistency compare predicates declared by Migrations with predicates of json tags of
+// structures of database and predicates of DQL queries and N-Quads in go files of sourceDirectory.
+func CheckSchemaConsistency(sourceDirectory string) ([]SchemaIssue, error) {
+	schemaPredicates := SchemaPredicates()
+	structurePredicates := structuresPredicates()
+
+	queryPredicates, indexUsages, err := sourcePredicates(sourceDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for predicate := range schemaPredicates {
+		known[predicate] = true
+	}
+
+	for predicate := range structurePredicates {
+		known[predicate] = true
+	}
+
+	used := map[string]string{}
+	for predicate, position := range queryPredicates {
+		used[predicate] = position
+	}
+
+	for predicate, position := range structurePredicates {
+		used[predicate] = position
+	}
+
+	var issues []SchemaIssue
+
+	for predicate, position := range used {
+		if schemaPredicates[predicate] {
+			continue
+		}
+
+		_, inStructure := structurePredicates[predicate]
+		suggestion := closestPredicate(predicate, known)
+		if !inStructure && suggestion != "" {
+			issues = append(issues, SchemaIssue{
+				Kind: SchemaIssueMisspelled, Predicate: predicate, Suggestion: suggestion, Position: position})
+			continue
+		}
+
+		issues = append(issues, SchemaIssue{
+			Kind: SchemaIssueNotInSchema, Predicate: predicate, Suggestion: suggestion, Position: position})
+	}
+
+	for predicate := range schemaPredicates {
+		if _, ok := used[predicate]; ok {
+			continue
+		}
+
+		issues = append(issues, SchemaIssue{Kind: SchemaIssueNotUsed, Predicate: predicate, Position: "schema"})
+	}
+
+	issues = append(issues, notIndexedIssues(SchemaIndexes(), schemaPredicates, indexUsages)...)
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+
+		return issues[i].Predicate < issues[j].Predicate
+	})
+
+	return issues, nil
+}
+
+var schemaLine = regexp.MustCompile(`(?m)^\s*([A-Za-z_][A-Za-z0-9_]*)\s*:`)
+
+// SchemaPredicates is a set of predicates declared by all migrations and schema version
+func SchemaPredicates() map[string]bool {
+	predicates := map[string]bool{}
+
+	for _, match := range schemaLine.FindAllStringSubmatch(schemaVersionSchema, -1) {
+		predicates[match[1]] = true
+	}
+
+	for _, migration := range Migrations {
+		for _, match := range schemaLine.FindAllStringSubmatch(migration.Schema, -1) {
+			predicates[match[1]] = true
+		}
+
+		for _, predicate := range migration.DropPredicates {
+			delete(predicates, predicate)
+		}
+	}
+
+	return predicates
+}
+
+var schemaIndexDirective = regexp.MustCompile(`@index\(([^)]*)\)`)
+
+// SchemaIndexes is a set of tokenizers of index of each predicate declared by the latest migration of predicate
+func SchemaIndexes() map[string]map[string]bool {
+	indexes := map[string]map[string]bool{}
+
+	for _, migration := range Migrations {
+		for _, line := range strings.Split(migration.Schema, "\n") {
+			match := schemaLine.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+
+			tokenizers := map[string]bool{}
+			for _, directive := range schemaIndexDirective.FindAllStringSubmatch(line, -1) {
+				for _, tokenizer := range strings.Split(directive[1], ",") {
+					tokenizers[strings.TrimSpace(tokenizer)] = true
+				}
+			}
+
+			indexes[match[1]] = tokenizers
+		}
+
+		for _, predicate := range migration.DropPredicates {
+			delete(indexes, predicate)
+		}
+	}
+
+	return indexes
+}
+
+// sortableTokenizers are tokenizers of indexes which can be used by inequality functions and sort at root of query
+var sortableTokenizers = []string{"exact", "int", "float", "datetime", "year", "month", "day", "hour"}
+
+// indexTokenizers are tokenizers of index which are suitable for function or sort of query
+var indexTokenizers = map[string][]string{
+	"eq":         {"exact", "hash", "term", "fulltext", "int", "float", "bool", "datetime", "year", "month", "day", "hour"},
+	"ge":         sortableTokenizers,
+	"gt":         sortableTokenizers,
+	"le":         sortableTokenizers,
+	"lt":         sortableTokenizers,
+	"orderasc":   sortableTokenizers,
+	"orderdesc":  sortableTokenizers,
+	"regexp":     {"trigram"},
+	"match":      {"trigram"},
+	"anyofterms": {"term"},
+	"allofterms": {"term"},
+	"anyoftext":  {"fulltext"},
+	"alloftext":  {"fulltext"},
+}
+
+// IndexUsage is a predicate used by function or sort of query which needs index
+type IndexUsage struct {
+	Predicate string
+	Function  string
+}
+
+var (
+	indexedFunctions = regexp.MustCompile(
+		`\b(eq|ge|gt|le|lt|regexp|match|anyofterms|allofterms|anyoftext|alloftext)\s*\(\s*([A-Za-z_][A-Za-z0-9_]*)\s*([(@,]?)`)
+	indexedSorts = regexp.MustCompile(`\b(orderasc|orderdesc)\s*:\s*([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// QueryIndexUsages returns predicates used by functions and sorts of DQL query or query template which need index.
+// Arguments of functions like val(...) and count(...) are not predicates with index, so they are skipped.
+func QueryIndexUsages(query string) []IndexUsage {
+	query = templateActions.ReplaceAllString(query, "")
+	query = queryComments.ReplaceAllString(query, "")
+	query = queryStrings.ReplaceAllString(query, `""`)
+
+	found := map[IndexUsage]bool{}
+	var usages []IndexUsage
+
+	add := func(usage IndexUsage) {
+		if !found[usage] {
+			found[usage] = true
+			usages = append(usages, usage)
+		}
+	}
+
+	for _, match := range indexedFunctions.FindAllStringSubmatch(query, -1) {
+		if match[3] == "(" {
+			continue
+		}
+
+		add(IndexUsage{Predicate: match[2], Function: match[1]})
+	}
+
+	for _, match := range indexedSorts.FindAllStringSubmatch(query, -1) {
+		add(IndexUsage{Predicate: match[2], Function: match[1]})
+	}
+
+	return usages
+}
+
+func notIndexedIssues(indexes map[string]map[string]bool, schemaPredicates map[string]bool,
+	usages map[IndexUsage]string) []SchemaIssue {
+	var issues []SchemaIssue
+	reported := map[string]bool{}
+
+	for usage, position := range usages {
+		if !schemaPredicates[usage.Predicate] || reported[usage.Predicate+" "+usage.Function] {
+			continue
+		}
+
+		suitable := false
+		for _, tokenizer := range indexTokenizers[usage.Function] {
+			if indexes[usage.Predicate][tokenizer] {
+				suitable = true
+				break
+			}
+		}
+
+		if suitable {
+			continue
+		}
+
+		reported[usage.Predicate+" "+usage.Function] = true
+		issues = append(issues, SchemaIssue{
+			Kind:       SchemaIssueNotIndexed,
+			Predicate:  usage.Predicate,
+			Suggestion: fmt.Sprintf("@index(%v) for %v", indexTokenizers[usage.Function][0], usage.Function),
+			Position:   position})
+	}
+
+	return issues
+}
+
+func structuresPredicates() map[string]string {
+	predicates := map[string]string{}
+
+	for _, structure := range schemaStructures {
+		structureType := reflect.TypeOf(structure)
+
+		for index := 0; index < structureType.NumField(); index++ {
+			field := structureType.Field(index)
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" || name == "uid" {
+				continue
+			}
+
+			if _, ok := predicates[name]; !ok {
+				predicates[name] = fmt.Sprintf("%v.%v", structureType.Name(), field.Name)
+			}
+		}
+	}
+
+	return predicates
+}
+
+var (
+	templateActions  = regexp.MustCompile(`\{\{.*?\}\}`)
+	formatVerbs      = regexp.MustCompile(`%[sdv]`)
+	queryComments    = regexp.MustCompile(`#[^\n]*`)
+	queryStrings     = regexp.MustCompile(`"[^"\n]*"`)
+	queryRegexps     = regexp.MustCompile(`/[^/\n]*/[a-z]*`)
+	queryIdentifiers = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+	nquadPredicates  = regexp.MustCompile(`<%s> <([A-Za-z_][A-Za-z0-9_]*)>`)
+
+	queryKeywords = map[string]bool{
+		"uid": true, "AND": true, "OR": true, "NOT": true, "true": true, "false": true, "as": true}
+)
+
+func sourcePredicates(sourceDirectory string) (map[string]string, map[IndexUsage]string, error) {
+	files := token.NewFileSet()
+
+	packages, err := parser.ParseDir(files, sourceDirectory, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	predicates := map[string]string{}
+	add := func(predicate string, position token.Pos) {
+		if _, ok := predicates[predicate]; !ok {
+			place := files.Position(position)
+			predicates[predicate] = fmt.Sprintf("%v:%v", place.Filename, place.Line)
+		}
+	}
+
+	usages := map[IndexUsage]string{}
+	addUsage := func(usage IndexUsage, position token.Pos) {
+		if _, ok := usages[usage]; !ok {
+			place := files.Position(position)
+			usages[usage] = fmt.Sprintf("%v:%v", place.Filename, place.Line)
+		}
+	}
+
+	for _, sourcePackage := range packages {
+		for _, file := range sourcePackage.Files {
+			ast.Inspect(file, func(node ast.Node) bool {
+				switch expression := node.(type) {
+				case *ast.CallExpr:
+					for _, predicate := range nquadArgumentPredicates(expression) {
+						add(predicate, expression.Pos())
+					}
+				case *ast.BasicLit:
+					if expression.Kind != token.STRING {
+						return true
+					}
+
+					value, err := strconv.Unquote(expression.Value)
+					if err != nil {
+						return true
+					}
+
+					for _, match := range nquadPredicates.FindAllStringSubmatch(value, -1) {
+						add(match[1], expression.Pos())
+					}
+
+					if strings.Contains(value, "func:") {
+						for _, predicate := range QueryPredicates(value) {
+							add(predicate, expression.Pos())
+						}
+
+						for _, usage := range QueryIndexUsages(value) {
+							addUsage(usage, expression.Pos())
+						}
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	return predicates, usages, nil
+}
+
+// nquadArgumentPredicates returns predicates passed as arguments of fmt.Sprintf(`<%s> <%s> <%s> .`, ...)
+func nquadArgumentPredicates(call *ast.CallExpr) []string {
+	if len(call.Args) < 3 {
+		return nil
+	}
+
+	format, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || !strings.Contains(format.Value, "<%s> <%s>") {
+		return nil
+	}
+
+	var predicates []string
+	for _, argument := range call.Args[1:] {
+		literal, ok := argument.(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			continue
+		}
+
+		value, err := strconv.Unquote(literal.Value)
+		if err == nil {
+			predicates = append(predicates, value)
+		}
+	}
+
+	return predicates
+}
+
+// QueryPredicates returns predicates used by DQL query or query template
+func QueryPredicates(query string) []string {
+	query = templateActions.ReplaceAllString(query, "")
+	query = formatVerbs.ReplaceAllString(query, "")
+	query = queryComments.ReplaceAllString(query, "")
+	query = queryStrings.ReplaceAllString(query, "")
+	query = queryRegexps.ReplaceAllString(query, "")
+
+	variables := map[string]bool{}
+	found := map[string]bool{}
+	var predicates []string
+
+	for _, location := range queryIdentifiers.FindAllStringIndex(query, -1) {
+		identifier := query[location[0]:location[1]]
+		directive := location[0] > 0 && query[location[0]-1] == '@'
+		next, nextWord := nextSignificant(query, location[1])
+
+		switch {
+		case queryKeywords[identifier] || variables[identifier]:
+			continue
+		case nextWord == "as":
+			variables[identifier] = true
+			continue
+		case next == ':' || next == '(' || directive:
+			continue
+		}
+
+		if !found[identifier] {
+			found[identifier] = true
+			predicates = append(predicates, identifier)
+		}
+	}
+
+	return predicates
+}
+
+func nextSignificant(text string, position int) (byte, string) {
+	for index := position; index < len(text); index++ {
+		if text[index] == ' ' || text[index] == '\t' || text[index] == '\n' {
+			continue
+		}
+
+		word := queryIdentifiers.FindString(text[index:])
+		if !strings.HasPrefix(text[index:], word) {
+			word = ""
+		}
+
+		return text[index], word
+	}
+
+	return 0, ""
+}
+
+// closestPredicate returns known predicate which differs from predicate by one or two letters
+func closestPredicate(predicate string, known map[string]bool) string {
+	closest := ""
+	closestDistance := 3
+
+	for candidate := range known {
+		if candidate == predicate {
+			continue
+		}
+
+		distance := editDistance(predicate, candidate)
+		if distance < closestDistance || (distance == closestDistance && candidate < closest) {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+
+	return closest
+}
+
+func editDistance(first, second string) int {
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+
+	for index := range previous {
+		previous[index] = index
+	}
+
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(second)]
+}
+
+func minimum(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"testing"
+)
+
+func TestStorageSchemaIsConsistent(test *testing.T) {
+	issues, err := CheckSchemaConsistency(".")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	for _, issue := range issues {
+		test.Error(issue)
+	}
+}
+
+func TestPredicatesCanBeFoundInQuery(test *testing.T) {
+	query := `{
+				all as counters(func: regexp(productName@{{.Language}}, /{{.ProductName}}/i))
+				@filter(eq(productIsActive, true) AND has(productName)){
+					total: count(uid)
+				}
+
+				products(func: uid(all), first: {{.ItemsPerPage}}, offset: {{.Offset}}) {
+					uid
+					productName: productName@{{.Language}}
+					has_price @filter(eq(priceIsActive, true)) (orderdesc: priceDateTime) {
+						priceValue
+						belong_to_company @filter(uid_in(has_category, {{.CategoryID}})) { #TODO: comment
+							companyName: companyName@%v
+						}
+					}
+				}
+			}`
+
+	expected := []string{
+		"productName", "productIsActive", "has_price", "priceIsActive", "priceDateTime",
+		"priceValue", "belong_to_company", "has_category", "companyName"}
+
+	predicates := QueryPredicates(query)
+
+	if len(predicates) != len(expected) {
+		test.Fatal(predicates)
+	}
+
+	for index, predicate := range expected {
+		if predicates[index] != predicate {
+			test.Errorf("expected %v, got %v", predicate, predicates[index])
+		}
+	}
+}
+
+func TestMisspelledPredicateHasSuggestion(test *testing.T) {
+	known := map[string]bool{"belongs_to_company": true, "belongs_to_category": true, "has_company": true}
+
+	if closestPredicate("belong_to_company", known) != "belongs_to_company" {
+		test.Fail()
+	}
+
+	if closestPredicate("previewImageLink", known) != "" {
+		test.Fail()
+	}
+}
+
+func TestPredicatesWithoutSuitableIndexAreReported(test *testing.T) {
+	query := `{
+				products(func: regexp(productName@{{.Language}}, /{{.ProductName}}/i), orderasc: productIri)
+				@filter(eq(productIsActive, true) AND ge(count(has_price), 1)) {
+					has_price @filter(lt(priceDateTime, "{{.Before}}") AND eq(val(total), 1)) {
+						priceValue
+					}
+				}
+			}`
+
+	usages := map[IndexUsage]string{}
+	for _, usage := range QueryIndexUsages(query) {
+		usages[usage] = "query"
+	}
+
+	expected := []IndexUsage{
+		{Predicate: "productName", Function: "regexp"},
+		{Predicate: "productIsActive", Function: "eq"},
+		{Predicate: "priceDateTime", Function: "lt"},
+		{Predicate: "productIri", Function: "orderasc"}}
+
+	if len(usages) != len(expected) {
+		test.Fatal(usages)
+	}
+
+	for _, usage := range expected {
+		if _, ok := usages[usage]; !ok {
+			test.Error(usage)
+		}
+	}
+
+	indexes := map[string]map[string]bool{
+		"productName":     {"term": true},
+		"productIri":      {"term": true},
+		"productIsActive": {"bool": true},
+		"priceDateTime":   {"day": true}}
+	schemaPredicates := map[string]bool{"productName": true, "productIri": true, "productIsActive": true, "priceDateTime": true}
+
+	issues := notIndexedIssues(indexes, schemaPredicates, usages)
+	if len(issues) != 2 {
+		test.Fatal(issues)
+	}
+
+	for _, issue := range issues {
+		if issue.Kind != SchemaIssueNotIndexed || issue.Predicate != "productName" && issue.Predicate != "productIri" {
+			test.Error(issue)
+		}
+	}
+
+	if SchemaIndexes()["priceValue"]["float"] != true || len(SchemaIndexes()["offerPreviousValue"]) != 0 {
+		test.Fail()
+	}
+}
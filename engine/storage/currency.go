@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+	"github.com/hecatoncheir/Sproot/engine/money"
+)
+
+// DefaultCurrency is a currency of prices which are saved without currency
+const DefaultCurrency = "RUB"
+
+// ratesTag is a tag of cached table of rates
+const ratesTag = "rates"
+
+// Rate is a price of one major unit of currency in major units of reference currency of storage,
+// value is a decimal text, so it is exact
+type Rate struct {
+	ID       string    `json:"uid,omitempty"`
+	Currency string    `json:"rateCurrency"`
+	Value    string    `json:"rateValue"`
+	DateTime time.Time `json:"rateDateTime"`
+}
+
+// Rates is resource of storage for rates of currencies to reference currency
+type Rates struct {
+	storage *Storage
+}
+
+// NewRatesResourceForStorage is a constructor of Rates resource
+func NewRatesResourceForStorage(storage *Storage) *Rates {
+	return &Rates{storage: storage}
+}
+
+var (
+	// ErrRateIsNotValid means that currency of rate is not like RUB or value is not a positive decimal number
+	ErrRateIsNotValid = errors.New("rate is not valid")
+
+	// ErrRateCanNotBeSet means that the rate can't be created or updated in database
+	ErrRateCanNotBeSet = errors.New("rate can not be set")
+
+	// ErrRatesCanNotBeRead means that the rates can't be read from database
+	ErrRatesCanNotBeRead = errors.New("rates can not be read")
+
+	// ErrPricesCanNotBeConverted means that currency is not like RUB or rate of currency of price is not set
+	ErrPricesCanNotBeConverted = errors.New("prices can not be converted")
+)
+
+// MoneyOf is a method for get exact amount of price, prices saved before minor units have only value
+// and prices saved without currency are in default currency
+func (price Price) MoneyOf(defaultCurrency string) money.Money {
+	currency := price.Currency
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	if price.Amount == 0 && price.Value != 0 {
+		return money.FromFloat(price.Value, currency)
+	}
+
+	return money.Money{Amount: price.Amount, Currency: currency}
+}
+
+// PriceOf is a function for make price of exact amount with value for indexes and aggregates of database
+func PriceOf(amount money.Money, dateTime time.Time) Price {
+	return Price{Value: amount.Float(), Amount: amount.Amount, Currency: amount.Currency, DateTime: dateTime}
+}
+
+// SetRate is a method for create or update rate of currency to reference currency of storage
+func (rates *Rates) SetRate(ctx context.Context, currency, value string, dateTime time.Time) (Rate, error) {
+	defer rates.storage.invalidate(ratesTag)
+
+	rate := Rate{Currency: currency, Value: value, DateTime: dateTime}
+
+	err := money.ValidateCurrency(currency)
+	if err != nil {
+		return rate, wrap(ErrRateIsNotValid, err)
+	}
+
+	_, err = money.ParseRate(value)
+	if err != nil {
+		return rate, wrap(ErrRateIsNotValid, err)
+	}
+
+	query := fmt.Sprintf(`{
+				rates(func: eq(rateCurrency, "%v")) {
+					uid
+				}
+			}`, currency)
+
+	transaction := rates.storage.newTransaction()
+	defer transaction.Discard(ctx)
+
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return rate, wrap(ErrRateCanNotBeSet, err)
+	}
+
+	var found struct {
+		Rates []Rate `json:"rates"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return rate, wrap(ErrRateCanNotBeSet, err)
+	}
+
+	rate.ID = "_:rate"
+	if len(found.Rates) > 0 {
+		rate.ID = found.Rates[0].ID
+	}
+
+	encodedRate, err := json.Marshal(rate)
+	if err != nil {
+		logError(err)
+		return rate, wrap(ErrRateCanNotBeSet, err)
+	}
+
+	mutation := &dataBaseAPI.Mutation{
+		SetJson:   encodedRate,
+		CommitNow: true}
+
+	assigned, err := transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return rate, wrap(ErrRateCanNotBeSet, err)
+	}
+
+	if len(found.Rates) == 0 {
+		rate.ID = assigned.Uids["rate"]
+	}
+
+	rates.storage.invalidate(ratesTag)
+
+	_, err = rates.storage.Offers.RecalculateReferenceAmounts(ctx)
+	if err != nil {
+		return rate, err
+	}
+
+	return rate, nil
+}
+
+// ReadRates is a method for get rates of all currencies ordered by currency
+func (rates *Rates) ReadRates(ctx context.Context) ([]Rate, error) {
+	key := cacheKey("rates")
+	cached, generation := rates.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.([]Rate), nil
+	}
+
+	query := `{
+				rates(func: has(rateCurrency), orderasc: rateCurrency) {
+					uid
+					rateCurrency
+					rateValue
+					rateDateTime
+				}
+			}`
+
+	transaction := rates.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrRatesCanNotBeRead, err)
+	}
+
+	var found struct {
+		Rates []Rate `json:"rates"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrRatesCanNotBeRead, err)
+	}
+
+	rates.storage.remember(key, generation, found.Rates, ratesTag)
+
+	return found.Rates, nil
+}
+
+// ReadRateTable is a method for get rates of currencies to reference currency of storage for conversion of money
+func (rates *Rates) ReadRateTable(ctx context.Context) (money.Rates, error) {
+	table := money.Rates{Reference: rates.storage.referenceCurrency(), ToReference: map[string]*big.Rat{}}
+
+	found, err := rates.ReadRates(ctx)
+	if err != nil {
+		return table, err
+	}
+
+	for _, rate := range found {
+		value, err := money.ParseRate(rate.Value)
+		if err != nil {
+			return table, wrap(ErrRatesCanNotBeRead, err)
+		}
+
+		table.ToReference[rate.Currency] = value
+	}
+
+	return table, nil
+}
+
+// PricesCurrency is a method for get currency of prices which are saved without currency
+func (storage *Storage) PricesCurrency() string {
+	if storage.DefaultCurrency == "" {
+		return DefaultCurrency
+	}
+
+	return storage.DefaultCurrency
+}
+
+// referenceCurrency is a currency which rates of other currencies are set to
+func (storage *Storage) referenceCurrency() string {
+	if storage.ReferenceCurrency == "" {
+		return storage.PricesCurrency()
+	}
+
+	return storage.ReferenceCurrency
+}
+
+// convertPrice return price with value, amount and currency converted to currency by rates
+func convertPrice(price Price, rates money.Rates, currency, defaultCurrency string) (Price, error) {
+	converted, err := rates.Convert(price.MoneyOf(defaultCurrency), currency)
+	if err != nil {
+		return price, wrap(ErrPricesCanNotBeConverted, err)
+	}
+
+	price.Value, price.Amount, price.Currency = converted.Float(), converted.Amount, converted.Currency
+
+	return price, nil
+}
+
+// convertProducts return copies of products with prices converted to currency, products are not changed
+// because they can be shared by cache
+func convertProducts(products []Product, rates money.Rates, currency, defaultCurrency string) ([]Product, error) {
+	convertedProducts := make([]Product, len(products))
+
+	for index, product := range products {
+		prices := make([]Price, len(product.Prices))
+		for priceIndex, price := range product.Prices {
+			converted, err := convertPrice(price, rates, currency, defaultCurrency)
+			if err != nil {
+				return nil, err
+			}
+
+			prices[priceIndex] = converted
+		}
+
+		product.Prices = prices
+		convertedProducts[index] = product
+	}
+
+	return convertedProducts, nil
+}
+
+// convertOffers change value, amount and previous value of offers to currency, order of offers is not changed
+func convertOffers(offers []Offer, rates money.Rates, currency, defaultCurrency string) error {
+	for index, offer := range offers {
+		value, err := convertPrice(Price{Value: offer.Value, Amount: offer.Amount, Currency: offer.Currency},
+			rates, currency, defaultCurrency)
+		if err != nil {
+			return err
+		}
+
+		previous, err := convertPrice(Price{Value: offer.PreviousValue, Currency: offer.Currency}, rates, currency, defaultCurrency)
+		if err != nil {
+			return err
+		}
+
+		offers[index].Value, offers[index].Amount, offers[index].PreviousValue = value.Value, value.Amount, previous.Value
+		offers[index].Currency = currency
+	}
+
+	return nil
+}
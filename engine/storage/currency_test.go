@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/money"
+)
+
+func TestPricesAreConvertedToRequestedCurrency(test *testing.T) {
+	rates := money.Rates{Reference: "RUB", ToReference: map[string]*big.Rat{"USD": big.NewRat(925, 10)}}
+
+	legacy := Price{Value: 925}
+	if legacy.MoneyOf("RUB") != (money.Money{Amount: 92500, Currency: "RUB"}) {
+		test.Error(legacy.MoneyOf("RUB"))
+	}
+
+	products := []Product{{ID: "0x1", Prices: []Price{legacy, {Value: 10, Amount: 1000, Currency: "USD"}}}}
+
+	converted, err := convertProducts(products, rates, "USD", "RUB")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	for _, price := range converted[0].Prices {
+		if price.Amount != 1000 || price.Value != 10 || price.Currency != "USD" {
+			test.Error(price)
+		}
+	}
+
+	if products[0].Prices[0].Currency != "" || products[0].Prices[0].Value != 925 {
+		test.Error("Prices of found products must not be changed by conversion", products[0].Prices[0])
+	}
+
+	_, err = convertProducts(products, rates, "EUR", "RUB")
+	if !errors.Is(err, ErrPricesCanNotBeConverted) || !errors.Is(err, money.ErrRateDoesNotExist) {
+		test.Error(err)
+	}
+}
+
+func TestOffersAreConvertedWithoutChangeOfOrder(test *testing.T) {
+	rates := money.Rates{Reference: "RUB", ToReference: map[string]*big.Rat{"USD": big.NewRat(100, 1)}}
+
+	offers := []Offer{
+		{ID: "0x1", Value: 200},
+		{ID: "0x2", Value: 3, Amount: 300, Currency: "USD", PreviousValue: 4},
+		{ID: "0x3", Value: 500, Amount: 50000, Currency: "RUB"}}
+
+	err := convertOffers(offers, rates, "RUB", "RUB")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if offers[0].ID != "0x1" || offers[1].ID != "0x2" || offers[2].ID != "0x3" {
+		test.Error(offers)
+	}
+
+	if offers[1].Value != 300 || offers[1].Amount != 30000 || offers[1].PreviousValue != 400 || offers[1].Currency != "RUB" {
+		test.Error(offers[1])
+	}
+
+	if offers[0].Amount != 20000 || offers[0].Currency != "RUB" {
+		test.Error(offers[0])
+	}
+
+	if amount, ok := referenceAmountOf(money.Money{Amount: 300, Currency: "USD"}, rates); !ok || amount != 30000 {
+		test.Error(amount)
+	}
+
+	if _, ok := referenceAmountOf(money.Money{Amount: 300, Currency: "EUR"}, rates); ok {
+		test.Fail()
+	}
+}
+
+func TestCurrencyOfProjectionMustBeValid(test *testing.T) {
+	err := Projection{Currency: "usd"}.validate()
+	if !errors.Is(err, ErrProjectionIsNotValid) || KindOf(err) != InvalidInput {
+		test.Error(err)
+	}
+
+	_, err = (&Offers{}).ReadOffers(context.Background(), OfferFilter{Currency: "dollars"}, "en")
+	if !errors.Is(err, ErrOfferFilterIsNotValid) {
+		test.Error(err)
+	}
+
+	_, err = NewRatesResourceForStorage(&Storage{}).SetRate(context.Background(), "USD", "-1", time.Now())
+	if !errors.Is(err, ErrRateIsNotValid) || KindOf(err) != InvalidInput {
+		test.Error(err)
+	}
+}
+
+func TestIntegrationRatesCanBeSetAndReadAsTable(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	for _, value := range []string{"90", "92.5"} {
+		_, err := storage.Rates.SetRate(ctx, "USD", value, time.Now())
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	found, err := storage.Rates.ReadRates(ctx)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	count := 0
+	for _, rate := range found {
+		if rate.Currency == "USD" {
+			count++
+		}
+	}
+
+	if count != 1 {
+		test.Error(found)
+	}
+
+	table, err := storage.Rates.ReadRateTable(ctx)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	converted, err := table.Convert(money.Money{Amount: 1000, Currency: "USD"}, table.Reference)
+	if err != nil || table.Reference != "RUB" || converted.Amount != 92500 {
+		test.Error(converted, err)
+	}
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dgraph-io/dgo/y"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kind is a class of error of storage which defines how caller must react on it
+type Kind int
+
+const (
+	// Internal kind is for failed operations of database which can not be classified
+	Internal Kind = iota
+
+	// NotFound kind is for nodes which do not exist in database
+	NotFound
+
+	// Conflict kind is for nodes which exist already and for aborted transactions which can be retried
+	Conflict
+
+	// InvalidInput kind is for arguments of methods which can not be used for operation
+	InvalidInput
+
+	// Unavailable kind is for database which can not be reached or does not answer in time
+	Unavailable
+)
+
+var kindNames = []string{"internal", "not found", "conflict", "invalid input", "unavailable"}
+
+func (kind Kind) String() string {
+	if kind < Internal || kind > Unavailable {
+		return kindNames[Internal]
+	}
+
+	return kindNames[kind]
+}
+
+var (
+	// ErrInternal means that operation of database failed by not classified reason
+	ErrInternal = errors.New("internal error of storage")
+
+	// ErrNotFound means that node does not exist in database
+	ErrNotFound = errors.New("not found in storage")
+
+	// ErrConflict means that node exists in database already or transaction is aborted
+	ErrConflict = errors.New("conflict in storage")
+
+	// ErrInvalidInput means that arguments of method can not be used for operation
+	ErrInvalidInput = errors.New("invalid input for storage")
+
+	// ErrUnavailable means that database can not be reached or does not answer in time
+	ErrUnavailable = errors.New("storage is unavailable")
+)
+
+var errorsOfKinds = []error{ErrInternal, ErrNotFound, ErrConflict, ErrInvalidInput, ErrUnavailable}
+
+// Error is an error of method of storage with error of operation and cause of failure.
+// Error is matched by errors.Is with error of operation, with error of its kind and with cause.
+type Error struct {
+	Kind  Kind
+	Err   error
+	Cause error
+}
+
+func (err *Error) Error() string {
+	if err.Cause == nil {
+		return err.Err.Error()
+	}
+
+	return err.Err.Error() + ": " + err.Cause.Error()
+}
+
+// Unwrap is a method for get cause of error
+func (err *Error) Unwrap() error {
+	return err.Cause
+}
+
+// Is is a method for match error with error of operation or with error of kind
+func (err *Error) Is(target error) bool {
+	return target == err.Err || target == errorsOfKinds[err.Kind]
+}
+
+// kinds of errors of operations which do not depend on cause
+var kinds = map[error]Kind{
+	ErrProductsByNameNotFound:             NotFound,
+	ErrProductDoesNotExist:                NotFound,
+	ErrCategoriesByNameNotFound:           NotFound,
+	ErrCategoryDoesNotExist:               NotFound,
+	ErrCompaniesByNameNotFound:            NotFound,
+	ErrCompanyDoesNotExist:                NotFound,
+	ErrCitiesByNameNotFound:               NotFound,
+	ErrCityDoesNotExist:                   NotFound,
+	ErrPriceDoesNotExist:                  NotFound,
+	ErrInstructionDoesNotExist:            NotFound,
+	ErrPageInstructionDoesNotExist:        NotFound,
+	ErrInstructionsForCompanyDoesNotExist: NotFound,
+
+	ErrProductAlreadyExist:  Conflict,
+	ErrCategoryAlreadyExist: Conflict,
+	ErrCompanyAlreadyExist:  Conflict,
+	ErrCityAlreadyExist:     Conflict,
+
+	ErrProductCanNotBeWithoutID:         InvalidInput,
+	ErrCategoryCanNotBeWithoutID:        InvalidInput,
+	ErrCompanyCanNotBeWithoutID:         InvalidInput,
+	ErrCityCanNotBeWithoutID:            InvalidInput,
+	ErrInstructionCanNotBeWithoutID:     InvalidInput,
+	ErrPageInstructionCanNotBeWithoutID: InvalidInput,
+	ErrImportedNodeWithoutKey:           InvalidInput,
+	ErrGraphFormatIsNotSupported:        InvalidInput,
+	ErrGraphIsIncomplete:                InvalidInput,
+	ErrGraphChecksumMismatch:            InvalidInput,
+	ErrEndpointIsNotValid:               InvalidInput,
+	ErrCertificatesCanNotBeLoaded:       InvalidInput,
+	ErrProjectionIsNotValid:             InvalidInput,
+	ErrOfferFilterIsNotValid:            InvalidInput,
+	ErrPriceStatisticsWindowIsNotValid:  InvalidInput,
+	ErrRetentionPolicyIsNotValid:        InvalidInput,
+	ErrRateIsNotValid:                   InvalidInput,
+	ErrPricesCanNotBeConverted:          InvalidInput,
+
+	ErrSchemaIsOutdated: Unavailable,
+	ErrSchemaIsNewer:    Unavailable}
+
+// KindOf is a function for get kind of error returned by storage, Internal for unknown errors
+func KindOf(err error) Kind {
+	var storageError *Error
+	if errors.As(err, &storageError) {
+		return storageError.Kind
+	}
+
+	for sentinel, kind := range kinds {
+		if errors.Is(err, sentinel) {
+			return kind
+		}
+	}
+
+	return kindOfCause(err)
+}
+
+// kindOfCause classify error of database client or of context
+func kindOfCause(cause error) Kind {
+	switch {
+	case cause == nil:
+		return Internal
+	case errors.Is(cause, y.ErrAborted):
+		return Conflict
+	case errors.Is(cause, context.DeadlineExceeded), errors.Is(cause, context.Canceled):
+		return Unavailable
+	}
+
+	switch status.Code(cause) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return Unavailable
+	case codes.Aborted, codes.AlreadyExists:
+		return Conflict
+	case codes.InvalidArgument:
+		return InvalidInput
+	case codes.NotFound:
+		return NotFound
+	}
+
+	return Internal
+}
+
+// wrap is a function for make error of operation with cause of failure,
+// kind of error is defined by error of operation or by cause
+func wrap(err, cause error) error {
+	kind, ok := kinds[err]
+	if !ok {
+		kind = KindOf(cause)
+	}
+
+	return &Error{Kind: kind, Err: err, Cause: cause}
+}
+
+// fail is a function for make error of operation without cause
+func fail(err error) error {
+	return wrap(err, nil)
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dgraph-io/dgo/y"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorOfStorageWrapsCause(test *testing.T) {
+	cause := status.Error(codes.Unavailable, "connection refused")
+	err := wrap(ErrProductsByNameCanNotBeFound, cause)
+
+	if !errors.Is(err, ErrProductsByNameCanNotBeFound) || !errors.Is(err, cause) || !errors.Is(err, ErrUnavailable) {
+		test.Error(err)
+	}
+
+	if errors.Is(err, ErrNotFound) || KindOf(err) != Unavailable {
+		test.Error(KindOf(err))
+	}
+
+	if err.Error() != "products by name can not be found: rpc error: code = Unavailable desc = connection refused" {
+		test.Error(err)
+	}
+
+	var storageError *Error
+	if !errors.As(err, &storageError) || storageError.Err != ErrProductsByNameCanNotBeFound {
+		test.Error(storageError)
+	}
+}
+
+func TestKindOfErrorIsDefinedByOperationOrByCause(test *testing.T) {
+	cases := []struct {
+		err  error
+		kind Kind
+	}{
+		{fail(ErrProductsByNameNotFound), NotFound},
+		{ErrCompanyDoesNotExist, NotFound},
+		{fail(ErrCityAlreadyExist), Conflict},
+		{fail(ErrCategoryCanNotBeWithoutID), InvalidInput},
+		{wrap(ErrProductCanNotBeCreated, y.ErrAborted), Conflict},
+		{wrap(ErrProductCanNotBeCreated, context.DeadlineExceeded), Unavailable},
+		{wrap(ErrProductCanNotBeCreated, fail(ErrProductsByNameCanNotBeFound)), Internal},
+		{wrap(ErrProductCanNotBeCreated, wrap(ErrProductsByNameCanNotBeFound, status.Error(codes.Unavailable, ""))), Unavailable},
+		{errors.New("unknown"), Internal}}
+
+	for _, testCase := range cases {
+		if KindOf(testCase.err) != testCase.kind {
+			test.Errorf("Expected kind %v of %v, actual: %v", testCase.kind, testCase.err, KindOf(testCase.err))
+		}
+	}
+}
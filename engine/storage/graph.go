@@ -0,0 +1,971 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+)
+
+// graphKind is a type of node of graph with predicates for export
+type graphKind struct {
+	Name    string
+	Key     string
+	Scalars []string
+	Edges   []string
+}
+
+// graphKinds are all types of nodes of database in order of export
+var graphKinds = []graphKind{
+	{
+		Name:    "city",
+		Key:     "cityName",
+		Scalars: []string{"cityName@*", "cityIsActive"}},
+	{
+		Name:    "company",
+		Key:     "companyName",
+		Scalars: []string{"companyName@*", "companyIri", "companyIsActive"},
+		Edges:   []string{"has_category"}},
+	{
+		Name:    "category",
+		Key:     "categoryName",
+		Scalars: []string{"categoryName@*", "categoryIsActive"},
+		Edges:   []string{"belongs_to_company", "has_product"}},
+	{
+		Name:    "product",
+		Key:     "productName",
+		Scalars: []string{"productName@*", "productIri", "previewImageLink", "productIsActive"},
+		Edges:   []string{"belongs_to_category", "belongs_to_company", "has_price", "has_offer"}},
+	{
+		Name:    "price",
+		Key:     "priceValue",
+		Scalars: []string{"priceValue", "priceAmount", "priceCurrency", "priceDateTime", "priceLastSeenAt", "priceIsActive"},
+		Edges:   []string{"belongs_to_city", "belongs_to_product", "belongs_to_company"}},
+	{
+		Name: "offer",
+		Key:  "offerKey",
+		Scalars: []string{
+			"offerKey", "offerValue", "offerPreviousValue", "offerAmount", "offerCurrency", "offerReferenceAmount",
+			"offerDateTime"},
+		Edges: []string{"belongs_to_product", "belongs_to_company", "belongs_to_city"}},
+	{
+		Name:    "rate",
+		Key:     "rateCurrency",
+		Scalars: []string{"rateCurrency", "rateValue", "rateDateTime"}},
+	{
+		Name: "pageInstruction",
+		Key:  "path",
+		Scalars: []string{
+			"path", "pageInPaginationSelector", "previewImageOfSelector", "pageParamPath", "cityParamPath",
+			"itemSelector", "nameOfItemSelector", "linkOfItemSelector", "cityInCookieKey", "cityIdForCookie",
+			"priceOfItemSelector", "pageInstructionIsActive"}},
+	{
+		Name:    "instruction",
+		Key:     "instructionLanguage",
+		Scalars: []string{"instructionLanguage", "instructionIsActive"},
+		Edges:   []string{"has_company", "has_city", "has_category", "has_page"}},
+}
+
+const (
+	// GraphFormatVersion is a version of format of exported graph
+	GraphFormatVersion = 1
+
+	graphRecordHeader = "header"
+	graphRecordNode   = "node"
+	graphRecordEdges  = "edges"
+	graphRecordFooter = "footer"
+
+	defaultGraphBatchSize = 1000
+)
+
+// GraphRecord is a line of newline-delimited JSON of exported graph.
+// Graph starts with header, then all nodes with values of predicates, then all edges of nodes and footer.
+type GraphRecord struct {
+	Type          string                 `json:"type"`
+	Kind          string                 `json:"kind,omitempty"`
+	UID           string                 `json:"uid,omitempty"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+	Edges         map[string][]string    `json:"edges,omitempty"`
+	FormatVersion int                    `json:"formatVersion,omitempty"`
+	SchemaVersion int                    `json:"schemaVersion,omitempty"`
+	Records       int                    `json:"records,omitempty"`
+	Checksum      string                 `json:"checksum,omitempty"`
+}
+
+// GraphProgress is a state of export or import of graph
+type GraphProgress struct {
+	Section string
+	Kind    string
+	Records int
+	Nodes   int
+	Edges   int
+	Skipped int
+}
+
+// GraphOptions is a settings of export and import of graph.
+// CheckpointPath is a file for resume import after failure, it is removed after successful import.
+type GraphOptions struct {
+	BatchSize      int
+	Progress       func(GraphProgress)
+	CheckpointPath string
+}
+
+func (options GraphOptions) batchSize() int {
+	if options.BatchSize <= 0 {
+		return defaultGraphBatchSize
+	}
+
+	return options.BatchSize
+}
+
+func (options GraphOptions) report(progress GraphProgress) {
+	if options.Progress != nil {
+		options.Progress(progress)
+	}
+}
+
+var (
+	// ErrGraphCanNotBeExported means that the graph can't be read from database
+	ErrGraphCanNotBeExported = errors.New("graph can not be exported")
+
+	// ErrGraphCanNotBeImported means that the graph can't be written to database
+	ErrGraphCanNotBeImported = errors.New("graph can not be imported")
+
+	// ErrGraphFormatIsNotSupported means that the graph was exported in unknown format
+	ErrGraphFormatIsNotSupported = errors.New("format of graph is not supported")
+
+	// ErrGraphIsIncomplete means that the graph has no footer
+	ErrGraphIsIncomplete = errors.New("graph is incomplete")
+
+	// ErrGraphChecksumMismatch means that the graph was changed or damaged after export
+	ErrGraphChecksumMismatch = errors.New("checksum of graph does not match")
+)
+
+// graphWriter writes records of graph and counts checksum of written lines
+type graphWriter struct {
+	writer   io.Writer
+	checksum hash.Hash
+	progress GraphProgress
+}
+
+func (out *graphWriter) write(record GraphRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	line = append(line, '\n')
+
+	_, err = out.writer.Write(line)
+	if err != nil {
+		return err
+	}
+
+	out.checksum.Write(line)
+	out.progress.Records++
+
+	return nil
+}
+
+// graphPosition is a last exported node of graph
+type graphPosition struct {
+	Section string
+	Kind    string
+	UID     string
+}
+
+// ExportGraph is a method for write all nodes and edges of database to writer as newline-delimited JSON.
+// Nodes are read by batches in one read-only transaction, so graph is a snapshot of database
+// at one read timestamp and it is never loaded to memory.
+func (storage *Storage) ExportGraph(ctx context.Context, writer io.Writer, options GraphOptions) (GraphProgress, error) {
+	out := &graphWriter{writer: writer, checksum: sha256.New()}
+
+	schemaVersion, err := storage.SchemaVersion(ctx)
+	if err != nil {
+		return out.progress, err
+	}
+
+	err = out.write(GraphRecord{
+		Type: graphRecordHeader, FormatVersion: GraphFormatVersion, SchemaVersion: schemaVersion})
+	if err != nil {
+		return out.progress, err
+	}
+
+	return storage.exportGraph(ctx, out, graphPosition{}, options)
+}
+
+// ExportGraphToFile is a method for export graph to file.
+// If resume is true and file exists, export continues after last complete record of file,
+// records written after resume are read at new read timestamp.
+func (storage *Storage) ExportGraphToFile(ctx context.Context, path string, resume bool, options GraphOptions) (GraphProgress, error) {
+	if !resume {
+		file, err := os.Create(path)
+		if err != nil {
+			return GraphProgress{}, err
+		}
+		defer file.Close()
+
+		buffer := bufio.NewWriter(file)
+		progress, err := storage.ExportGraph(ctx, buffer, options)
+		if err != nil {
+			buffer.Flush()
+			return progress, err
+		}
+
+		return progress, buffer.Flush()
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return GraphProgress{}, err
+	}
+	defer file.Close()
+
+	out := &graphWriter{checksum: sha256.New()}
+	position := graphPosition{}
+	complete := int64(0)
+	headerFound := false
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return out.progress, err
+		}
+
+		record := GraphRecord{}
+		if json.Unmarshal(line, &record) != nil {
+			break
+		}
+
+		if record.Type == graphRecordFooter {
+			return out.progress, nil
+		}
+
+		if record.Type == graphRecordHeader {
+			headerFound = true
+		} else {
+			position = graphPosition{Section: record.Type, Kind: record.Kind, UID: record.UID}
+		}
+
+		out.checksum.Write(line)
+		out.progress.Records++
+		complete += int64(len(line))
+	}
+
+	err = file.Truncate(complete)
+	if err != nil {
+		return out.progress, err
+	}
+
+	_, err = file.Seek(complete, io.SeekStart)
+	if err != nil {
+		return out.progress, err
+	}
+
+	buffer := bufio.NewWriter(file)
+	out.writer = buffer
+
+	if !headerFound {
+		schemaVersion, err := storage.SchemaVersion(ctx)
+		if err != nil {
+			return out.progress, err
+		}
+
+		err = out.write(GraphRecord{
+			Type: graphRecordHeader, FormatVersion: GraphFormatVersion, SchemaVersion: schemaVersion})
+		if err != nil {
+			return out.progress, err
+		}
+	}
+
+	progress, err := storage.exportGraph(ctx, out, position, options)
+	if err != nil {
+		buffer.Flush()
+		return progress, err
+	}
+
+	return progress, buffer.Flush()
+}
+
+func (storage *Storage) exportGraph(ctx context.Context, out *graphWriter, from graphPosition, options GraphOptions) (GraphProgress, error) {
+	sections := []string{graphRecordNode, graphRecordEdges}
+
+	// all batches are read at start timestamp of the first query of transaction
+	transaction := storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+
+	skip := from.Section != ""
+	for _, section := range sections {
+		for _, kind := range graphKinds {
+			after := ""
+
+			if skip {
+				if section != from.Section || kind.Name != from.Kind {
+					continue
+				}
+
+				skip = false
+				after = from.UID
+			}
+
+			if section == graphRecordEdges && len(kind.Edges) == 0 {
+				continue
+			}
+
+			out.progress.Section = section
+			out.progress.Kind = kind.Name
+
+			for {
+				nodes, err := readGraphNodes(ctx, transaction, kind, section, after, options.batchSize())
+				if err != nil {
+					return out.progress, err
+				}
+
+				for _, node := range nodes {
+					if section == graphRecordEdges && len(node.Edges) == 0 {
+						continue
+					}
+
+					err = out.write(node)
+					if err != nil {
+						return out.progress, err
+					}
+
+					if section == graphRecordNode {
+						out.progress.Nodes++
+					} else {
+						out.progress.Edges += countGraphEdges(node)
+					}
+				}
+
+				options.report(out.progress)
+
+				if len(nodes) == 0 || len(nodes) < options.batchSize() {
+					break
+				}
+
+				after = nodes[len(nodes)-1].UID
+			}
+		}
+	}
+
+	err := writeGraphFooter(out)
+	if err != nil {
+		return out.progress, err
+	}
+
+	return out.progress, nil
+}
+
+// writeGraphFooter write count of records and checksum of all written lines
+func writeGraphFooter(out *graphWriter) error {
+	footer := GraphRecord{
+		Type:     graphRecordFooter,
+		Records:  out.progress.Records,
+		Checksum: hex.EncodeToString(out.checksum.Sum(nil))}
+
+	line, err := json.Marshal(footer)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.writer.Write(append(line, '\n'))
+
+	return err
+}
+
+func countGraphEdges(record GraphRecord) int {
+	count := 0
+	for _, targets := range record.Edges {
+		count += len(targets)
+	}
+
+	return count
+}
+
+var graphNodesTemplate = template.Must(template.New("graphNodes").Parse(`{
+				nodes(func: has({{.Key}}), first: {{.First}}{{if .After}}, after: {{.After}}{{end}}) {
+					uid
+					{{range .Predicates}}{{.}}
+					{{end}}
+				}
+			}`))
+
+// readGraphNodes read one batch of nodes of kind in order of uids by transaction of export
+func readGraphNodes(ctx context.Context, transaction *transaction, kind graphKind, section, after string, first int) ([]GraphRecord, error) {
+	predicates := kind.Scalars
+	if section == graphRecordEdges {
+		predicates = nil
+		for _, edge := range kind.Edges {
+			predicates = append(predicates, fmt.Sprintf("%v { uid }", edge))
+		}
+	}
+
+	variables := struct {
+		Key, After string
+		First      int
+		Predicates []string
+	}{
+		Key:        kind.Key,
+		After:      after,
+		First:      first,
+		Predicates: predicates}
+
+	queryBuf := bytes.Buffer{}
+	err := graphNodesTemplate.Execute(&queryBuf, variables)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrGraphCanNotBeExported, err)
+	}
+
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrGraphCanNotBeExported, err)
+	}
+
+	type nodesInStorage struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+	}
+
+	var foundedNodes nodesInStorage
+	err = json.Unmarshal(response.GetJson(), &foundedNodes)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrGraphCanNotBeExported, err)
+	}
+
+	records := make([]GraphRecord, 0, len(foundedNodes.Nodes))
+	for _, node := range foundedNodes.Nodes {
+		uid, _ := node["uid"].(string)
+		delete(node, "uid")
+
+		record := GraphRecord{Type: section, Kind: kind.Name, UID: uid}
+
+		if section == graphRecordNode {
+			record.Data = node
+		} else {
+			record.Edges = map[string][]string{}
+			for predicate, value := range node {
+				targets, _ := value.([]interface{})
+				for _, target := range targets {
+					targetNode, _ := target.(map[string]interface{})
+					targetUID, _ := targetNode["uid"].(string)
+					if targetUID != "" {
+						record.Edges[predicate] = append(record.Edges[predicate], targetUID)
+					}
+				}
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// graphCheckpoint is a line of checkpoint file with last imported line of graph and uids assigned to its nodes.
+// The first line has id of import which marks imported nodes.
+type graphCheckpoint struct {
+	Line   int               `json:"line"`
+	Import string            `json:"import,omitempty"`
+	UIDs   map[string]string `json:"uids,omitempty"`
+}
+
+func readGraphCheckpoint(path string) (int, map[string]string, string, error) {
+	uids := map[string]string{}
+	importID := ""
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, uids, importID, nil
+	}
+
+	if err != nil {
+		return 0, uids, importID, err
+	}
+	defer file.Close()
+
+	line := 0
+	reader := bufio.NewReader(file)
+	for {
+		data, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return 0, uids, importID, err
+		}
+
+		checkpoint := graphCheckpoint{}
+		if json.Unmarshal(data, &checkpoint) != nil {
+			break
+		}
+
+		for exported, imported := range checkpoint.UIDs {
+			uids[exported] = imported
+		}
+
+		if checkpoint.Import != "" {
+			importID = checkpoint.Import
+		}
+
+		line = checkpoint.Line
+	}
+
+	return line, uids, importID, nil
+}
+
+// newGraphImportID make random id of resumable import of graph
+func newGraphImportID() (string, error) {
+	id := make([]byte, 8)
+
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// graphImport is a state of import of graph
+type graphImport struct {
+	storage    *Storage
+	options    GraphOptions
+	checkpoint *os.File
+
+	uids     map[string]string
+	nquads   bytes.Buffer
+	blanks   map[string]string
+	pending  int
+	line     int
+	progress GraphProgress
+
+	// importID marks nodes of resumable import, so nodes of batch which was written
+	// without checkpoint before failure are matched instead of written again
+	importID string
+	resumed  bool
+}
+
+// ImportGraph is a method for read graph exported by ExportGraph and write its nodes and edges to database.
+// Exported uids are replaced by new uids of database. Nodes and edges are written by batches and if
+// CheckpointPath is set, import can be repeated after failure and continues after last written batch.
+// Nodes of resumable import are marked by graphImportID in the same mutation, so nodes of the batch written
+// before failure of checkpoint are found by marks and they are not duplicated.
+// Checksum is compared only at the end of graph, so graph must be checked by VerifyGraph before import.
+func (storage *Storage) ImportGraph(ctx context.Context, reader io.Reader, options GraphOptions) (GraphProgress, error) {
+	defer storage.Cache.Purge()
+
+	state := graphImport{storage: storage, options: options, blanks: map[string]string{}}
+
+	lastImportedLine := 0
+	state.uids = map[string]string{}
+
+	if options.CheckpointPath != "" {
+		var err error
+		lastImportedLine, state.uids, state.importID, err = readGraphCheckpoint(options.CheckpointPath)
+		if err != nil {
+			return state.progress, err
+		}
+
+		state.resumed = state.importID != ""
+
+		state.checkpoint, err = os.OpenFile(options.CheckpointPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return state.progress, err
+		}
+		defer state.checkpoint.Close()
+
+		if state.importID == "" {
+			state.importID, err = newGraphImportID()
+			if err != nil {
+				return state.progress, err
+			}
+
+			err = state.writeCheckpoint(graphCheckpoint{Line: lastImportedLine, Import: state.importID})
+			if err != nil {
+				return state.progress, err
+			}
+		}
+	}
+
+	checksum := sha256.New()
+	lines := bufio.NewReader(reader)
+	footerFound := false
+
+	for {
+		line, err := lines.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			break
+		}
+
+		if err != nil && err != io.EOF {
+			return state.progress, err
+		}
+
+		state.line++
+
+		record := GraphRecord{}
+		err = json.Unmarshal(line, &record)
+		if err != nil {
+			return state.progress, wrap(ErrGraphFormatIsNotSupported, err)
+		}
+
+		if record.Type == graphRecordFooter {
+			footerFound = true
+
+			if record.Checksum != hex.EncodeToString(checksum.Sum(nil)) || record.Records != state.line-1 {
+				return state.progress, fail(ErrGraphChecksumMismatch)
+			}
+
+			break
+		}
+
+		checksum.Write(line)
+		state.progress.Records++
+
+		if record.Type == graphRecordHeader {
+			if record.FormatVersion != GraphFormatVersion {
+				return state.progress, fail(ErrGraphFormatIsNotSupported)
+			}
+
+			if record.SchemaVersion > LatestSchemaVersion() {
+				return state.progress, fail(ErrSchemaIsNewer)
+			}
+
+			continue
+		}
+
+		if state.line <= lastImportedLine {
+			continue
+		}
+
+		if record.Type == graphRecordEdges && state.progress.Section == graphRecordNode {
+			err = state.flush(ctx)
+			if err != nil {
+				return state.progress, err
+			}
+		}
+
+		state.progress.Section = record.Type
+		state.progress.Kind = record.Kind
+
+		switch record.Type {
+		case graphRecordNode:
+			state.addNode(record)
+		case graphRecordEdges:
+			state.addEdges(record)
+		default:
+			return state.progress, fail(ErrGraphFormatIsNotSupported)
+		}
+
+		if state.pending >= options.batchSize() {
+			err = state.flush(ctx)
+			if err != nil {
+				return state.progress, err
+			}
+		}
+	}
+
+	if !footerFound {
+		return state.progress, fail(ErrGraphIsIncomplete)
+	}
+
+	err := state.flush(ctx)
+	if err != nil {
+		return state.progress, err
+	}
+
+	if options.CheckpointPath != "" {
+		state.checkpoint.Close()
+		err = os.Remove(options.CheckpointPath)
+		if err != nil {
+			return state.progress, err
+		}
+	}
+
+	return state.progress, nil
+}
+
+func (state *graphImport) addNode(record GraphRecord) {
+	if _, imported := state.uids[record.UID]; imported {
+		return
+	}
+
+	blank := "node" + strings.TrimPrefix(record.UID, "0x")
+	state.blanks[blank] = record.UID
+
+	for key, value := range record.Data {
+		predicate, language := key, ""
+		if index := strings.Index(key, "@"); index > 0 {
+			predicate, language = key[:index], key[index+1:]
+		}
+
+		literal := graphLiteral(value)
+		if language != "" {
+			literal += "@" + language
+		}
+
+		fmt.Fprintf(&state.nquads, "_:%s <%s> %s .\n", blank, predicate, literal)
+	}
+
+	if state.importID != "" {
+		fmt.Fprintf(&state.nquads, "_:%s <graphImportID> %s .\n", blank, graphLiteral(state.importID+"/"+record.UID))
+	}
+
+	state.pending++
+	state.progress.Nodes++
+}
+
+func (state *graphImport) addEdges(record GraphRecord) {
+	subject, ok := state.uids[record.UID]
+	if !ok {
+		state.progress.Skipped++
+		return
+	}
+
+	for predicate, targets := range record.Edges {
+		for _, target := range targets {
+			object, ok := state.uids[target]
+			if !ok {
+				state.progress.Skipped++
+				continue
+			}
+
+			fmt.Fprintf(&state.nquads, "<%s> <%s> <%s> .\n", subject, predicate, object)
+			state.progress.Edges++
+		}
+	}
+
+	if record.Kind == "offer" {
+		state.rekeyOffer(subject, record)
+	}
+
+	state.pending++
+}
+
+// rekeyOffer write natural key of offer made of new uids of its product, company and city,
+// exported key is made of exported uids
+func (state *graphImport) rekeyOffer(subject string, record GraphRecord) {
+	var ids []string
+	for _, predicate := range []string{"belongs_to_product", "belongs_to_company", "belongs_to_city"} {
+		targets := record.Edges[predicate]
+		if len(targets) == 0 {
+			return
+		}
+
+		id, ok := state.uids[targets[0]]
+		if !ok {
+			return
+		}
+
+		ids = append(ids, id)
+	}
+
+	fmt.Fprintf(&state.nquads, "<%s> <offerKey> %s .\n", subject, graphLiteral(offerKey(ids[0], ids[1], ids[2])))
+}
+
+func graphLiteral(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return strconv.Quote(typed)
+	case float64:
+		return strconv.Quote(strconv.FormatFloat(typed, 'f', -1, 64))
+	case bool:
+		return strconv.Quote(strconv.FormatBool(typed))
+	default:
+		return strconv.Quote(fmt.Sprint(typed))
+	}
+}
+
+// flush write batch of nodes and edges to database and save checkpoint
+func (state *graphImport) flush(ctx context.Context) error {
+	checkpoint := graphCheckpoint{Line: state.line, UIDs: map[string]string{}}
+
+	if state.resumed {
+		err := state.matchImportedNodes(ctx, checkpoint)
+		if err != nil {
+			return err
+		}
+
+		state.resumed = false
+	}
+
+	if state.nquads.Len() > 0 {
+		mutation := dataBaseAPI.Mutation{
+			SetNquads: state.nquads.Bytes(),
+			CommitNow: true}
+
+		transaction := state.storage.newTransaction()
+		assigned, err := transaction.Mutate(ctx, &mutation)
+		transaction.Discard(ctx)
+		if err != nil {
+			logError(err)
+			return wrap(ErrGraphCanNotBeImported, err)
+		}
+
+		for blank, exportedUID := range state.blanks {
+			if uid, ok := assigned.Uids[blank]; ok {
+				state.uids[exportedUID] = uid
+				checkpoint.UIDs[exportedUID] = uid
+			}
+		}
+	}
+
+	if state.checkpoint != nil {
+		err := state.writeCheckpoint(checkpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	state.nquads.Reset()
+	state.blanks = map[string]string{}
+	state.pending = 0
+
+	state.options.report(state.progress)
+
+	return nil
+}
+
+// writeCheckpoint append line to checkpoint file and sync it
+func (state *graphImport) writeCheckpoint(checkpoint graphCheckpoint) error {
+	line, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	_, err = state.checkpoint.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return state.checkpoint.Sync()
+}
+
+// matchImportedNodes find nodes of batch which were written before failure by marks of import,
+// they get uids of database and their nquads are not written again
+func (state *graphImport) matchImportedNodes(ctx context.Context, checkpoint graphCheckpoint) error {
+	if len(state.blanks) == 0 {
+		return nil
+	}
+
+	query := strings.Builder{}
+	query.WriteString("{\n")
+	for blank, exportedUID := range state.blanks {
+		fmt.Fprintf(&query, "%s(func: eq(graphImportID, %s)) {\n uid\n }\n", blank, graphLiteral(state.importID+"/"+exportedUID))
+	}
+	query.WriteString("}")
+
+	transaction := state.storage.newReadTransaction(WithConsistency(ctx, Linearizable))
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query.String())
+	if err != nil {
+		logError(err)
+		return wrap(ErrGraphCanNotBeImported, err)
+	}
+
+	var found map[string][]struct {
+		ID string `json:"uid"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return wrap(ErrGraphCanNotBeImported, err)
+	}
+
+	matched := map[string]bool{}
+	for blank, nodes := range found {
+		exportedUID, ok := state.blanks[blank]
+		if !ok || len(nodes) == 0 {
+			continue
+		}
+
+		state.uids[exportedUID] = nodes[0].ID
+		checkpoint.UIDs[exportedUID] = nodes[0].ID
+		matched[blank] = true
+		delete(state.blanks, blank)
+		state.progress.Nodes--
+	}
+
+	if len(matched) > 0 {
+		nquads := withoutBlankNodes(state.nquads.Bytes(), matched)
+		state.nquads.Reset()
+		state.nquads.Write(nquads)
+	}
+
+	return nil
+}
+
+// withoutBlankNodes return nquads without lines of blank nodes
+func withoutBlankNodes(nquads []byte, blanks map[string]bool) []byte {
+	kept := bytes.Buffer{}
+
+	for _, line := range bytes.SplitAfter(nquads, []byte("\n")) {
+		if end := bytes.IndexByte(line, ' '); bytes.HasPrefix(line, []byte("_:")) && end > 2 && blanks[string(line[2:end])] {
+			continue
+		}
+
+		kept.Write(line)
+	}
+
+	return kept.Bytes()
+}
+
+// VerifyGraph is a function for check format and checksum of exported graph without import
+func VerifyGraph(reader io.Reader) (int, error) {
+	checksum := sha256.New()
+	lines := bufio.NewReader(reader)
+	records := 0
+
+	for {
+		line, err := lines.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			return records, fail(ErrGraphIsIncomplete)
+		}
+
+		if err != nil && err != io.EOF {
+			return records, err
+		}
+
+		record := GraphRecord{}
+		if json.Unmarshal(line, &record) != nil {
+			return records, fail(ErrGraphFormatIsNotSupported)
+		}
+
+		if record.Type == graphRecordHeader && record.FormatVersion != GraphFormatVersion {
+			return records, fail(ErrGraphFormatIsNotSupported)
+		}
+
+		if record.Type == graphRecordFooter {
+			if record.Checksum != hex.EncodeToString(checksum.Sum(nil)) || record.Records != records {
+				return records, fail(ErrGraphChecksumMismatch)
+			}
+
+			return records, nil
+		}
+
+		checksum.Write(line)
+		records++
+	}
+}
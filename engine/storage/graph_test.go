@@ -0,0 +1,428 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestGraph(test *testing.T, records ...GraphRecord) []byte {
+	buffer := bytes.Buffer{}
+	out := &graphWriter{writer: &buffer, checksum: sha256.New()}
+
+	err := out.write(GraphRecord{Type: graphRecordHeader, FormatVersion: GraphFormatVersion, SchemaVersion: 1})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	for _, record := range records {
+		err = out.write(record)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	err = writeGraphFooter(out)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func testGraphRecords() []GraphRecord {
+	return []GraphRecord{
+		{Type: graphRecordNode, Kind: "city", UID: "0x1",
+			Data: map[string]interface{}{"cityName@en": "Graph test city", "cityIsActive": true}},
+		{Type: graphRecordNode, Kind: "product", UID: "0x2",
+			Data: map[string]interface{}{
+				"productName@en": "Graph test product", "productIri": "/graph/test", "productIsActive": true}},
+		{Type: graphRecordNode, Kind: "price", UID: "0x3",
+			Data: map[string]interface{}{
+				"priceValue": 123.5, "priceDateTime": "2017-05-01T16:27:18Z", "priceIsActive": true}},
+		{Type: graphRecordEdges, Kind: "product", UID: "0x2",
+			Edges: map[string][]string{"has_price": {"0x3"}}},
+		{Type: graphRecordEdges, Kind: "price", UID: "0x3",
+			Edges: map[string][]string{"belongs_to_product": {"0x2"}, "belongs_to_city": {"0x1"}}},
+	}
+}
+
+func TestGraphCanBeVerified(test *testing.T) {
+	graph := writeTestGraph(test, testGraphRecords()...)
+
+	records, err := VerifyGraph(bytes.NewReader(graph))
+	if err != nil {
+		test.Error(err)
+	}
+
+	if records != len(testGraphRecords())+1 {
+		test.Fail()
+	}
+}
+
+func TestChangedGraphCanNotBeVerified(test *testing.T) {
+	graph := writeTestGraph(test, testGraphRecords()...)
+	changed := bytes.Replace(graph, []byte("123.5"), []byte("321.5"), 1)
+
+	_, err := VerifyGraph(bytes.NewReader(changed))
+	if !errors.Is(err, ErrGraphChecksumMismatch) {
+		test.Error(err)
+	}
+}
+
+func TestGraphWithoutFooterCanNotBeVerified(test *testing.T) {
+	graph := writeTestGraph(test, testGraphRecords()...)
+	lines := strings.SplitAfter(string(graph), "\n")
+	incomplete := strings.Join(lines[:len(lines)-2], "")
+
+	_, err := VerifyGraph(strings.NewReader(incomplete))
+	if !errors.Is(err, ErrGraphIsIncomplete) {
+		test.Error(err)
+	}
+}
+
+func TestGraphLiteralsAreQuoted(test *testing.T) {
+	literals := map[string]interface{}{
+		`"123.5"`:            123.5,
+		`"true"`:             true,
+		`"say \"hi\"\n"`:     "say \"hi\"\n",
+		`"Смартфоны"`:        "Смартфоны",
+		`"2017-05-01T16:27"`: "2017-05-01T16:27"}
+
+	for expected, value := range literals {
+		if graphLiteral(value) != expected {
+			test.Errorf("literal of %v must be %v, not %v", value, expected, graphLiteral(value))
+		}
+	}
+}
+
+func TestIntegrationGraphCanBeExported(test *testing.T) {
+	once.Do(prepareStorage)
+
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Graph export test city"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Cities.DeleteCity(context.Background(), createdCity)
+
+	buffer := bytes.Buffer{}
+	batches := 0
+	_, err = storage.ExportGraph(context.Background(), &buffer, GraphOptions{BatchSize: 2, Progress: func(GraphProgress) { batches++ }})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if batches == 0 {
+		test.Fail()
+	}
+
+	_, err = VerifyGraph(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		test.Error(err)
+	}
+
+	found := false
+	for _, line := range bytes.Split(buffer.Bytes(), []byte("\n")) {
+		record := GraphRecord{}
+		if json.Unmarshal(line, &record) != nil {
+			continue
+		}
+
+		if record.Kind == "city" && record.UID == createdCity.ID && record.Data["cityName@en"] == createdCity.Name {
+			found = true
+		}
+	}
+
+	if !found {
+		test.Fail()
+	}
+}
+
+func TestIntegrationGraphExportCanBeResumed(test *testing.T) {
+	once.Do(prepareStorage)
+
+	directory, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	completePath := filepath.Join(directory, "complete.ndjson")
+	_, err = storage.ExportGraphToFile(context.Background(), completePath, false, GraphOptions{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	complete, err := ioutil.ReadFile(completePath)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	lines := strings.SplitAfter(string(complete), "\n")
+	interrupted := strings.Join(lines[:len(lines)/2], "") + `{"type":"node","ki`
+
+	resumedPath := filepath.Join(directory, "resumed.ndjson")
+	err = ioutil.WriteFile(resumedPath, []byte(interrupted), 0644)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = storage.ExportGraphToFile(context.Background(), resumedPath, true, GraphOptions{BatchSize: 3})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	resumed, err := ioutil.ReadFile(resumedPath)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = VerifyGraph(bytes.NewReader(resumed))
+	if err != nil {
+		test.Error(err)
+	}
+
+	if !bytes.Equal(resumed, complete) {
+		test.Fail()
+	}
+}
+
+func TestIntegrationGraphCanBeImported(test *testing.T) {
+	once.Do(prepareStorage)
+
+	graph := writeTestGraph(test, testGraphRecords()...)
+
+	directory, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	checkpointPath := filepath.Join(directory, "checkpoint")
+
+	progress, err := storage.ImportGraph(context.Background(), bytes.NewReader(graph), GraphOptions{BatchSize: 1, CheckpointPath: checkpointPath})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if progress.Nodes != 3 || progress.Edges != 3 || progress.Skipped != 0 {
+		test.Fail()
+	}
+
+	if _, err = os.Stat(checkpointPath); !os.IsNotExist(err) {
+		test.Error("checkpoint must be removed after import")
+	}
+
+	products, err := storage.Products.ReadProductsByName(context.Background(), "Graph test product", "en", Projection{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(products) != 1 || products[0].IRI != "/graph/test" || products[0].ID == "0x2" {
+		test.Fatal()
+	}
+
+	if len(products[0].Prices) != 1 || products[0].Prices[0].Value != 123.5 {
+		test.Fail()
+	}
+
+	storage.Prices.DeletePrice(context.Background(), products[0].Prices[0])
+	storage.Products.DeleteProduct(context.Background(), products[0])
+
+	cities, err := storage.Cities.ReadCitiesByName(context.Background(), "Graph test city", "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	for _, city := range cities {
+		storage.Cities.DeleteCity(context.Background(), city)
+	}
+}
+
+func TestIntegrationGraphImportCanBeResumed(test *testing.T) {
+	once.Do(prepareStorage)
+
+	graph := writeTestGraph(test, testGraphRecords()...)
+
+	directory, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	checkpointPath := filepath.Join(directory, "checkpoint")
+
+	// Import is interrupted after nodes of graph
+	lines := strings.SplitAfter(string(graph), "\n")
+	interrupted := strings.Join(lines[:4], "")
+
+	_, err = storage.ImportGraph(context.Background(), strings.NewReader(interrupted), GraphOptions{BatchSize: 1, CheckpointPath: checkpointPath})
+	if !errors.Is(err, ErrGraphIsIncomplete) {
+		test.Fatal(err)
+	}
+
+	progress, err := storage.ImportGraph(context.Background(), bytes.NewReader(graph), GraphOptions{BatchSize: 1, CheckpointPath: checkpointPath})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if progress.Nodes != 0 || progress.Edges != 3 {
+		test.Fail()
+	}
+
+	products, err := storage.Products.ReadProductsByName(context.Background(), "Graph test product", "en", Projection{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(products) != 1 || len(products[0].Prices) != 1 {
+		test.Fatal()
+	}
+
+	storage.Prices.DeletePrice(context.Background(), products[0].Prices[0])
+	storage.Products.DeleteProduct(context.Background(), products[0])
+
+	cities, err := storage.Cities.ReadCitiesByName(context.Background(), "Graph test city", "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	for _, city := range cities {
+		storage.Cities.DeleteCity(context.Background(), city)
+	}
+}
+
+func TestOfferIsRekeyedByImportedUIDs(test *testing.T) {
+	state := graphImport{uids: map[string]string{"0x1": "0x11", "0x2": "0x12", "0x4": "0x14", "0x5": "0x15"}}
+
+	state.addEdges(GraphRecord{Type: graphRecordEdges, Kind: "offer", UID: "0x5",
+		Edges: map[string][]string{"belongs_to_product": {"0x2"}, "belongs_to_company": {"0x4"}, "belongs_to_city": {"0x1"}}})
+
+	if !strings.Contains(state.nquads.String(), `<0x15> <offerKey> "0x12/0x14/0x11" .`) {
+		test.Error(state.nquads.String())
+	}
+
+	state.nquads.Reset()
+	state.addEdges(GraphRecord{Type: graphRecordEdges, Kind: "offer", UID: "0x5",
+		Edges: map[string][]string{"belongs_to_product": {"0x2"}, "belongs_to_city": {"0x1"}}})
+
+	if strings.Contains(state.nquads.String(), "offerKey") {
+		test.Error("Offer without company must not be rekeyed")
+	}
+}
+
+func TestIntegrationPriceInCurrencyCanBeExportedAndImportedAsGraph(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Graph currency test product"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	price, err := storage.Prices.CreatePrice(ctx, Price{Value: 12.35, Amount: 1235, Currency: "USD",
+		DateTime: time.Date(2017, 5, 1, 16, 27, 18, 0, time.UTC), IsActive: true})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	err = storage.Products.AddPriceToProduct(ctx, product.ID, price.ID)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	buffer := bytes.Buffer{}
+	_, err = storage.ExportGraph(ctx, &buffer, GraphOptions{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var records []GraphRecord
+	for _, line := range bytes.Split(buffer.Bytes(), []byte("\n")) {
+		record := GraphRecord{}
+		if json.Unmarshal(line, &record) != nil {
+			continue
+		}
+
+		if record.UID == product.ID || record.UID == price.ID {
+			records = append(records, record)
+		}
+	}
+
+	storage.Prices.DeletePrice(ctx, price)
+	storage.Products.DeleteProduct(ctx, product)
+
+	_, err = storage.ImportGraph(ctx, bytes.NewReader(writeTestGraph(test, records...)), GraphOptions{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	products, err := storage.Products.ReadProductsByName(ctx, "Graph currency test product", "en", Projection{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(products) != 1 || len(products[0].Prices) != 1 {
+		test.Fatal(products)
+	}
+
+	imported := products[0].Prices[0]
+	if imported.Amount != 1235 || imported.Currency != "USD" || imported.Value != 12.35 {
+		test.Error(imported)
+	}
+
+	storage.Prices.DeletePrice(ctx, imported)
+	storage.Products.DeleteProduct(ctx, products[0])
+}
+
+func TestNodesOfResumableImportAreMarked(test *testing.T) {
+	state := graphImport{uids: map[string]string{}, blanks: map[string]string{}, importID: "a1b2"}
+
+	state.addNode(GraphRecord{Type: graphRecordNode, Kind: "city", UID: "0x1a",
+		Data: map[string]interface{}{"cityIsActive": true}})
+
+	if !strings.Contains(state.nquads.String(), `_:node1a <graphImportID> "a1b2/0x1a" .`) {
+		test.Error(state.nquads.String())
+	}
+}
+
+func TestMatchedNodesAreNotWrittenAgain(test *testing.T) {
+	nquads := []byte("_:node1 <cityIsActive> \"true\" .\n_:node10 <cityIsActive> \"true\" .\n<0x5> <has_price> <0x6> .\n")
+
+	kept := string(withoutBlankNodes(nquads, map[string]bool{"node1": true}))
+	if kept != "_:node10 <cityIsActive> \"true\" .\n<0x5> <has_price> <0x6> .\n" {
+		test.Error(kept)
+	}
+}
+
+func TestIDOfImportIsReadFromCheckpoint(test *testing.T) {
+	directory, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	path := filepath.Join(directory, "checkpoint")
+	err = ioutil.WriteFile(path, []byte(`{"line":0,"import":"a1b2"}`+"\n"+`{"line":3,"uids":{"0x1":"0x11"}}`+"\n"), 0644)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	line, uids, importID, err := readGraphCheckpoint(path)
+	if err != nil || line != 3 || uids["0x1"] != "0x11" || importID != "a1b2" {
+		test.Error(line, uids, importID, err)
+	}
+}
@@ -0,0 +1,468 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+)
+
+// ImportedNode is a node of exported JSON and uid of database which it was written to or matched with
+type ImportedNode struct {
+	Kind       string
+	ExportedID string
+	ID         string
+	Created    bool
+}
+
+// ImportReport is a mapping of exported uids to uids of database made by import
+type ImportReport struct {
+	Nodes []ImportedNode
+}
+
+// ID is a method for get uid of database for exported uid of node of kind
+func (report ImportReport) ID(kind, exportedID string) string {
+	for _, node := range report.Nodes {
+		if node.Kind == kind && node.ExportedID == exportedID {
+			return node.ID
+		}
+	}
+
+	return ""
+}
+
+// Created is a count of nodes which were not found in database and were made by import
+func (report ImportReport) Created() int {
+	count := 0
+	for _, node := range report.Nodes {
+		if node.Created {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Matched is a count of nodes which were found in database by natural keys
+func (report ImportReport) Matched() int {
+	return len(report.Nodes) - report.Created()
+}
+
+var (
+	// ErrImportedNodeWithoutKey means that the exported node has no fields for find it in database
+	ErrImportedNodeWithoutKey = errors.New("imported node can not be without natural key")
+
+	// ErrImportedNodeCanNotBeMatched means that the database can't be searched for exported node
+	ErrImportedNodeCanNotBeMatched = errors.New("imported node can not be matched")
+
+	// ErrImportedNodeCanNotBeCreated means that the exported node can't be written to database
+	ErrImportedNodeCanNotBeCreated = errors.New("imported node can not be created")
+)
+
+// importMapping resolves exported nodes to nodes of database by natural keys:
+// company by name and IRI, category and city by name, product by IRI or name if IRI is empty,
+// price by product, company, city and date, rate by currency. Missing nodes are made with blank nodes instead of exported uids.
+// Offers are not exported, they are updated by imported prices.
+type importMapping struct {
+	storage  *Storage
+	language string
+	report   ImportReport
+	ids      map[string]string
+}
+
+func newImportMapping(storage *Storage, language string) *importMapping {
+	return &importMapping{storage: storage, language: language, ids: map[string]string{}}
+}
+
+func (mapping *importMapping) resolved(kind, exportedID string) (string, bool) {
+	if exportedID == "" {
+		return "", false
+	}
+
+	id, ok := mapping.ids[kind+exportedID]
+	return id, ok
+}
+
+func (mapping *importMapping) add(kind, exportedID, id string, created bool) {
+	if exportedID != "" {
+		mapping.ids[kind+exportedID] = id
+	}
+
+	mapping.report.Nodes = append(mapping.report.Nodes,
+		ImportedNode{Kind: kind, ExportedID: exportedID, ID: id, Created: created})
+}
+
+// importPredicate is a value of predicate of node made by import
+type importPredicate struct {
+	Name     string
+	Value    interface{}
+	Language bool
+}
+
+func (mapping *importMapping) findNode(ctx context.Context, query string) (string, error) {
+	transaction := mapping.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrImportedNodeCanNotBeMatched, err)
+	}
+
+	type nodesInStorage struct {
+		Nodes []struct {
+			ID string `json:"uid"`
+		} `json:"nodes"`
+	}
+
+	var foundedNodes nodesInStorage
+	err = json.Unmarshal(response.GetJson(), &foundedNodes)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrImportedNodeCanNotBeMatched, err)
+	}
+
+	if len(foundedNodes.Nodes) == 0 {
+		return "", nil
+	}
+
+	return foundedNodes.Nodes[0].ID, nil
+}
+
+func (mapping *importMapping) createNode(ctx context.Context, kind, exportedID string, predicates []importPredicate) (string, error) {
+	blankNode := kind + strings.TrimPrefix(exportedID, "0x")
+
+	nquads := bytes.Buffer{}
+	for _, predicate := range predicates {
+		literal := graphLiteral(predicate.Value)
+		if predicate.Language {
+			literal += "@" + mapping.language
+		}
+
+		fmt.Fprintf(&nquads, "_:%s <%s> %s .\n", blankNode, predicate.Name, literal)
+	}
+
+	mutation := dataBaseAPI.Mutation{
+		SetNquads: nquads.Bytes(),
+		CommitNow: true}
+
+	transaction := mapping.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	assigned, err := transaction.Mutate(ctx, &mutation)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrImportedNodeCanNotBeCreated, err)
+	}
+
+	id := assigned.Uids[blankNode]
+	if id == "" {
+		return "", fail(ErrImportedNodeCanNotBeCreated)
+	}
+
+	mapping.add(kind, exportedID, id, true)
+
+	return id, nil
+}
+
+// link set edges between nodes, every edge is a pair of predicate and object for subject
+func (mapping *importMapping) link(ctx context.Context, subject string, edges ...string) error {
+	nquads := bytes.Buffer{}
+	for index := 0; index+1 < len(edges); index += 2 {
+		if edges[index+1] == "" {
+			continue
+		}
+
+		fmt.Fprintf(&nquads, "<%s> <%s> <%s> .\n", subject, edges[index], edges[index+1])
+	}
+
+	if nquads.Len() == 0 {
+		return nil
+	}
+
+	mutation := dataBaseAPI.Mutation{
+		SetNquads: nquads.Bytes(),
+		CommitNow: true}
+
+	transaction := mapping.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
+	if err != nil {
+		logError(err)
+		return wrap(ErrImportedNodeCanNotBeCreated, err)
+	}
+
+	return nil
+}
+
+func (mapping *importMapping) company(ctx context.Context, exported Company) (string, error) {
+	if id, ok := mapping.resolved("company", exported.ID); ok {
+		return id, nil
+	}
+
+	if exported.Name == "" {
+		return "", fail(ErrImportedNodeWithoutKey)
+	}
+
+	filter := ""
+	if exported.IRI != "" {
+		filter = fmt.Sprintf("@filter(eq(companyIri, %s))", strconv.Quote(exported.IRI))
+	}
+
+	id, err := mapping.findNode(ctx, fmt.Sprintf(`{
+				nodes(func: eq(companyName@%s, %s)) %s {
+					uid
+				}
+			}`, mapping.language, strconv.Quote(exported.Name), filter))
+	if err != nil {
+		return "", err
+	}
+
+	if id != "" {
+		mapping.add("company", exported.ID, id, false)
+		return id, nil
+	}
+
+	predicates := []importPredicate{
+		{Name: "companyName", Value: exported.Name, Language: true},
+		{Name: "companyIsActive", Value: exported.IsActive}}
+
+	if exported.IRI != "" {
+		predicates = append(predicates, importPredicate{Name: "companyIri", Value: exported.IRI})
+	}
+
+	return mapping.createNode(ctx, "company", exported.ID, predicates)
+}
+
+func (mapping *importMapping) category(ctx context.Context, exported Category) (string, error) {
+	if id, ok := mapping.resolved("category", exported.ID); ok {
+		return id, nil
+	}
+
+	if exported.Name == "" {
+		return "", fail(ErrImportedNodeWithoutKey)
+	}
+
+	id, err := mapping.findNode(ctx, fmt.Sprintf(`{
+				nodes(func: eq(categoryName@%s, %s)) {
+					uid
+				}
+			}`, mapping.language, strconv.Quote(exported.Name)))
+	if err != nil {
+		return "", err
+	}
+
+	if id != "" {
+		mapping.add("category", exported.ID, id, false)
+		return id, nil
+	}
+
+	return mapping.createNode(ctx, "category", exported.ID, []importPredicate{
+		{Name: "categoryName", Value: exported.Name, Language: true},
+		{Name: "categoryIsActive", Value: exported.IsActive}})
+}
+
+func (mapping *importMapping) city(ctx context.Context, exported City) (string, error) {
+	if id, ok := mapping.resolved("city", exported.ID); ok {
+		return id, nil
+	}
+
+	if exported.Name == "" {
+		return "", fail(ErrImportedNodeWithoutKey)
+	}
+
+	id, err := mapping.findNode(ctx, fmt.Sprintf(`{
+				nodes(func: eq(cityName@%s, %s)) {
+					uid
+				}
+			}`, mapping.language, strconv.Quote(exported.Name)))
+	if err != nil {
+		return "", err
+	}
+
+	if id != "" {
+		mapping.add("city", exported.ID, id, false)
+		return id, nil
+	}
+
+	return mapping.createNode(ctx, "city", exported.ID, []importPredicate{
+		{Name: "cityName", Value: exported.Name, Language: true},
+		{Name: "cityIsActive", Value: exported.IsActive}})
+}
+
+func (mapping *importMapping) product(ctx context.Context, exported Product) (string, error) {
+	if id, ok := mapping.resolved("product", exported.ID); ok {
+		return id, nil
+	}
+
+	var query string
+	switch {
+	case exported.IRI != "":
+		query = fmt.Sprintf(`{
+				nodes(func: eq(productIri, %s)) {
+					uid
+				}
+			}`, strconv.Quote(exported.IRI))
+	case exported.Name != "":
+		query = fmt.Sprintf(`{
+				nodes(func: eq(productName@%s, %s)) {
+					uid
+				}
+			}`, mapping.language, strconv.Quote(exported.Name))
+	default:
+		return "", fail(ErrImportedNodeWithoutKey)
+	}
+
+	id, err := mapping.findNode(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	if id != "" {
+		mapping.add("product", exported.ID, id, false)
+		return id, nil
+	}
+
+	predicates := []importPredicate{{Name: "productIsActive", Value: exported.IsActive}}
+
+	if exported.Name != "" {
+		predicates = append(predicates, importPredicate{Name: "productName", Value: exported.Name, Language: true})
+	}
+
+	if exported.IRI != "" {
+		predicates = append(predicates, importPredicate{Name: "productIri", Value: exported.IRI})
+	}
+
+	if exported.PreviewImageLink != "" {
+		predicates = append(predicates, importPredicate{Name: "previewImageLink", Value: exported.PreviewImageLink})
+	}
+
+	return mapping.createNode(ctx, "product", exported.ID, predicates)
+}
+
+// price find price of product with the same date, company and city, or make new price
+func (mapping *importMapping) price(ctx context.Context, exported Price, productID, companyID, cityID string) (string, error) {
+	if id, ok := mapping.resolved("price", exported.ID); ok {
+		return id, nil
+	}
+
+	query := fmt.Sprintf(`{
+				products(func: uid(%s)) {
+					has_price {
+						uid
+						priceDateTime
+						belongs_to_city {
+							uid
+						}
+						belongs_to_company {
+							uid
+						}
+					}
+				}
+			}`, productID)
+
+	transaction := mapping.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrImportedNodeCanNotBeMatched, err)
+	}
+
+	type productsInStorage struct {
+		Products []Product `json:"products"`
+	}
+
+	var foundedProducts productsInStorage
+	err = json.Unmarshal(response.GetJson(), &foundedProducts)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrImportedNodeCanNotBeMatched, err)
+	}
+
+	for _, product := range foundedProducts.Products {
+		for _, price := range product.Prices {
+			if !price.DateTime.Equal(exported.DateTime) {
+				continue
+			}
+
+			if cityID != "" && (len(price.Cities) == 0 || price.Cities[0].ID != cityID) {
+				continue
+			}
+
+			if companyID != "" && (len(price.Companies) == 0 || price.Companies[0].ID != companyID) {
+				continue
+			}
+
+			mapping.add("price", exported.ID, price.ID, false)
+			return price.ID, nil
+		}
+	}
+
+	predicates := []importPredicate{
+		{Name: "priceValue", Value: exported.Value},
+		{Name: "priceDateTime", Value: exported.DateTime.Format(time.RFC3339Nano)},
+		{Name: "priceIsActive", Value: exported.IsActive}}
+
+	if exported.Amount != 0 {
+		predicates = append(predicates, importPredicate{Name: "priceAmount", Value: exported.Amount})
+	}
+
+	if exported.Currency != "" {
+		predicates = append(predicates, importPredicate{Name: "priceCurrency", Value: exported.Currency})
+	}
+
+	if !exported.LastSeenAt.IsZero() {
+		predicates = append(predicates,
+			importPredicate{Name: "priceLastSeenAt", Value: exported.LastSeenAt.Format(time.RFC3339Nano)})
+	}
+
+	return mapping.createNode(ctx, "price", exported.ID, predicates)
+}
+
+// offer update offer of product in city of company by imported price, so the offer has the latest price
+// of source and target databases. Prices without company or city and inactive prices have no offers.
+func (mapping *importMapping) offer(ctx context.Context, exported Price, productID, companyID, cityID string) error {
+	if productID == "" || companyID == "" || cityID == "" || !exported.IsActive {
+		return nil
+	}
+
+	_, err := mapping.storage.Offers.UpdateOffer(ctx, productID, companyID, cityID, exported)
+	return err
+}
+
+// rate find rate of the same currency, or make new rate. Rate of database is not changed by imported one.
+func (mapping *importMapping) rate(ctx context.Context, exported Rate) (string, error) {
+	if id, ok := mapping.resolved("rate", exported.ID); ok {
+		return id, nil
+	}
+
+	if exported.Currency == "" {
+		return "", fail(ErrImportedNodeWithoutKey)
+	}
+
+	id, err := mapping.findNode(ctx, fmt.Sprintf(`{
+				nodes(func: eq(rateCurrency, %s)) {
+					uid
+				}
+			}`, strconv.Quote(exported.Currency)))
+	if err != nil {
+		return "", err
+	}
+
+	if id != "" {
+		mapping.add("rate", exported.ID, id, false)
+		return id, nil
+	}
+
+	defer mapping.storage.invalidate(ratesTag)
+
+	return mapping.createNode(ctx, "rate", exported.ID, []importPredicate{
+		{Name: "rateCurrency", Value: exported.Currency},
+		{Name: "rateValue", Value: exported.Value},
+		{Name: "rateDateTime", Value: exported.DateTime.Format(time.RFC3339Nano)}})
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+)
+
+// Modes of ingest of parsed prices
+const (
+	// RecordAll creates price for each parsed price
+	RecordAll = "all"
+
+	// RecordChanges creates price only when value differs from the latest price of product in city of company
+	// or heartbeat elapsed after it, otherwise the latest price is marked as seen
+	RecordChanges = "changes"
+)
+
+// IngestPolicy is a rule for record parsed prices, zero heartbeat records only changes of value.
+// Prices without currency are in DefaultCurrency, it is DefaultCurrency of storage when it is empty.
+type IngestPolicy struct {
+	Mode            string
+	Heartbeat       time.Duration
+	DefaultCurrency string
+}
+
+// MustRecord is a method for check that price must be created instead of mark the latest price as seen
+func (policy IngestPolicy) MustRecord(latest, price Price) bool {
+	if policy.Mode != RecordChanges || latest.ID == "" {
+		return true
+	}
+
+	if latest.Value != price.Value || policy.currencyOf(latest) != policy.currencyOf(price) ||
+		price.DateTime.Before(latest.DateTime) {
+		return true
+	}
+
+	return policy.Heartbeat > 0 && price.DateTime.Sub(latest.DateTime) >= policy.Heartbeat
+}
+
+// currencyOf return currency of price, prices saved before currencies have no currency
+func (policy IngestPolicy) currencyOf(price Price) string {
+	if price.Currency != "" {
+		return price.Currency
+	}
+
+	if policy.DefaultCurrency != "" {
+		return policy.DefaultCurrency
+	}
+
+	return DefaultCurrency
+}
+
+var (
+	// ErrLatestPriceCanNotBeRead means that the latest price of product in city of company can't be read
+	ErrLatestPriceCanNotBeRead = errors.New("latest price can not be read")
+
+	// ErrPriceCanNotBeMarkedAsSeen means that the time of the last parse can't be written to price
+	ErrPriceCanNotBeMarkedAsSeen = errors.New("price can not be marked as seen")
+)
+
+var latestPriceTemplate = template.Must(template.New("ReadLatestPrice").Parse(`{
+				products(func: uid({{.ProductID}})) {
+					has_price @filter(eq(priceIsActive, true) AND uid_in(belongs_to_company, {{.CompanyID}}) AND uid_in(belongs_to_city, {{.CityID}}))
+						(orderdesc: priceDateTime, first: 1) {
+						uid
+						priceValue
+						priceAmount
+						priceCurrency
+						priceDateTime
+						priceLastSeenAt
+						priceIsActive
+					}
+				}
+			}`))
+
+// ReadLatestPrice is a method for get active price of product in city of company with the latest date
+func (prices *Prices) ReadLatestPrice(ctx context.Context, productID, companyID, cityID string) (Price, error) {
+	for _, uid := range []string{productID, companyID, cityID} {
+		if !uidPattern.MatchString(uid) {
+			return Price{}, wrap(ErrLatestPriceCanNotBeRead, fmt.Errorf("uid must be like 0x1a, got %v", uid))
+		}
+	}
+
+	variables := struct {
+		ProductID, CompanyID, CityID string
+	}{
+		ProductID: productID,
+		CompanyID: companyID,
+		CityID:    cityID}
+
+	queryBuf := bytes.Buffer{}
+	err := latestPriceTemplate.Execute(&queryBuf, variables)
+	if err != nil {
+		logError(err)
+		return Price{}, wrap(ErrLatestPriceCanNotBeRead, err)
+	}
+
+	transaction := prices.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return Price{}, wrap(ErrLatestPriceCanNotBeRead, err)
+	}
+
+	var found struct {
+		Products []Product `json:"products"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return Price{}, wrap(ErrLatestPriceCanNotBeRead, err)
+	}
+
+	if len(found.Products) == 0 || len(found.Products[0].Prices) == 0 {
+		return Price{}, fail(ErrPriceDoesNotExist)
+	}
+
+	return found.Products[0].Prices[0], nil
+}
+
+// MarkPriceAsSeen is a method for write time of the last parse of the same value to price
+func (prices *Prices) MarkPriceAsSeen(ctx context.Context, priceID string, seenAt time.Time) error {
+	defer prices.storage.invalidate(priceID)
+
+	encodedPrice, err := json.Marshal(map[string]interface{}{"uid": priceID, "priceLastSeenAt": seenAt.UTC()})
+	if err != nil {
+		logError(err)
+		return wrap(ErrPriceCanNotBeMarkedAsSeen, err)
+	}
+
+	mutation := &dataBaseAPI.Mutation{
+		SetJson:   encodedPrice,
+		CommitNow: true}
+
+	transaction := prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
+
+	_, err = transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return wrap(ErrPriceCanNotBeMarkedAsSeen, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPriceIsRecordedByIngestPolicy(test *testing.T) {
+	dateTime := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	latest := Price{ID: "0x1", Value: 10, DateTime: dateTime}
+
+	cases := []struct {
+		policy IngestPolicy
+		latest Price
+		price  Price
+		record bool
+	}{
+		{IngestPolicy{Mode: RecordAll}, latest, Price{Value: 10, DateTime: dateTime.Add(time.Minute)}, true},
+		{IngestPolicy{Mode: RecordChanges}, Price{}, Price{Value: 10, DateTime: dateTime}, true},
+		{IngestPolicy{Mode: RecordChanges}, latest, Price{Value: 10, DateTime: dateTime.Add(time.Minute)}, false},
+		{IngestPolicy{Mode: RecordChanges}, latest, Price{Value: 11, DateTime: dateTime.Add(time.Minute)}, true},
+		{IngestPolicy{Mode: RecordChanges}, latest, Price{Value: 10, Currency: "USD", DateTime: dateTime.Add(time.Minute)}, true},
+		{IngestPolicy{Mode: RecordChanges}, latest, Price{Value: 10, DateTime: dateTime.Add(-time.Minute)}, true},
+		{IngestPolicy{Mode: RecordChanges, Heartbeat: time.Hour}, latest, Price{Value: 10, DateTime: dateTime.Add(time.Hour)}, true},
+		{IngestPolicy{Mode: RecordChanges, Heartbeat: time.Hour}, latest, Price{Value: 10, DateTime: dateTime.Add(time.Minute)}, false},
+		{IngestPolicy{Mode: RecordChanges}, latest, Price{Value: 10, Currency: "RUB", DateTime: dateTime.Add(time.Minute)}, false},
+		{IngestPolicy{Mode: RecordChanges, DefaultCurrency: "USD"}, latest, Price{Value: 10, Currency: "USD", DateTime: dateTime.Add(time.Minute)}, false},
+		{IngestPolicy{Mode: RecordChanges, DefaultCurrency: "USD"}, latest, Price{Value: 10, Currency: "RUB", DateTime: dateTime.Add(time.Minute)}, true},
+	}
+
+	for index, testCase := range cases {
+		if testCase.policy.MustRecord(testCase.latest, testCase.price) != testCase.record {
+			test.Error(index, testCase.policy, testCase.price)
+		}
+	}
+}
+
+func TestIntegrationLatestPriceCanBeMarkedAsSeen(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Ingest test product"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	company, err := storage.Companies.CreateCompany(ctx, Company{Name: "Ingest test company"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Companies.DeleteCompany(ctx, company)
+
+	city, err := storage.Cities.CreateCity(ctx, City{Name: "Ingest test city"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Cities.DeleteCity(ctx, city)
+
+	dateTime := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, value := range []float64{10, 12} {
+		price, err := storage.Prices.CreatePrice(ctx, Price{Value: value, DateTime: dateTime})
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		defer storage.Prices.DeletePrice(ctx, price)
+
+		err = storage.Products.AddPriceToProduct(ctx, product.ID, price.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		err = storage.Prices.AddCompanyToPrice(ctx, price.ID, company.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		err = storage.Prices.AddCityToPrice(ctx, price.ID, city.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		dateTime = dateTime.Add(time.Hour)
+	}
+
+	latest, err := storage.Prices.ReadLatestPrice(ctx, product.ID, company.ID, city.ID)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if latest.Value != 12 || !latest.LastSeenAt.Equal(latest.DateTime) {
+		test.Fatal(latest)
+	}
+
+	err = storage.Prices.MarkPriceAsSeen(ctx, latest.ID, dateTime)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	price, err := storage.Prices.ReadPriceByID(ctx, latest.ID, "en")
+	if err != nil || !price.LastSeenAt.Equal(dateTime) {
+		test.Error(price, err)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 
 	"bytes"
 	"text/template"
@@ -27,6 +26,43 @@ type PageInstruction struct {
 	CityInCookieKey            string `json:"cityInCookieKey,omitempty"`
 	CityIDForCookie            string `json:"cityIdForCookie,omitempty"`
 	PriceOfItemSelector        string `json:"priceOfItemSelector,omitempty"`
+	IsActive                   bool   `json:"pageInstructionIsActive"`
+}
+
+// pageInstructionForUpdate is a page instruction with activity which is written only when it is set,
+// so update of selectors can't activate or deactivate page instruction.
+// Cleared predicates are removed from page instruction, because empty strings are not written.
+type pageInstructionForUpdate struct {
+	PageInstruction
+	IsActive *bool    `json:"pageInstructionIsActive,omitempty"`
+	Cleared  []string `json:"-"`
+}
+
+// emptyPredicatesOf returns predicates of selectors and paths of page instruction which are empty
+func emptyPredicatesOf(pageInstruction PageInstruction) []string {
+	fields := []struct {
+		predicate, value string
+	}{
+		{"path", pageInstruction.Path},
+		{"pageInPaginationSelector", pageInstruction.PageInPaginationSelector},
+		{"previewImageOfSelector", pageInstruction.PreviewImageOfItemSelector},
+		{"pageParamPath", pageInstruction.PageParamPath},
+		{"cityParamPath", pageInstruction.CityParamPath},
+		{"itemSelector", pageInstruction.ItemSelector},
+		{"nameOfItemSelector", pageInstruction.NameOfItemSelector},
+		{"linkOfItemSelector", pageInstruction.LinkOfItemSelector},
+		{"cityInCookieKey", pageInstruction.CityInCookieKey},
+		{"cityIdForCookie", pageInstruction.CityIDForCookie},
+		{"priceOfItemSelector", pageInstruction.PriceOfItemSelector}}
+
+	var predicates []string
+	for _, field := range fields {
+		if field.value == "" {
+			predicates = append(predicates, field.predicate)
+		}
+	}
+
+	return predicates
 }
 
 // Instruction is a structure of instruction for parse
@@ -40,6 +76,14 @@ type Instruction struct {
 	Categories       []Category        `json:"has_category,omitempty"`
 }
 
+// instructionForUpdate is an instruction without edges with activity which is written only when it is set,
+// so update of language can't activate or deactivate instruction
+type instructionForUpdate struct {
+	ID       string `json:"uid,omitempty"`
+	Language string `json:"instructionLanguage,omitempty"`
+	IsActive *bool  `json:"instructionIsActive,omitempty"`
+}
+
 // NewInstructionsResourceForStorage is a constructor of Prices resource
 func NewInstructionsResourceForStorage(storage *Storage) *Instructions {
 	return &Instructions{storage: storage}
@@ -50,43 +94,16 @@ type Instructions struct {
 	storage *Storage
 }
 
-// SetUp is a method of Instructions resource for prepare database client and schema.
-func (resource *Instructions) SetUp() (err error) {
-	schema := `
-		instructionLanguage: string @index(term) .
-		instructionIsActive: bool @index(bool) .
-		has_company: uid @count .
-		has_city: uid @count .
-		has_page: uid @count .
-
-		path: string @index(term) .
-		pageInPaginationSelector: string @index(term) .
-		previewImageOfSelector: string @index(term) .
-		pageParamPath: string @index(term) .
-		pageCityPath: string @index(term) .
-		itemSelector: string @index(term) .
-		nameOfItemSelector: string @index(term) .
-		priceOfItemSelector: string @index(term) .
-		cityInCookieKey: string @index(term) .
-		cityIdForCookie: string @index(term) .
-	`
-	operation := &dataBaseAPI.Operation{Schema: schema}
-
-	err = resource.storage.Client.Alter(context.Background(), operation)
-	if err != nil {
-		log.Println(err)
-		return err
-	}
+// CreatePageInstruction make page instruction and save it to storage
+func (resource *Instructions) CreatePageInstruction(ctx context.Context, pageInstruction PageInstruction) (PageInstruction, error) {
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
-	return nil
-}
-
-func (resource *Instructions) CreatePageInstruction(pageInstruction PageInstruction) (PageInstruction, error) {
-	transaction := resource.storage.Client.NewTxn()
+	pageInstruction.IsActive = true
 
 	encodedPageInstruction, err := json.Marshal(pageInstruction)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return pageInstruction, err
 	}
 
@@ -94,9 +111,9 @@ func (resource *Instructions) CreatePageInstruction(pageInstruction PageInstruct
 		SetJson:   encodedPageInstruction,
 		CommitNow: true}
 
-	assigned, err := transaction.Mutate(context.Background(), mutation)
+	assigned, err := transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return pageInstruction, err
 	}
 
@@ -108,7 +125,7 @@ func (resource *Instructions) CreatePageInstruction(pageInstruction PageInstruct
 // ErrPageInstructionDoesNotExist means than the page instruction does not exist in database
 var ErrPageInstructionDoesNotExist = errors.New("page instruction by id not found")
 
-func (resource *Instructions) ReadPageInstructionByID(pageInstructionID string) (PageInstruction, error) {
+func (resource *Instructions) ReadPageInstructionByID(ctx context.Context, pageInstructionID string) (PageInstruction, error) {
 	pageInstruction := PageInstruction{ID: pageInstructionID}
 
 	query := fmt.Sprintf(`{
@@ -116,18 +133,24 @@ func (resource *Instructions) ReadPageInstructionByID(pageInstructionID string)
 					uid
 					path
 					pageInPaginationSelector
+					previewImageOfSelector
 					pageParamPath
 					cityParamPath
 					itemSelector
 					nameOfItemSelector
+					linkOfItemSelector
+					cityInCookieKey
+					cityIdForCookie
 					priceOfItemSelector
+					pageInstructionIsActive
 				}
 			}`, pageInstructionID)
 
-	transaction := resource.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), query)
+	transaction := resource.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return pageInstruction, err
 	}
 
@@ -139,21 +162,21 @@ func (resource *Instructions) ReadPageInstructionByID(pageInstructionID string)
 
 	err = json.Unmarshal(response.GetJson(), &foundedPageInstructions)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return pageInstruction, err
 	}
 
 	if len(foundedPageInstructions.PageInstructions) == 0 {
-		return pageInstruction, ErrPageInstructionDoesNotExist
+		return pageInstruction, fail(ErrPageInstructionDoesNotExist)
 	}
 
 	return foundedPageInstructions.PageInstructions[0], nil
 }
 
-func (resource *Instructions) DeletePageInstruction(pageInstruction PageInstruction) (string, error) {
+func (resource *Instructions) DeletePageInstruction(ctx context.Context, pageInstruction PageInstruction) (string, error) {
 	deletePageInstructionData, err := json.Marshal(map[string]string{"uid": pageInstruction.ID})
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return pageInstruction.ID, err
 	}
 
@@ -161,25 +184,126 @@ func (resource *Instructions) DeletePageInstruction(pageInstruction PageInstruct
 		DeleteJson: deletePageInstructionData,
 		CommitNow:  true}
 
-	transaction := resource.storage.Client.NewTxn()
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return pageInstruction.ID, err
 	}
 
 	return pageInstruction.ID, nil
 }
 
-func (resource *Instructions) CreateInstructionForCompany(companyID, language string) (Instruction, error) {
+var (
+	// ErrPageInstructionCanNotBeWithoutID means that page instruction can't be found in storage for make some operation
+	ErrPageInstructionCanNotBeWithoutID = errors.New("page instruction can not be without id")
+
+	// ErrPageInstructionCanNotBeUpdated means that page instruction can't be updated
+	ErrPageInstructionCanNotBeUpdated = errors.New("page instruction can not be updated")
+
+	// ErrPageInstructionCanNotBeDeactivate means that the page instruction can't be deactivate in database
+	ErrPageInstructionCanNotBeDeactivate = errors.New("page instruction can't be deactivate")
+
+	// ErrPageInstructionCanNotBeActivate means that the page instruction can't be activate in database
+	ErrPageInstructionCanNotBeActivate = errors.New("page instruction can't be activate")
+)
+
+// UpdatePageInstruction method for change selectors and paths of page instruction in storage.
+// Empty selectors and paths are removed from page instruction.
+// Activity of page instruction is changed only by Activate/Deactivate methods.
+func (resource *Instructions) UpdatePageInstruction(ctx context.Context, pageInstruction PageInstruction) (PageInstruction, error) {
+	return resource.updatePageInstruction(ctx, pageInstructionForUpdate{
+		PageInstruction: pageInstruction,
+		Cleared:         emptyPredicatesOf(pageInstruction)})
+}
+
+func (resource *Instructions) updatePageInstruction(ctx context.Context, update pageInstructionForUpdate) (PageInstruction, error) {
+	pageInstruction := update.PageInstruction
+	if pageInstruction.ID == "" {
+		return pageInstruction, fail(ErrPageInstructionCanNotBeWithoutID)
+	}
+
+	encodedPageInstruction, err := json.Marshal(update)
+	if err != nil {
+		logError(err)
+		return pageInstruction, wrap(ErrPageInstructionCanNotBeUpdated, err)
+	}
+
+	cleared := bytes.Buffer{}
+	for _, predicate := range update.Cleared {
+		fmt.Fprintf(&cleared, "<%s> <%s> * .\n", pageInstruction.ID, predicate)
+	}
+
+	mutation := &dataBaseAPI.Mutation{
+		SetJson:   encodedPageInstruction,
+		DelNquads: cleared.Bytes(),
+		CommitNow: true}
+
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return pageInstruction, wrap(ErrPageInstructionCanNotBeUpdated, err)
+	}
+
+	updatedPageInstruction, err := resource.ReadPageInstructionByID(ctx, pageInstruction.ID)
+	if err != nil {
+		logError(err)
+		return pageInstruction, wrap(ErrPageInstructionCanNotBeUpdated, err)
+	}
+
+	return updatedPageInstruction, nil
+}
+
+// DeactivatePageInstruction method for exclude page instruction from parse without removing it from database
+func (resource *Instructions) DeactivatePageInstruction(ctx context.Context, pageInstruction PageInstruction) (string, error) {
+	if pageInstruction.ID == "" {
+		return "", fail(ErrPageInstructionCanNotBeWithoutID)
+	}
+
+	isActive := false
+	update := pageInstructionForUpdate{PageInstruction: PageInstruction{ID: pageInstruction.ID}, IsActive: &isActive}
+
+	updatedPageInstruction, err := resource.updatePageInstruction(ctx, update)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrPageInstructionCanNotBeDeactivate, err)
+	}
+
+	return updatedPageInstruction.ID, nil
+}
+
+// ActivatePageInstruction method for return deactivated page instruction to parse
+func (resource *Instructions) ActivatePageInstruction(ctx context.Context, pageInstruction PageInstruction) (string, error) {
+	if pageInstruction.ID == "" {
+		return "", fail(ErrPageInstructionCanNotBeWithoutID)
+	}
+
+	isActive := true
+	update := pageInstructionForUpdate{PageInstruction: PageInstruction{ID: pageInstruction.ID}, IsActive: &isActive}
+
+	updatedPageInstruction, err := resource.updatePageInstruction(ctx, update)
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrPageInstructionCanNotBeActivate, err)
+	}
+
+	return updatedPageInstruction.ID, nil
+}
+
+// CreateInstructionForCompany make instruction of company and save it to storage
+func (resource *Instructions) CreateInstructionForCompany(ctx context.Context, companyID, language string) (Instruction, error) {
 	instruction := Instruction{IsActive: true, Language: language}
 
-	transaction := resource.storage.Client.NewTxn()
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	encodedInstruction, err := json.Marshal(instruction)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction, err
 	}
 
@@ -187,9 +311,9 @@ func (resource *Instructions) CreateInstructionForCompany(companyID, language st
 		SetJson:   encodedInstruction,
 		CommitNow: true}
 
-	assigned, err := transaction.Mutate(context.Background(), mutation)
+	assigned, err := transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction, err
 	}
 
@@ -200,13 +324,14 @@ func (resource *Instructions) CreateInstructionForCompany(companyID, language st
 		SetNquads: []byte(predicate),
 		CommitNow: true}
 
-	transaction = resource.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), mutation)
+	transaction = resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, mutation)
 	if err != nil {
 		return instruction, err
 	}
 
-	updatedInstruction, err := resource.ReadInstructionByID(instruction.ID, language)
+	updatedInstruction, err := resource.ReadInstructionByID(ctx, instruction.ID, language)
 	if err != nil {
 		return updatedInstruction, err
 	}
@@ -217,7 +342,7 @@ func (resource *Instructions) CreateInstructionForCompany(companyID, language st
 // ErrInstructionDoesNotExist means than the instruction does not exist in database
 var ErrInstructionDoesNotExist = errors.New("instruction by id not found")
 
-func (resource *Instructions) ReadInstructionByID(instructionID, language string) (Instruction, error) {
+func (resource *Instructions) ReadInstructionByID(ctx context.Context, instructionID, language string) (Instruction, error) {
 	variables := struct {
 		InstructionID string
 		Language      string
@@ -234,11 +359,16 @@ func (resource *Instructions) ReadInstructionByID(instructionID, language string
 						uid
 						path
 						pageInPaginationSelector
+						previewImageOfSelector
 						pageParamPath
 						cityParamPath
 						itemSelector
 						nameOfItemSelector
+						linkOfItemSelector
+						cityInCookieKey
+						cityIdForCookie
 						priceOfItemSelector
+						pageInstructionIsActive
 					}
 					has_city @filter(eq(cityIsActive, true)) {
 						uid
@@ -261,21 +391,22 @@ func (resource *Instructions) ReadInstructionByID(instructionID, language string
 	instruction := Instruction{ID: instructionID}
 
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction, err
 	}
 
 	queryBuf := bytes.Buffer{}
 	err = queryTemplate.Execute(&queryBuf, variables)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction, err
 	}
 
-	transaction := resource.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := resource.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction, err
 	}
 
@@ -287,21 +418,21 @@ func (resource *Instructions) ReadInstructionByID(instructionID, language string
 
 	err = json.Unmarshal(response.GetJson(), &foundedInstructions)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction, err
 	}
 
 	if len(foundedInstructions.Instructions) == 0 {
-		return instruction, ErrInstructionDoesNotExist
+		return instruction, fail(ErrInstructionDoesNotExist)
 	}
 
 	return foundedInstructions.Instructions[0], nil
 }
 
-func (resource *Instructions) DeleteInstruction(instruction Instruction) (string, error) {
+func (resource *Instructions) DeleteInstruction(ctx context.Context, instruction Instruction) (string, error) {
 	deleteInstructionData, err := json.Marshal(map[string]string{"uid": instruction.ID})
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction.ID, err
 	}
 
@@ -309,30 +440,120 @@ func (resource *Instructions) DeleteInstruction(instruction Instruction) (string
 		DeleteJson: deleteInstructionData,
 		CommitNow:  true}
 
-	transaction := resource.storage.Client.NewTxn()
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return instruction.ID, err
 	}
 
 	return instruction.ID, nil
 }
 
+var (
+	// ErrInstructionCanNotBeWithoutID means that instruction can't be found in storage for make some operation
+	ErrInstructionCanNotBeWithoutID = errors.New("instruction can not be without id")
+
+	// ErrInstructionCanNotBeUpdated means that instruction can't be updated
+	ErrInstructionCanNotBeUpdated = errors.New("instruction can not be updated")
+
+	// ErrInstructionCanNotBeDeactivate means that the instruction can't be deactivate in database
+	ErrInstructionCanNotBeDeactivate = errors.New("instruction can't be deactivate")
+
+	// ErrInstructionCanNotBeActivate means that the instruction can't be activate in database
+	ErrInstructionCanNotBeActivate = errors.New("instruction can't be activate")
+)
+
+// UpdateInstruction method for change language of instruction in storage.
+// Edges of instruction are changed by Add.../Remove... methods, activity by Activate/Deactivate methods.
+func (resource *Instructions) UpdateInstruction(ctx context.Context, instruction Instruction) (Instruction, error) {
+	return resource.updateInstruction(ctx, instruction, instructionForUpdate{ID: instruction.ID, Language: instruction.Language})
+}
+
+func (resource *Instructions) updateInstruction(ctx context.Context, instruction Instruction, update instructionForUpdate) (Instruction, error) {
+	if instruction.ID == "" {
+		return instruction, fail(ErrInstructionCanNotBeWithoutID)
+	}
+
+	encodedInstruction, err := json.Marshal(update)
+	if err != nil {
+		logError(err)
+		return instruction, wrap(ErrInstructionCanNotBeUpdated, err)
+	}
+
+	mutation := &dataBaseAPI.Mutation{
+		SetJson:   encodedInstruction,
+		CommitNow: true}
+
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return instruction, wrap(ErrInstructionCanNotBeUpdated, err)
+	}
+
+	updatedInstruction, err := resource.ReadInstructionByID(ctx, instruction.ID, ".")
+	if err != nil {
+		logError(err)
+		return instruction, wrap(ErrInstructionCanNotBeUpdated, err)
+	}
+
+	return updatedInstruction, nil
+}
+
+// DeactivateInstruction method for exclude instruction from parse without removing it from database
+func (resource *Instructions) DeactivateInstruction(ctx context.Context, instruction Instruction) (string, error) {
+	if instruction.ID == "" {
+		return "", fail(ErrInstructionCanNotBeWithoutID)
+	}
+
+	isActive := false
+
+	updatedInstruction, err := resource.updateInstruction(ctx, instruction,
+		instructionForUpdate{ID: instruction.ID, IsActive: &isActive})
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrInstructionCanNotBeDeactivate, err)
+	}
+
+	return updatedInstruction.ID, nil
+}
+
+// ActivateInstruction method for return deactivated instruction to parse
+func (resource *Instructions) ActivateInstruction(ctx context.Context, instruction Instruction) (string, error) {
+	if instruction.ID == "" {
+		return "", fail(ErrInstructionCanNotBeWithoutID)
+	}
+
+	isActive := true
+
+	updatedInstruction, err := resource.updateInstruction(ctx, instruction,
+		instructionForUpdate{ID: instruction.ID, IsActive: &isActive})
+	if err != nil {
+		logError(err)
+		return "", wrap(ErrInstructionCanNotBeActivate, err)
+	}
+
+	return updatedInstruction.ID, nil
+}
+
 // ErrCityCanNotBeAddedToInstruction means that the city can't be added to instruction
 var ErrCityCanNotBeAddedToInstruction = errors.New("city can not be added to instruction")
 
-func (resource *Instructions) AddCityToInstruction(instructionID, cityID string) error {
+func (resource *Instructions) AddCityToInstruction(ctx context.Context, instructionID, cityID string) error {
 	predicate := fmt.Sprintf(`<%s> <%s> <%s> .`, instructionID, "has_city", cityID)
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(predicate),
 		CommitNow: true}
 
-	transaction := resource.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCityCanNotBeAddedToInstruction
+		return wrap(ErrCityCanNotBeAddedToInstruction, err)
 	}
 
 	return nil
@@ -341,16 +562,17 @@ func (resource *Instructions) AddCityToInstruction(instructionID, cityID string)
 // ErrCityCanNotBeRemovedFromInstruction means that the city can't be removed from instruction
 var ErrCityCanNotBeRemovedFromInstruction = errors.New("city can not be removed from instruction")
 
-func (resource *Instructions) RemoveCityFromInstruction(instructionID, cityID string) error {
+func (resource *Instructions) RemoveCityFromInstruction(ctx context.Context, instructionID, cityID string) error {
 	predicate := fmt.Sprintf(`<%s> <%s> <%s> .`, instructionID, "has_city", cityID)
 	mutation := dataBaseAPI.Mutation{
 		DelNquads: []byte(predicate),
 		CommitNow: true}
 
-	transaction := resource.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCityCanNotBeRemovedFromInstruction
+		return wrap(ErrCityCanNotBeRemovedFromInstruction, err)
 	}
 
 	return nil
@@ -359,16 +581,17 @@ func (resource *Instructions) RemoveCityFromInstruction(instructionID, cityID st
 // ErrPageInstructionCanNotBeAddedToInstruction means that the page instruction can't be added to instruction
 var ErrPageInstructionCanNotBeAddedToInstruction = errors.New("page instruction can not be added to instruction")
 
-func (resource *Instructions) AddPageInstructionToInstruction(instructionID, pageInstructionID string) error {
+func (resource *Instructions) AddPageInstructionToInstruction(ctx context.Context, instructionID, pageInstructionID string) error {
 	predicate := fmt.Sprintf(`<%s> <%s> <%s> .`, instructionID, "has_page", pageInstructionID)
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(predicate),
 		CommitNow: true}
 
-	transaction := resource.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrPageInstructionCanNotBeAddedToInstruction
+		return wrap(ErrPageInstructionCanNotBeAddedToInstruction, err)
 	}
 
 	return nil
@@ -377,16 +600,17 @@ func (resource *Instructions) AddPageInstructionToInstruction(instructionID, pag
 // ErrPageInstructionCanNotBeRemovedFromInstruction means that the page instruction can't be removed from instruction
 var ErrPageInstructionCanNotBeRemovedFromInstruction = errors.New("page instruction can not be removed from instruction")
 
-func (resource *Instructions) RemovePageInstructionFromInstruction(instructionID, pageInstructionID string) error {
+func (resource *Instructions) RemovePageInstructionFromInstruction(ctx context.Context, instructionID, pageInstructionID string) error {
 	predicate := fmt.Sprintf(`<%s> <%s> <%s> .`, instructionID, "has_page", pageInstructionID)
 	mutation := dataBaseAPI.Mutation{
 		DelNquads: []byte(predicate),
 		CommitNow: true}
 
-	transaction := resource.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrPageInstructionCanNotBeRemovedFromInstruction
+		return wrap(ErrPageInstructionCanNotBeRemovedFromInstruction, err)
 	}
 
 	return nil
@@ -395,31 +619,33 @@ func (resource *Instructions) RemovePageInstructionFromInstruction(instructionID
 // ErrCategoryCanNotBeAddedToInstruction means that the category can't be added to instruction
 var ErrCategoryCanNotBeAddedToInstruction = errors.New("category can not be added to instruction")
 
-func (resource *Instructions) AddCategoryToInstruction(instructionID, categoryID string) error {
+func (resource *Instructions) AddCategoryToInstruction(ctx context.Context, instructionID, categoryID string) error {
 	predicate := fmt.Sprintf(`<%s> <%s> <%s> .`, instructionID, "has_category", categoryID)
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(predicate),
 		CommitNow: true}
 
-	transaction := resource.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCategoryCanNotBeAddedToInstruction
+		return wrap(ErrCategoryCanNotBeAddedToInstruction, err)
 	}
 
 	return nil
 }
 
-func (resource *Instructions) RemoveCategoryFromInstruction(instructionID, categoryID string) error {
+func (resource *Instructions) RemoveCategoryFromInstruction(ctx context.Context, instructionID, categoryID string) error {
 	predicate := fmt.Sprintf(`<%s> <%s> <%s> .`, instructionID, "has_category", categoryID)
 	mutation := dataBaseAPI.Mutation{
 		DelNquads: []byte(predicate),
 		CommitNow: true}
 
-	transaction := resource.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := resource.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCategoryCanNotBeAddedToInstruction
+		return wrap(ErrCategoryCanNotBeAddedToInstruction, err)
 	}
 
 	return nil
@@ -427,7 +653,9 @@ func (resource *Instructions) RemoveCategoryFromInstruction(instructionID, categ
 
 var ErrInstructionsForCompanyDoesNotExist = errors.New("instructions can not be founded for company")
 
-func (resource *Instructions) ReadAllInstructionsForCompany(companyID, language string) ([]Instruction, error) {
+// ReadAllInstructionsForCompany is a method for get active instructions of company with their active pages for parse.
+// Page instructions saved before their activity was added have no flag, so they are active unless deactivated.
+func (resource *Instructions) ReadAllInstructionsForCompany(ctx context.Context, companyID, language string) ([]Instruction, error) {
 
 	variables := struct {
 		CompanyID string
@@ -442,15 +670,20 @@ func (resource *Instructions) ReadAllInstructionsForCompany(companyID, language
 					uid
 					instructionLanguage
 					instructionIsActive
-					has_page {
+					has_page @filter(NOT eq(pageInstructionIsActive, false)) {
 						uid
 						path
 						pageInPaginationSelector
+						previewImageOfSelector
 						pageParamPath
 						cityParamPath
 						itemSelector
 						nameOfItemSelector
+						linkOfItemSelector
+						cityInCookieKey
+						cityIdForCookie
 						priceOfItemSelector
+						pageInstructionIsActive
 					}
 					has_company @filter(eq(companyIsActive, true)) {
 						uid
@@ -471,21 +704,22 @@ func (resource *Instructions) ReadAllInstructionsForCompany(companyID, language
 			}`)
 
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
 	queryBuf := bytes.Buffer{}
 	err = queryTemplate.Execute(&queryBuf, variables)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
-	transaction := resource.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := resource.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
@@ -497,23 +731,395 @@ func (resource *Instructions) ReadAllInstructionsForCompany(companyID, language
 
 	err = json.Unmarshal(response.GetJson(), &foundedInstructions)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
 	if len(foundedInstructions.Instructions) == 0 {
-		return nil, ErrInstructionsForCompanyDoesNotExist
+		return nil, fail(ErrInstructionsForCompanyDoesNotExist)
 	}
 
 	return foundedInstructions.Instructions, nil
 }
 
-// TODO
-//func (resource *Instructions) ReadInstructionsOfCompany(companyID, language string) ([]Instruction, error) {
-//
-//}
+// ReadInstructionsOfCompany is a method for get all instructions of company with all page instructions,
+// active and not active
+func (resource *Instructions) ReadInstructionsOfCompany(ctx context.Context, companyID, language string) ([]Instruction, error) {
+
+	variables := struct {
+		CompanyID string
+		Language  string
+	}{
+		CompanyID: companyID,
+		Language:  language}
+
+	queryTemplate, err := template.New("ReadInstructionsOfCompany").Parse(`{
+				instructions(func: has(has_company))
+				@filter(has(instructionLanguage) AND uid_in(has_company, {{.CompanyID}})) {
+					uid
+					instructionLanguage
+					instructionIsActive
+					has_page {
+						uid
+						path
+						pageInPaginationSelector
+						previewImageOfSelector
+						pageParamPath
+						cityParamPath
+						itemSelector
+						nameOfItemSelector
+						linkOfItemSelector
+						cityInCookieKey
+						cityIdForCookie
+						priceOfItemSelector
+						pageInstructionIsActive
+					}
+					has_company {
+						uid
+						companyName: companyName@{{.Language}}
+						companyIsActive
+					}
+					has_city {
+						uid
+						cityName: cityName@{{.Language}}
+						cityIsActive
+					}
+					has_category {
+						uid
+						categoryName: categoryName@{{.Language}}
+						categoryIsActive
+					}
+				}
+			}`)
+
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	queryBuf := bytes.Buffer{}
+	err = queryTemplate.Execute(&queryBuf, variables)
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	transaction := resource.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	type InstructionsFromStorage struct {
+		Instructions []Instruction `json:"instructions"`
+	}
+
+	var foundedInstructions InstructionsFromStorage
+
+	err = json.Unmarshal(response.GetJson(), &foundedInstructions)
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	if len(foundedInstructions.Instructions) == 0 {
+		return nil, fail(ErrInstructionsForCompanyDoesNotExist)
+	}
+
+	return foundedInstructions.Instructions, nil
+}
 
 //TODO
 //func (resource *Instructions) ReadInstructionOfCategoryForCompany(companyID, categoryID, language string) ([]Instruction, error) {
 //
 //}
+
+// ExportedInstruction is an instruction in exported JSON without uids of database.
+// Instruction is found by name of company and language on import, page instruction is found by path.
+type ExportedInstruction struct {
+	CompanyName string            `json:"companyName"`
+	CompanyIRI  string            `json:"companyIri,omitempty"`
+	Language    string            `json:"instructionLanguage"`
+	IsActive    bool              `json:"instructionIsActive"`
+	Cities      []string          `json:"cities,omitempty"`
+	Categories  []string          `json:"categories,omitempty"`
+	Pages       []PageInstruction `json:"pages,omitempty"`
+}
+
+type allExportedInstructions struct {
+	Language     string                `json:"language"`
+	Instructions []ExportedInstruction `json:"instructions"`
+}
+
+// ExportJSON is a method for export all instructions of companies with page instructions, cities and categories,
+// active and not active
+func (resource *Instructions) ExportJSON(ctx context.Context, language string) ([]byte, error) {
+	queryTemplate, err := template.New("ExportInstructions").Parse(`{
+				instructions(func: has(instructionLanguage)) @filter(has(has_company)) {
+					uid
+					instructionLanguage
+					instructionIsActive
+					has_page {
+						uid
+						path
+						pageInPaginationSelector
+						previewImageOfSelector
+						pageParamPath
+						cityParamPath
+						itemSelector
+						nameOfItemSelector
+						linkOfItemSelector
+						cityInCookieKey
+						cityIdForCookie
+						priceOfItemSelector
+						pageInstructionIsActive
+					}
+					has_company {
+						uid
+						companyName: companyName@{{.}}
+						companyIri
+						companyIsActive
+					}
+					has_city {
+						uid
+						cityName: cityName@{{.}}
+						cityIsActive
+					}
+					has_category {
+						uid
+						categoryName: categoryName@{{.}}
+						categoryIsActive
+					}
+				}
+			}`)
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	queryBuf := bytes.Buffer{}
+	err = queryTemplate.Execute(&queryBuf, language)
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	transaction := resource.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	type InstructionsFromStorage struct {
+		Instructions []Instruction `json:"instructions"`
+	}
+
+	var foundedInstructions InstructionsFromStorage
+	err = json.Unmarshal(response.GetJson(), &foundedInstructions)
+	if err != nil {
+		logError(err)
+		return nil, err
+	}
+
+	exportedInstructions := allExportedInstructions{Language: language, Instructions: []ExportedInstruction{}}
+
+	for _, instruction := range foundedInstructions.Instructions {
+		if len(instruction.Companies) == 0 || instruction.Companies[0].Name == "" {
+			continue
+		}
+
+		exportedInstruction := ExportedInstruction{
+			CompanyName: instruction.Companies[0].Name,
+			CompanyIRI:  instruction.Companies[0].IRI,
+			Language:    instruction.Language,
+			IsActive:    instruction.IsActive}
+
+		for _, city := range instruction.Cities {
+			exportedInstruction.Cities = append(exportedInstruction.Cities, city.Name)
+		}
+
+		for _, category := range instruction.Categories {
+			exportedInstruction.Categories = append(exportedInstruction.Categories, category.Name)
+		}
+
+		for _, page := range instruction.PagesInstruction {
+			page.ID = ""
+			exportedInstruction.Pages = append(exportedInstruction.Pages, page)
+		}
+
+		exportedInstructions.Instructions = append(exportedInstructions.Instructions, exportedInstruction)
+	}
+
+	jsonForExport, err := json.Marshal(exportedInstructions)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonForExport, nil
+}
+
+// ImportJSON is a method for add or update exported instructions.
+// Companies, cities and categories are found by name and made if they are not in database,
+// instructions are found by company and language, page instructions by path, so import can be repeated.
+func (resource *Instructions) ImportJSON(ctx context.Context, exportedInstructions []byte) error {
+	defer resource.storage.Cache.Purge()
+
+	var allInstructionsInJSON allExportedInstructions
+
+	err := json.Unmarshal(exportedInstructions, &allInstructionsInJSON)
+	if err != nil {
+		return err
+	}
+
+	for _, exportedInstruction := range allInstructionsInJSON.Instructions {
+		err = resource.importInstruction(ctx, exportedInstruction, allInstructionsInJSON.Language)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (resource *Instructions) importInstruction(ctx context.Context, exportedInstruction ExportedInstruction, language string) error {
+	company, err := resource.storage.Companies.CreateCompany(ctx,
+		Company{Name: exportedInstruction.CompanyName, IRI: exportedInstruction.CompanyIRI}, language)
+	if err != nil && !errors.Is(err, ErrCompanyAlreadyExist) {
+		return err
+	}
+
+	instructions, err := resource.ReadInstructionsOfCompany(ctx, company.ID, language)
+	if err != nil && !errors.Is(err, ErrInstructionsForCompanyDoesNotExist) {
+		return err
+	}
+
+	var instruction Instruction
+	for _, instructionOfCompany := range instructions {
+		if instructionOfCompany.Language == exportedInstruction.Language {
+			instruction = instructionOfCompany
+			break
+		}
+	}
+
+	if instruction.ID == "" {
+		instruction, err = resource.CreateInstructionForCompany(ctx, company.ID, exportedInstruction.Language)
+		if err != nil {
+			return err
+		}
+	}
+
+	if instruction.IsActive != exportedInstruction.IsActive {
+		if exportedInstruction.IsActive {
+			_, err = resource.ActivateInstruction(ctx, instruction)
+		} else {
+			_, err = resource.DeactivateInstruction(ctx, instruction)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	linkedCities := map[string]bool{}
+	for _, city := range instruction.Cities {
+		linkedCities[city.ID] = true
+	}
+
+	for _, cityName := range exportedInstruction.Cities {
+		city, err := resource.storage.Cities.CreateCity(ctx, City{Name: cityName}, language)
+		if err != nil && !errors.Is(err, ErrCityAlreadyExist) {
+			return err
+		}
+
+		if linkedCities[city.ID] {
+			continue
+		}
+
+		err = resource.AddCityToInstruction(ctx, instruction.ID, city.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	linkedCategories := map[string]bool{}
+	for _, category := range instruction.Categories {
+		linkedCategories[category.ID] = true
+	}
+
+	for _, categoryName := range exportedInstruction.Categories {
+		category, err := resource.storage.Categories.CreateCategory(ctx, Category{Name: categoryName}, language)
+		if err != nil && !errors.Is(err, ErrCategoryAlreadyExist) {
+			return err
+		}
+
+		if linkedCategories[category.ID] {
+			continue
+		}
+
+		err = resource.AddCategoryToInstruction(ctx, instruction.ID, category.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, exportedPage := range exportedInstruction.Pages {
+		err = resource.importPageInstruction(ctx, instruction, exportedPage)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (resource *Instructions) importPageInstruction(ctx context.Context, instruction Instruction, exportedPage PageInstruction) error {
+	for _, page := range instruction.PagesInstruction {
+		if page.Path != exportedPage.Path {
+			continue
+		}
+
+		exportedPage.ID = page.ID
+		if page == exportedPage {
+			return nil
+		}
+
+		_, err := resource.UpdatePageInstruction(ctx, exportedPage)
+		if err != nil || page.IsActive == exportedPage.IsActive {
+			return err
+		}
+
+		if exportedPage.IsActive {
+			_, err = resource.ActivatePageInstruction(ctx, exportedPage)
+			return err
+		}
+
+		_, err = resource.DeactivatePageInstruction(ctx, exportedPage)
+		return err
+	}
+
+	exportedPage.ID = ""
+	isActive := exportedPage.IsActive
+
+	createdPage, err := resource.CreatePageInstruction(ctx, exportedPage)
+	if err != nil {
+		return err
+	}
+
+	err = resource.AddPageInstructionToInstruction(ctx, instruction.ID, createdPage.ID)
+	if err != nil {
+		return err
+	}
+
+	if !isActive {
+		_, err = resource.DeactivatePageInstruction(ctx, createdPage)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
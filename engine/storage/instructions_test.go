@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -16,13 +19,13 @@ func TestIntegrationPageInstructionCanBeCreated(test *testing.T) {
 		NameOfItemSelector:       ".product-tile-title",
 		PriceOfItemSelector:      ".product-price-current"}
 
-	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(mVideoPageInstruction)
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeletePageInstruction(createdPageInstruction)
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
 		if err != nil {
 			test.Error(err)
 		}
@@ -44,19 +47,19 @@ func TestIntegrationPageInstructionCanBeReadById(test *testing.T) {
 		NameOfItemSelector:       ".product-tile-title",
 		PriceOfItemSelector:      ".product-price-current"}
 
-	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(mVideoPageInstruction)
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeletePageInstruction(createdPageInstruction)
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	pageInstructionFromStore, err := storage.Instructions.ReadPageInstructionByID(createdPageInstruction.ID)
+	pageInstructionFromStore, err := storage.Instructions.ReadPageInstructionByID(context.Background(), createdPageInstruction.ID)
 	if err != nil {
 		test.Fail()
 	}
@@ -78,12 +81,12 @@ func TestIntegrationPageInstructionCanBeDeleted(test *testing.T) {
 		NameOfItemSelector:       ".product-tile-title",
 		PriceOfItemSelector:      ".product-price-current"}
 
-	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(mVideoPageInstruction)
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
 	if err != nil {
 		test.Fail()
 	}
 
-	deletedPageInstructionID, err := storage.Instructions.DeletePageInstruction(createdPageInstruction)
+	deletedPageInstructionID, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
 	if err != nil {
 		test.Error(err)
 	}
@@ -92,8 +95,8 @@ func TestIntegrationPageInstructionCanBeDeleted(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = storage.Instructions.ReadPageInstructionByID(createdPageInstruction.ID)
-	if err != ErrPageInstructionDoesNotExist {
+	_, err = storage.Instructions.ReadPageInstructionByID(context.Background(), createdPageInstruction.ID)
+	if !errors.Is(err, ErrPageInstructionDoesNotExist) {
 		test.Error(err)
 	}
 }
@@ -101,25 +104,25 @@ func TestIntegrationPageInstructionCanBeDeleted(test *testing.T) {
 func TestIntegrationInstructionCanBeCreated(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 
 		if err != nil {
 			test.Fail()
@@ -146,24 +149,24 @@ func TestIntegrationInstructionCanBeCreated(test *testing.T) {
 func TestIntegrationInstructionCanBeDeleted(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	deletedInstructionID, err := storage.Instructions.DeleteInstruction(instruction)
+	deletedInstructionID, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 	if err != nil {
 		test.Error(err)
 	}
@@ -172,8 +175,8 @@ func TestIntegrationInstructionCanBeDeleted(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = storage.Instructions.ReadInstructionByID(instruction.ID, "en")
-	if err != ErrInstructionDoesNotExist {
+	_, err = storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
+	if !errors.Is(err, ErrInstructionDoesNotExist) {
 		test.Error(err)
 	}
 }
@@ -181,48 +184,48 @@ func TestIntegrationInstructionCanBeDeleted(test *testing.T) {
 func TestIntegrationCityCanBeAddedToInstruction(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	city, err := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	city, err := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(city)
+		_, err := storage.Cities.DeleteCity(context.Background(), city)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Instructions.AddCityToInstruction(instruction.ID, city.ID)
+	err = storage.Instructions.AddCityToInstruction(context.Background(), instruction.ID, city.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err := storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err := storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -243,48 +246,48 @@ func TestIntegrationCityCanBeAddedToInstruction(test *testing.T) {
 func TestIntegrationCityCanBeRemovedFromInstruction(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	city, err := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	city, err := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(city)
+		_, err := storage.Cities.DeleteCity(context.Background(), city)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Instructions.AddCityToInstruction(instruction.ID, city.ID)
+	err = storage.Instructions.AddCityToInstruction(context.Background(), instruction.ID, city.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err := storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err := storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -297,12 +300,12 @@ func TestIntegrationCityCanBeRemovedFromInstruction(test *testing.T) {
 		test.Fail()
 	}
 
-	err = storage.Instructions.RemoveCityFromInstruction(instruction.ID, city.ID)
+	err = storage.Instructions.RemoveCityFromInstruction(context.Background(), instruction.ID, city.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err = storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err = storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -315,25 +318,25 @@ func TestIntegrationCityCanBeRemovedFromInstruction(test *testing.T) {
 func TestIntegrationPageInstructionCanBeAddedToInstruction(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
@@ -348,24 +351,24 @@ func TestIntegrationPageInstructionCanBeAddedToInstruction(test *testing.T) {
 		NameOfItemSelector:       ".product-tile-title",
 		PriceOfItemSelector:      ".product-price-current"}
 
-	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(mVideoPageInstruction)
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeletePageInstruction(createdPageInstruction)
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Instructions.AddPageInstructionToInstruction(instruction.ID, createdPageInstruction.ID)
+	err = storage.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, createdPageInstruction.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err := storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err := storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -382,25 +385,25 @@ func TestIntegrationPageInstructionCanBeAddedToInstruction(test *testing.T) {
 func TestIntegrationPageInstructionCanBeRemovedFromInstruction(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
@@ -415,20 +418,20 @@ func TestIntegrationPageInstructionCanBeRemovedFromInstruction(test *testing.T)
 		NameOfItemSelector:       ".product-tile-title",
 		PriceOfItemSelector:      ".product-price-current"}
 
-	createdPageInstruction, _ := storage.Instructions.CreatePageInstruction(mVideoPageInstruction)
+	createdPageInstruction, _ := storage.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
 	defer func() {
-		_, err := storage.Instructions.DeletePageInstruction(createdPageInstruction)
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Instructions.AddPageInstructionToInstruction(instruction.ID, createdPageInstruction.ID)
+	err = storage.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, createdPageInstruction.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err := storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err := storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -441,12 +444,12 @@ func TestIntegrationPageInstructionCanBeRemovedFromInstruction(test *testing.T)
 		test.Fail()
 	}
 
-	err = storage.Instructions.RemovePageInstructionFromInstruction(instruction.ID, createdPageInstruction.ID)
+	err = storage.Instructions.RemovePageInstructionFromInstruction(context.Background(), instruction.ID, createdPageInstruction.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err = storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err = storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -459,48 +462,48 @@ func TestIntegrationPageInstructionCanBeRemovedFromInstruction(test *testing.T)
 func TestIntegrationCategoryCanBeAddedToInstruction(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	category, err := storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+	category, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(category)
+		_, err := storage.Categories.DeleteCategory(context.Background(), category)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Instructions.AddCategoryToInstruction(instruction.ID, category.ID)
+	err = storage.Instructions.AddCategoryToInstruction(context.Background(), instruction.ID, category.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err := storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err := storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -517,48 +520,48 @@ func TestIntegrationCategoryCanBeAddedToInstruction(test *testing.T) {
 func TestIntegrationCategoryCanBeRemovedFromInstruction(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	category, err := storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+	category, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(category)
+		_, err := storage.Categories.DeleteCategory(context.Background(), category)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Instructions.AddCategoryToInstruction(instruction.ID, category.ID)
+	err = storage.Instructions.AddCategoryToInstruction(context.Background(), instruction.ID, category.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err := storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err := storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -571,12 +574,12 @@ func TestIntegrationCategoryCanBeRemovedFromInstruction(test *testing.T) {
 		test.Fail()
 	}
 
-	err = storage.Instructions.RemoveCategoryFromInstruction(instruction.ID, category.ID)
+	err = storage.Instructions.RemoveCategoryFromInstruction(context.Background(), instruction.ID, category.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedInstruction, err = storage.Instructions.ReadInstructionByID(instruction.ID, "en")
+	updatedInstruction, err = storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -589,55 +592,55 @@ func TestIntegrationCategoryCanBeRemovedFromInstruction(test *testing.T) {
 func TestIntegrationCanGetAllInstructionsOfCompany(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	anotherCompany, err := storage.Companies.CreateCompany(Company{Name: "Another test company"}, "en")
+	anotherCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Another test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(anotherCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), anotherCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	anotherInstruction, err := storage.Instructions.CreateInstructionForCompany(anotherCompany.ID, "en")
+	anotherInstruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), anotherCompany.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(anotherInstruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), anotherInstruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instructionsForCompany, err := storage.Instructions.ReadAllInstructionsForCompany(company.ID, "en")
+	instructionsForCompany, err := storage.Instructions.ReadAllInstructionsForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -654,65 +657,65 @@ func TestIntegrationCanGetAllInstructionsOfCompany(test *testing.T) {
 func TestIntegrationCanGetFullInstructionForCompany(test *testing.T) {
 	once.Do(prepareStorage)
 
-	company, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(company)
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	instruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "en")
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(instruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	category, err := storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+	category, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(category)
+		_, err := storage.Categories.DeleteCategory(context.Background(), category)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Companies.AddCategoryToCompany(company.ID, category.ID)
+	err = storage.Companies.AddCategoryToCompany(context.Background(), company.ID, category.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = storage.Instructions.AddCategoryToInstruction(instruction.ID, category.ID)
+	err = storage.Instructions.AddCategoryToInstruction(context.Background(), instruction.ID, category.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	city, err := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	city, err := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(city)
+		_, err := storage.Cities.DeleteCity(context.Background(), city)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Instructions.AddCityToInstruction(instruction.ID, city.ID)
+	err = storage.Instructions.AddCityToInstruction(context.Background(), instruction.ID, city.ID)
 	if err != nil {
 		test.Error(err)
 	}
@@ -726,24 +729,24 @@ func TestIntegrationCanGetFullInstructionForCompany(test *testing.T) {
 		NameOfItemSelector:       ".product-tile-title",
 		PriceOfItemSelector:      ".product-price-current"}
 
-	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(mVideoPageInstruction)
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeletePageInstruction(createdPageInstruction)
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Instructions.AddPageInstructionToInstruction(instruction.ID, createdPageInstruction.ID)
+	err = storage.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, createdPageInstruction.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	instructionForCompany, err := storage.Instructions.ReadAllInstructionsForCompany(company.ID, "en")
+	instructionForCompany, err := storage.Instructions.ReadAllInstructionsForCompany(context.Background(), company.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -752,41 +755,41 @@ func TestIntegrationCanGetFullInstructionForCompany(test *testing.T) {
 		test.Fail()
 	}
 
-	err = storage.Companies.AddLanguageOfCompanyName(company.ID, "Тестовая компания", "ru")
+	err = storage.Companies.AddLanguageOfCompanyName(context.Background(), company.ID, "Тестовая компания", "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
-	secondInstruction, err := storage.Instructions.CreateInstructionForCompany(company.ID, "ru")
+	secondInstruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Instructions.DeleteInstruction(secondInstruction)
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), secondInstruction)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	secondCity, err := storage.Cities.CreateCity(City{Name: "Тестовый город"}, "ru")
+	secondCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Тестовый город"}, "ru")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(secondCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), secondCity)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Instructions.AddCityToInstruction(secondInstruction.ID, secondCity.ID)
+	err = storage.Instructions.AddCityToInstruction(context.Background(), secondInstruction.ID, secondCity.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	instructionForCompany, err = storage.Instructions.ReadAllInstructionsForCompany(company.ID, "ru")
+	instructionForCompany, err = storage.Instructions.ReadAllInstructionsForCompany(context.Background(), company.ID, "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -795,3 +798,576 @@ func TestIntegrationCanGetFullInstructionForCompany(test *testing.T) {
 		test.Fail()
 	}
 }
+
+func TestIntegrationPageInstructionCanBeUpdated(test *testing.T) {
+	once.Do(prepareStorage)
+
+	mVideoPageInstruction := PageInstruction{
+		Path:                     "smartfony-i-svyaz/smartfony-205",
+		PageInPaginationSelector: ".pagination-list .pagination-item",
+		PageParamPath:            "/f/page=",
+		CityParamPath:            "?cityId=",
+		ItemSelector:             ".grid-view .product-tile",
+		NameOfItemSelector:       ".product-tile-title",
+		PriceOfItemSelector:      ".product-price-current"}
+
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), mVideoPageInstruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	if !createdPageInstruction.IsActive {
+		test.Fail()
+	}
+
+	createdPageInstruction.ItemSelector = ".c-product-tile"
+	createdPageInstruction.LinkOfItemSelector = ".c-product-tile__description .sel-product-tile-title"
+
+	updatedPageInstruction, err := storage.Instructions.UpdatePageInstruction(context.Background(), createdPageInstruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if updatedPageInstruction.ID != createdPageInstruction.ID {
+		test.Fail()
+	}
+
+	if updatedPageInstruction.ItemSelector != ".c-product-tile" {
+		test.Fail()
+	}
+
+	if updatedPageInstruction.LinkOfItemSelector != ".c-product-tile__description .sel-product-tile-title" {
+		test.Fail()
+	}
+
+	if updatedPageInstruction.Path != mVideoPageInstruction.Path {
+		test.Fail()
+	}
+
+	if !updatedPageInstruction.IsActive {
+		test.Fail()
+	}
+
+	_, err = storage.Instructions.UpdatePageInstruction(context.Background(), PageInstruction{Path: "/test/"})
+	if !errors.Is(err, ErrPageInstructionCanNotBeWithoutID) {
+		test.Error(err)
+	}
+}
+
+func TestIntegrationPageInstructionCanBeDeactivatedAndActivated(test *testing.T) {
+	once.Do(prepareStorage)
+
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), PageInstruction{Path: "/test/"})
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	err = storage.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, createdPageInstruction.ID)
+	if err != nil {
+		test.Error(err)
+	}
+
+	deactivatedPageInstructionID, err := storage.Instructions.DeactivatePageInstruction(context.Background(), createdPageInstruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if deactivatedPageInstructionID != createdPageInstruction.ID {
+		test.Fail()
+	}
+
+	pageInstructionFromStore, err := storage.Instructions.ReadPageInstructionByID(context.Background(), createdPageInstruction.ID)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if pageInstructionFromStore.IsActive {
+		test.Fail()
+	}
+
+	if pageInstructionFromStore.Path != "/test/" {
+		test.Fail()
+	}
+
+	instructionsForCompany, err := storage.Instructions.ReadAllInstructionsForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	if len(instructionsForCompany) != 1 {
+		test.Fatal()
+	}
+
+	if len(instructionsForCompany[0].PagesInstruction) != 0 {
+		test.Fail()
+	}
+
+	_, err = storage.Instructions.ActivatePageInstruction(context.Background(), createdPageInstruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	instructionsForCompany, err = storage.Instructions.ReadAllInstructionsForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	if len(instructionsForCompany) != 1 {
+		test.Fatal()
+	}
+
+	if len(instructionsForCompany[0].PagesInstruction) != 1 {
+		test.Fatal()
+	}
+
+	if instructionsForCompany[0].PagesInstruction[0].ID != createdPageInstruction.ID {
+		test.Fail()
+	}
+}
+
+func TestIntegrationInstructionCanBeUpdated(test *testing.T) {
+	once.Do(prepareStorage)
+
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	instruction.Language = "ru"
+
+	updatedInstruction, err := storage.Instructions.UpdateInstruction(context.Background(), instruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if updatedInstruction.ID != instruction.ID {
+		test.Fail()
+	}
+
+	if updatedInstruction.Language != "ru" {
+		test.Fail()
+	}
+
+	if !updatedInstruction.IsActive {
+		test.Fail()
+	}
+
+	if len(updatedInstruction.Companies) != 1 {
+		test.Fatal()
+	}
+
+	if updatedInstruction.Companies[0].ID != company.ID {
+		test.Fail()
+	}
+
+	_, err = storage.Instructions.UpdateInstruction(context.Background(), Instruction{Language: "en"})
+	if !errors.Is(err, ErrInstructionCanNotBeWithoutID) {
+		test.Error(err)
+	}
+}
+
+func TestIntegrationInstructionCanBeDeactivatedAndActivated(test *testing.T) {
+	once.Do(prepareStorage)
+
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	deactivatedInstructionID, err := storage.Instructions.DeactivateInstruction(context.Background(), instruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if deactivatedInstructionID != instruction.ID {
+		test.Fail()
+	}
+
+	instructionFromStore, err := storage.Instructions.ReadInstructionByID(context.Background(), instruction.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	if instructionFromStore.IsActive {
+		test.Fail()
+	}
+
+	_, err = storage.Instructions.ReadAllInstructionsForCompany(context.Background(), company.ID, "en")
+	if !errors.Is(err, ErrInstructionsForCompanyDoesNotExist) {
+		test.Error(err)
+	}
+
+	_, err = storage.Instructions.ActivateInstruction(context.Background(), instruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	instructionsForCompany, err := storage.Instructions.ReadAllInstructionsForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	if len(instructionsForCompany) != 1 {
+		test.Fatal()
+	}
+
+	if instructionsForCompany[0].ID != instruction.ID {
+		test.Fail()
+	}
+}
+
+func TestIntegrationInstructionsCanBeExportedAndImportedManyTimes(test *testing.T) {
+	once.Do(prepareStorage)
+
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Exported instruction company", IRI: "http://company/"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Companies.DeleteCompany(context.Background(), company)
+
+	city, err := storage.Cities.CreateCity(context.Background(), City{Name: "Exported instruction city"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Cities.DeleteCity(context.Background(), city)
+
+	category, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Exported instruction category"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Categories.DeleteCategory(context.Background(), category)
+
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = storage.Instructions.AddCityToInstruction(context.Background(), instruction.ID, city.ID)
+	if err != nil {
+		test.Error(err)
+	}
+
+	err = storage.Instructions.AddCategoryToInstruction(context.Background(), instruction.ID, category.ID)
+	if err != nil {
+		test.Error(err)
+	}
+
+	activePage, err := storage.Instructions.CreatePageInstruction(context.Background(), 
+		PageInstruction{Path: "smartphones", ItemSelector: ".item"})
+	if err != nil {
+		test.Error(err)
+	}
+
+	inactivePage, err := storage.Instructions.CreatePageInstruction(context.Background(), 
+		PageInstruction{Path: "tablets", ItemSelector: ".item"})
+	if err != nil {
+		test.Error(err)
+	}
+
+	_, err = storage.Instructions.DeactivatePageInstruction(context.Background(), inactivePage)
+	if err != nil {
+		test.Error(err)
+	}
+
+	for _, page := range []PageInstruction{activePage, inactivePage} {
+		err = storage.Instructions.AddPageInstructionToInstruction(context.Background(), instruction.ID, page.ID)
+		if err != nil {
+			test.Error(err)
+		}
+	}
+
+	exportedJSON, err := storage.Instructions.ExportJSON(context.Background(), "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	storage.Instructions.DeletePageInstruction(context.Background(), activePage)
+	storage.Instructions.DeletePageInstruction(context.Background(), inactivePage)
+	storage.Instructions.DeleteInstruction(context.Background(), instruction)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err = storage.Instructions.ImportJSON(context.Background(), exportedJSON)
+		if err != nil {
+			test.Error(err)
+		}
+	}
+
+	instructions, err := storage.Instructions.ReadInstructionsOfCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(instructions) != 1 {
+		test.Fatal()
+	}
+
+	importedInstruction := instructions[0]
+
+	defer func() {
+		for _, page := range importedInstruction.PagesInstruction {
+			storage.Instructions.DeletePageInstruction(context.Background(), page)
+		}
+
+		storage.Instructions.DeleteInstruction(context.Background(), importedInstruction)
+	}()
+
+	if importedInstruction.Language != "en" || !importedInstruction.IsActive {
+		test.Fail()
+	}
+
+	if len(importedInstruction.Cities) != 1 || importedInstruction.Cities[0].ID != city.ID {
+		test.Fail()
+	}
+
+	if len(importedInstruction.Categories) != 1 || importedInstruction.Categories[0].ID != category.ID {
+		test.Fail()
+	}
+
+	if len(importedInstruction.PagesInstruction) != 2 {
+		test.Fatal()
+	}
+
+	for _, page := range importedInstruction.PagesInstruction {
+		if page.ItemSelector != ".item" {
+			test.Fail()
+		}
+
+		if page.IsActive != (page.Path == "smartphones") {
+			test.Fail()
+		}
+	}
+}
+
+func TestUpdateOfInstructionsDoesNotWriteActivity(test *testing.T) {
+	encoded, err := json.Marshal(pageInstructionForUpdate{PageInstruction: PageInstruction{ID: "0x1", ItemSelector: ".item"}})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if string(encoded) != `{"uid":"0x1","itemSelector":".item"}` {
+		test.Error(string(encoded))
+	}
+
+	isActive := false
+	encoded, err = json.Marshal(pageInstructionForUpdate{PageInstruction: PageInstruction{ID: "0x1"}, IsActive: &isActive})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if string(encoded) != `{"uid":"0x1","pageInstructionIsActive":false}` {
+		test.Error(string(encoded))
+	}
+
+	encoded, err = json.Marshal(instructionForUpdate{ID: "0x2", Language: "ru"})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if string(encoded) != `{"uid":"0x2","instructionLanguage":"ru"}` {
+		test.Error(string(encoded))
+	}
+}
+
+func TestEmptySelectorsOfPageInstructionAreCleared(test *testing.T) {
+	predicates := emptyPredicatesOf(PageInstruction{
+		Path:                       "smartphones",
+		PageInPaginationSelector:   ".pagination",
+		PreviewImageOfItemSelector: ".preview",
+		PageParamPath:              "?page=",
+		CityParamPath:              "?city=",
+		ItemSelector:               ".item",
+		NameOfItemSelector:         ".name",
+		LinkOfItemSelector:         ".link",
+		CityInCookieKey:            "city",
+		PriceOfItemSelector:        ".price"})
+
+	if len(predicates) != 1 || predicates[0] != "cityIdForCookie" {
+		test.Error(predicates)
+	}
+
+	if len(emptyPredicatesOf(PageInstruction{})) != 11 {
+		test.Error(emptyPredicatesOf(PageInstruction{}))
+	}
+}
+
+func TestIntegrationSelectorOfPageInstructionCanBeCleared(test *testing.T) {
+	once.Do(prepareStorage)
+
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(),
+		PageInstruction{Path: "/test/", ItemSelector: ".item", CityInCookieKey: "city"})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	updatedPageInstruction, err := storage.Instructions.UpdatePageInstruction(context.Background(),
+		PageInstruction{ID: createdPageInstruction.ID, Path: "/test/", ItemSelector: ".item"})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if updatedPageInstruction.CityInCookieKey != "" || updatedPageInstruction.ItemSelector != ".item" ||
+		updatedPageInstruction.Path != "/test/" || !updatedPageInstruction.IsActive {
+		test.Error(updatedPageInstruction)
+	}
+}
+
+func TestIntegrationPartialUpdateKeepsActivityOfInstructions(test *testing.T) {
+	once.Do(prepareStorage)
+
+	company, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Companies.DeleteCompany(context.Background(), company)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	instruction, err := storage.Instructions.CreateInstructionForCompany(context.Background(), company.ID, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeleteInstruction(context.Background(), instruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	createdPageInstruction, err := storage.Instructions.CreatePageInstruction(context.Background(), PageInstruction{Path: "/test/"})
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		_, err := storage.Instructions.DeletePageInstruction(context.Background(), createdPageInstruction)
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	updatedPageInstruction, err := storage.Instructions.UpdatePageInstruction(context.Background(),
+		PageInstruction{ID: createdPageInstruction.ID, ItemSelector: ".c-product-tile"})
+	if err != nil {
+		test.Error(err)
+	}
+
+	if !updatedPageInstruction.IsActive || updatedPageInstruction.ItemSelector != ".c-product-tile" {
+		test.Fail()
+	}
+
+	_, err = storage.Instructions.DeactivatePageInstruction(context.Background(), createdPageInstruction)
+	if err != nil {
+		test.Error(err)
+	}
+
+	updatedPageInstruction, err = storage.Instructions.UpdatePageInstruction(context.Background(),
+		PageInstruction{ID: createdPageInstruction.ID, ItemSelector: ".product-tile", IsActive: true})
+	if err != nil {
+		test.Error(err)
+	}
+
+	if updatedPageInstruction.IsActive {
+		test.Fail()
+	}
+
+	updatedInstruction, err := storage.Instructions.UpdateInstruction(context.Background(),
+		Instruction{ID: instruction.ID, Language: "ru"})
+	if err != nil {
+		test.Error(err)
+	}
+
+	if !updatedInstruction.IsActive || updatedInstruction.Language != "ru" {
+		test.Fail()
+	}
+}
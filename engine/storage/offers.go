@@ -0,0 +1,555 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+	"github.com/hecatoncheir/Sproot/engine/money"
+)
+
+// Offer is a current price of product in city of company. It is updated by each price which is newer
+// than the current one, so the latest value is read without reading and sorting all prices of product.
+// Amount is exact value in minor units of currency, ReferenceAmount is value in minor units of reference
+// currency of storage by rates, so offers in different currencies are ordered by database.
+type Offer struct {
+	ID              string    `json:"uid,omitempty"`
+	Key             string    `json:"offerKey,omitempty"`
+	Value           float64   `json:"offerValue"`
+	PreviousValue   float64   `json:"offerPreviousValue,omitempty"`
+	Amount          int64     `json:"offerAmount,omitempty"`
+	Currency        string    `json:"offerCurrency,omitempty"`
+	ReferenceAmount int64     `json:"offerReferenceAmount,omitempty"`
+	DateTime        time.Time `json:"offerDateTime"`
+	Products        []Product `json:"belongs_to_product,omitempty"`
+	Companies       []Company `json:"belongs_to_company,omitempty"`
+	Cities          []City    `json:"belongs_to_city,omitempty"`
+}
+
+// OfferFilter is a condition for select offers, empty uids are not used.
+// Offers are ordered by value from the cheapest or from the most expensive one when Descending is set,
+// zero First means all offers. Values are converted to Currency and ordered by reference amounts when it is set.
+type OfferFilter struct {
+	ProductID  string `json:"productID,omitempty"`
+	CompanyID  string `json:"companyID,omitempty"`
+	CityID     string `json:"cityID,omitempty"`
+	Descending bool   `json:"descending,omitempty"`
+	First      int    `json:"first,omitempty"`
+	Offset     int    `json:"offset,omitempty"`
+	Currency   string `json:"currency,omitempty"`
+}
+
+// Offers is resource of storage for current prices of products
+type Offers struct {
+	storage *Storage
+}
+
+// NewOffersResourceForStorage is a constructor of Offers resource
+func NewOffersResourceForStorage(storage *Storage) *Offers {
+	return &Offers{storage: storage}
+}
+
+var (
+	// ErrOfferCanNotBeUpdated means that the offer can't be created or updated by price
+	ErrOfferCanNotBeUpdated = errors.New("offer can not be updated")
+
+	// ErrOffersCanNotBeRead means that the offers can't be read from database
+	ErrOffersCanNotBeRead = errors.New("offers can not be read")
+
+	// ErrOfferFilterIsNotValid means that uid of filter of offers is not like 0x1a or first or offset is negative
+	ErrOfferFilterIsNotValid = errors.New("filter of offers is not valid")
+
+	// ErrOffersCanNotBeRebuilt means that the offers can't be updated by prices of database
+	ErrOffersCanNotBeRebuilt = errors.New("offers can not be rebuilt")
+
+	// ErrOfferCanNotBeMarkedAsSeen means that the time of the last parse can't be written to offer
+	ErrOfferCanNotBeMarkedAsSeen = errors.New("offer can not be marked as seen")
+)
+
+// offerUpdateAttempts is a count of attempts of update of offer when transaction is aborted by conflict
+const offerUpdateAttempts = 3
+
+// offerKey is a natural key of offer of product in city of company
+func offerKey(productID, companyID, cityID string) string {
+	return strings.Join([]string{productID, companyID, cityID}, "/")
+}
+
+// UpdateOffer is a method for set price as current offer of product in city of company when the price
+// is newer than current offer, value of current offer becomes previous value.
+// Offer is created by the first price of product in city of company.
+func (offers *Offers) UpdateOffer(ctx context.Context, productID, companyID, cityID string, price Price) (Offer, error) {
+	defer offers.storage.invalidate(productID)
+
+	var offer Offer
+	var err error
+
+	for attempt := 1; attempt <= offerUpdateAttempts; attempt++ {
+		offer, err = offers.updateOffer(ctx, productID, companyID, cityID, price)
+		if err == nil || KindOf(err) != Conflict {
+			break
+		}
+	}
+
+	return offer, err
+}
+
+// updateOffer read offer and change it in one transaction, so concurrent updates of offer are conflicted
+func (offers *Offers) updateOffer(ctx context.Context, productID, companyID, cityID string, price Price) (Offer, error) {
+	key := offerKey(productID, companyID, cityID)
+	amount := price.MoneyOf(offers.storage.PricesCurrency())
+	offer := Offer{Key: key, Value: price.Value, Amount: amount.Amount, Currency: amount.Currency, DateTime: price.DateTime}
+
+	rates, err := offers.storage.Rates.ReadRateTable(ctx)
+	if err != nil {
+		return offer, wrap(ErrOfferCanNotBeUpdated, err)
+	}
+
+	offer.ReferenceAmount, _ = referenceAmountOf(amount, rates)
+
+	query := fmt.Sprintf(`{
+				offers(func: eq(offerKey, "%v")) {
+					uid
+					offerValue
+					offerDateTime
+				}
+			}`, key)
+
+	transaction := offers.storage.newTransaction()
+	defer transaction.Discard(ctx)
+
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return offer, wrap(ErrOfferCanNotBeUpdated, err)
+	}
+
+	var found struct {
+		Offers []Offer `json:"offers"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return offer, wrap(ErrOfferCanNotBeUpdated, err)
+	}
+
+	var change interface{}
+
+	if len(found.Offers) > 0 {
+		current := found.Offers[0]
+		if !price.DateTime.After(current.DateTime) {
+			current.Key = key
+			return current, nil
+		}
+
+		offer.ID = current.ID
+		offer.PreviousValue = current.Value
+
+		change = map[string]interface{}{
+			"uid":                  offer.ID,
+			"offerValue":           offer.Value,
+			"offerPreviousValue":   offer.PreviousValue,
+			"offerAmount":          offer.Amount,
+			"offerCurrency":        offer.Currency,
+			"offerReferenceAmount": offer.ReferenceAmount,
+			"offerDateTime":        offer.DateTime}
+	} else {
+		change = map[string]interface{}{
+			"uid": productID,
+			"has_offer": map[string]interface{}{
+				"uid":                  "_:offer",
+				"offerKey":             key,
+				"offerValue":           offer.Value,
+				"offerAmount":          offer.Amount,
+				"offerCurrency":        offer.Currency,
+				"offerReferenceAmount": offer.ReferenceAmount,
+				"offerDateTime":        offer.DateTime,
+				"belongs_to_product":   map[string]string{"uid": productID},
+				"belongs_to_company":   map[string]string{"uid": companyID},
+				"belongs_to_city":      map[string]string{"uid": cityID}}}
+	}
+
+	encodedChange, err := json.Marshal(change)
+	if err != nil {
+		logError(err)
+		return offer, wrap(ErrOfferCanNotBeUpdated, err)
+	}
+
+	mutation := &dataBaseAPI.Mutation{
+		SetJson:   encodedChange,
+		CommitNow: true}
+
+	assigned, err := transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return offer, wrap(ErrOfferCanNotBeUpdated, err)
+	}
+
+	if offer.ID == "" {
+		offer.ID = assigned.Uids["offer"]
+	}
+
+	return offer, nil
+}
+
+// MarkOfferAsSeen is a method for move date of offer of product in city of company to time of the last parse
+// of the same value, so offer is not stale when parsed price is not recorded. Offer which is newer is not changed.
+func (offers *Offers) MarkOfferAsSeen(ctx context.Context, productID, companyID, cityID string, seenAt time.Time) error {
+	defer offers.storage.invalidate(productID)
+
+	query := fmt.Sprintf(`{
+				offers(func: eq(offerKey, "%v")) {
+					uid
+					offerDateTime
+				}
+			}`, offerKey(productID, companyID, cityID))
+
+	transaction := offers.storage.newTransaction()
+	defer transaction.Discard(ctx)
+
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return wrap(ErrOfferCanNotBeMarkedAsSeen, err)
+	}
+
+	var found struct {
+		Offers []Offer `json:"offers"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return wrap(ErrOfferCanNotBeMarkedAsSeen, err)
+	}
+
+	if len(found.Offers) == 0 || !seenAt.After(found.Offers[0].DateTime) {
+		return nil
+	}
+
+	encodedOffer, err := json.Marshal(map[string]interface{}{"uid": found.Offers[0].ID, "offerDateTime": seenAt.UTC()})
+	if err != nil {
+		logError(err)
+		return wrap(ErrOfferCanNotBeMarkedAsSeen, err)
+	}
+
+	mutation := &dataBaseAPI.Mutation{
+		SetJson:   encodedOffer,
+		CommitNow: true}
+
+	_, err = transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return wrap(ErrOfferCanNotBeMarkedAsSeen, err)
+	}
+
+	return nil
+}
+
+var uidPattern = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+
+var offersTemplate = template.Must(template.New("ReadOffers").Parse(`{
+				offers(func: has(offerKey), {{if .Descending}}orderdesc{{else}}orderasc{{end}}: {{if .Currency}} offerReferenceAmount {{else}} offerValue {{end}}{{if .First}}, first: {{.First}}{{end}}{{if .Offset}}, offset: {{.Offset}}{{end}})
+				{{if .Conditions}}@filter({{.Conditions}}){{end}} {
+					uid
+					offerKey
+					offerValue
+					offerPreviousValue
+					offerAmount
+					offerCurrency
+					offerReferenceAmount
+					offerDateTime
+					belongs_to_product @filter(eq(productIsActive, true)) {
+						uid
+						productName: productName@{{.Language}}
+						productIri
+						previewImageLink
+						productIsActive
+					}
+					belongs_to_company @filter(eq(companyIsActive, true)) {
+						uid
+						companyName: companyName@{{.Language}}
+						companyIri
+						companyIsActive
+					}
+					belongs_to_city @filter(eq(cityIsActive, true)) {
+						uid
+						cityName: cityName@{{.Language}}
+						cityIsActive
+					}
+				}
+			}`))
+
+// ReadOffers is a method for get current offers matched by filter and ordered by value
+func (offers *Offers) ReadOffers(ctx context.Context, filter OfferFilter, language string) ([]Offer, error) {
+	if filter.First < 0 || filter.Offset < 0 {
+		return nil, wrap(ErrOfferFilterIsNotValid, fmt.Errorf("first and offset must not be negative"))
+	}
+
+	if filter.Currency != "" {
+		err := money.ValidateCurrency(filter.Currency)
+		if err != nil {
+			return nil, wrap(ErrOfferFilterIsNotValid, err)
+		}
+	}
+
+	var conditions []string
+	for _, edge := range []struct{ predicate, uid string }{
+		{"belongs_to_product", filter.ProductID},
+		{"belongs_to_company", filter.CompanyID},
+		{"belongs_to_city", filter.CityID}} {
+		if edge.uid == "" {
+			continue
+		}
+
+		if !uidPattern.MatchString(edge.uid) {
+			return nil, wrap(ErrOfferFilterIsNotValid, fmt.Errorf("uid must be like 0x1a, got %v", edge.uid))
+		}
+
+		conditions = append(conditions, fmt.Sprintf("uid_in(%v, %v)", edge.predicate, edge.uid))
+	}
+
+	variables := struct {
+		OfferFilter
+		Conditions, Language string
+	}{
+		OfferFilter: filter,
+		Conditions:  strings.Join(conditions, " AND "),
+		Language:    language}
+
+	queryBuf := bytes.Buffer{}
+	err := offersTemplate.Execute(&queryBuf, variables)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrOffersCanNotBeRead, err)
+	}
+
+	transaction := offers.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrOffersCanNotBeRead, err)
+	}
+
+	var found struct {
+		Offers []Offer `json:"offers"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrOffersCanNotBeRead, err)
+	}
+
+	if filter.Currency == "" {
+		return found.Offers, nil
+	}
+
+	rates, err := offers.storage.Rates.ReadRateTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = convertOffers(found.Offers, rates, filter.Currency, offers.storage.PricesCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	return found.Offers, nil
+}
+
+// referenceAmountOf return amount in minor units of reference currency of rates, false when rate is not set
+func referenceAmountOf(amount money.Money, rates money.Rates) (int64, bool) {
+	converted, err := rates.Convert(amount, rates.Reference)
+	if err != nil {
+		return 0, false
+	}
+
+	return converted.Amount, true
+}
+
+const offersRecalculationBatchSize = 1000
+
+// RecalculateReferenceAmounts is a method for update reference amounts of all offers by the current rates and
+// fill exact amounts of offers saved before them, it is called when rate is set.
+// Offers in currencies without rate are not changed. Count of changed offers is returned.
+func (offers *Offers) RecalculateReferenceAmounts(ctx context.Context) (int, error) {
+	rates, err := offers.storage.Rates.ReadRateTable(ctx)
+	if err != nil {
+		return 0, wrap(ErrOffersCanNotBeRebuilt, err)
+	}
+
+	count, after := 0, ""
+
+	for {
+		afterArgument := ""
+		if after != "" {
+			afterArgument = ", after: " + after
+		}
+
+		query := fmt.Sprintf(`{
+				offers(func: has(offerKey), first: %v%v) {
+					uid
+					offerValue
+					offerAmount
+					offerCurrency
+					offerReferenceAmount
+					belongs_to_product {
+						uid
+					}
+				}
+			}`, offersRecalculationBatchSize, afterArgument)
+
+		transaction := offers.storage.newReadTransaction(ctx)
+		response, err := transaction.Query(ctx, query)
+		transaction.Discard(ctx)
+		if err != nil {
+			logError(err)
+			return count, wrap(ErrOffersCanNotBeRebuilt, err)
+		}
+
+		var found struct {
+			Offers []Offer `json:"offers"`
+		}
+
+		err = json.Unmarshal(response.GetJson(), &found)
+		if err != nil {
+			logError(err)
+			return count, wrap(ErrOffersCanNotBeRebuilt, err)
+		}
+
+		nquads := bytes.Buffer{}
+		var changed []string
+		for _, offer := range found.Offers {
+			amount := Price{Value: offer.Value, Amount: offer.Amount, Currency: offer.Currency}.MoneyOf(offers.storage.PricesCurrency())
+
+			referenceAmount, ok := referenceAmountOf(amount, rates)
+			if !ok || offer.Amount == amount.Amount && offer.Currency == amount.Currency && offer.ReferenceAmount == referenceAmount {
+				continue
+			}
+
+			fmt.Fprintf(&nquads, "<%s> <offerAmount> \"%d\" .\n<%s> <offerCurrency> %q .\n<%s> <offerReferenceAmount> \"%d\" .\n",
+				offer.ID, amount.Amount, offer.ID, amount.Currency, offer.ID, referenceAmount)
+
+			count++
+			for _, product := range offer.Products {
+				changed = append(changed, product.ID)
+			}
+		}
+
+		if nquads.Len() > 0 {
+			err = offers.setNquads(ctx, nquads.Bytes(), changed)
+			if err != nil {
+				return count, err
+			}
+		}
+
+		if len(found.Offers) < offersRecalculationBatchSize {
+			return count, nil
+		}
+
+		after = found.Offers[len(found.Offers)-1].ID
+	}
+}
+
+// setNquads write N-Quads of offers and remove cached products of offers
+func (offers *Offers) setNquads(ctx context.Context, nquads []byte, productIDs []string) error {
+	defer offers.storage.invalidate(productIDs...)
+
+	mutation := &dataBaseAPI.Mutation{
+		SetNquads: nquads,
+		CommitNow: true}
+
+	transaction := offers.storage.newTransaction()
+	defer transaction.Discard(ctx)
+
+	_, err := transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return wrap(ErrOffersCanNotBeRebuilt, err)
+	}
+
+	return nil
+}
+
+const offersRebuildBatchSize = 1000
+
+// RebuildOffers is a method for update offers by all active prices of database which have product,
+// company and city, it is used for databases with prices which were saved before offers.
+// Count of read prices is returned.
+func (offers *Offers) RebuildOffers(ctx context.Context) (int, error) {
+	type pricesInStorage struct {
+		Prices []Price `json:"prices"`
+	}
+
+	count, after := 0, ""
+
+	for {
+		afterArgument := ""
+		if after != "" {
+			afterArgument = ", after: " + after
+		}
+
+		query := fmt.Sprintf(`{
+				prices(func: has(priceValue), first: %v%v) @filter(eq(priceIsActive, true)) {
+					uid
+					priceValue
+					priceAmount
+					priceCurrency
+					priceDateTime
+					belongs_to_product {
+						uid
+					}
+					belongs_to_company {
+						uid
+					}
+					belongs_to_city {
+						uid
+					}
+				}
+			}`, offersRebuildBatchSize, afterArgument)
+
+		transaction := offers.storage.newReadTransaction(ctx)
+		response, err := transaction.Query(ctx, query)
+		transaction.Discard(ctx)
+		if err != nil {
+			logError(err)
+			return count, wrap(ErrOffersCanNotBeRebuilt, err)
+		}
+
+		var found pricesInStorage
+		err = json.Unmarshal(response.GetJson(), &found)
+		if err != nil {
+			logError(err)
+			return count, wrap(ErrOffersCanNotBeRebuilt, err)
+		}
+
+		for _, price := range found.Prices {
+			count++
+
+			if len(price.Products) == 0 || len(price.Companies) == 0 || len(price.Cities) == 0 {
+				continue
+			}
+
+			_, err = offers.UpdateOffer(ctx, price.Products[0].ID, price.Companies[0].ID, price.Cities[0].ID, price)
+			if err != nil {
+				return count, wrap(ErrOffersCanNotBeRebuilt, err)
+			}
+		}
+
+		if len(found.Prices) < offersRebuildBatchSize {
+			_, err = offers.RecalculateReferenceAmounts(ctx)
+			return count, err
+		}
+
+		after = found.Prices[len(found.Prices)-1].ID
+	}
+}
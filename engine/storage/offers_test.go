@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIntegrationOfferIsUpdatedByNewerPrices(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Offer test product"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	company, err := storage.Companies.CreateCompany(ctx, Company{Name: "Offer test company"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Companies.DeleteCompany(ctx, company)
+
+	city, err := storage.Cities.CreateCity(ctx, City{Name: "Offer test city"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Cities.DeleteCity(ctx, city)
+
+	dateTime := time.Date(2018, 1, 2, 10, 0, 0, 0, time.UTC)
+	for _, price := range []Price{
+		{Value: 10, DateTime: dateTime},
+		{Value: 5, DateTime: dateTime.Add(-time.Hour)},
+		{Value: 12, DateTime: dateTime.Add(time.Hour)}} {
+		_, err = storage.Offers.UpdateOffer(ctx, product.ID, company.ID, city.ID, price)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	offers, err := storage.Offers.ReadOffers(ctx, OfferFilter{ProductID: product.ID, CityID: city.ID}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(offers) != 1 || offers[0].Value != 12 || offers[0].PreviousValue != 10 {
+		test.Fatal(offers)
+	}
+
+	if !offers[0].DateTime.Equal(dateTime.Add(time.Hour)) || offers[0].Cities[0].Name != "Offer test city" {
+		test.Error(offers[0])
+	}
+
+	for _, seenAt := range []time.Time{dateTime.Add(3 * time.Hour), dateTime.Add(2 * time.Hour)} {
+		err = storage.Offers.MarkOfferAsSeen(ctx, product.ID, company.ID, city.ID, seenAt)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	offers, err = storage.Offers.ReadOffers(ctx, OfferFilter{ProductID: product.ID, CityID: city.ID}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(offers) != 1 || offers[0].Value != 12 || offers[0].PreviousValue != 10 || !offers[0].DateTime.Equal(dateTime.Add(3*time.Hour)) {
+		test.Error(offers)
+	}
+}
+
+func TestOfferFilterMustHaveUids(test *testing.T) {
+	offers := &Offers{}
+
+	for _, filter := range []OfferFilter{{CityID: "0x1) OR has(productName"}, {First: -1}} {
+		_, err := offers.ReadOffers(context.Background(), filter, "en")
+		if !errors.Is(err, ErrOfferFilterIsNotValid) || KindOf(err) != InvalidInput {
+			test.Error(filter, err)
+		}
+	}
+
+	if offerKey("0x1", "0x2", "0x3") != "0x1/0x2/0x3" {
+		test.Error(offerKey("0x1", "0x2", "0x3"))
+	}
+}
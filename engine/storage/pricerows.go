@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// PriceRow is a price with names of its product, category, company and city in one flat structure
+type PriceRow struct {
+	ProductName  string
+	ProductIRI   string
+	CategoryName string
+	CompanyName  string
+	CityName     string
+	Value        float64
+	Currency     string
+	DateTime     time.Time
+}
+
+// PriceFilter is a condition for select prices, empty fields are not used.
+// Names are compared with names in language of request.
+type PriceFilter struct {
+	From         time.Time `json:"from,omitempty"`
+	To           time.Time `json:"to,omitempty"`
+	CompanyName  string    `json:"companyName,omitempty"`
+	CategoryName string    `json:"categoryName,omitempty"`
+	CityName     string    `json:"cityName,omitempty"`
+}
+
+var (
+	// ErrPriceRowsCanNotBeRead means that the prices can't be read from database
+	ErrPriceRowsCanNotBeRead = errors.New("price rows can not be read")
+)
+
+const priceRowsBatchSize = 1000
+
+var priceFilterNodesTemplate = template.Must(template.New("ReadPriceFilterNodes").Parse(`{
+				{{if .CompanyName}}
+				companies(func: eq(companyName@{{.Language}}, {{.CompanyName}})) {
+					uid
+				}
+				{{end}}
+				{{if .CategoryName}}
+				categories(func: eq(categoryName@{{.Language}}, {{.CategoryName}})) {
+					uid
+				}
+				{{end}}
+				{{if .CityName}}
+				cities(func: eq(cityName@{{.Language}}, {{.CityName}})) {
+					uid
+				}
+				{{end}}
+			}`))
+
+// priceFilterNodes are uids of companies, categories and cities which names are set in filter of prices
+type priceFilterNodes struct {
+	Companies  []Company  `json:"companies"`
+	Categories []Category `json:"categories"`
+	Cities     []City     `json:"cities"`
+}
+
+// readPriceFilterNodes find uids of nodes by names of filter, so prices are filtered by edges in database.
+// False is returned when some name of filter has no node, so no price can be matched.
+func (prices *Prices) readPriceFilterNodes(ctx context.Context, filter PriceFilter, language string) (priceFilterNodes, bool, error) {
+	var nodes priceFilterNodes
+
+	if filter.CompanyName == "" && filter.CategoryName == "" && filter.CityName == "" {
+		return nodes, true, nil
+	}
+
+	variables := struct {
+		CompanyName, CategoryName, CityName, Language string
+	}{Language: language}
+
+	if filter.CompanyName != "" {
+		variables.CompanyName = strconv.Quote(filter.CompanyName)
+	}
+
+	if filter.CategoryName != "" {
+		variables.CategoryName = strconv.Quote(filter.CategoryName)
+	}
+
+	if filter.CityName != "" {
+		variables.CityName = strconv.Quote(filter.CityName)
+	}
+
+	queryBuf := bytes.Buffer{}
+	err := priceFilterNodesTemplate.Execute(&queryBuf, variables)
+	if err != nil {
+		logError(err)
+		return nodes, false, wrap(ErrPriceRowsCanNotBeRead, err)
+	}
+
+	transaction := prices.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return nodes, false, wrap(ErrPriceRowsCanNotBeRead, err)
+	}
+
+	err = json.Unmarshal(response.GetJson(), &nodes)
+	if err != nil {
+		logError(err)
+		return nodes, false, wrap(ErrPriceRowsCanNotBeRead, err)
+	}
+
+	found := (filter.CompanyName == "" || len(nodes.Companies) > 0) &&
+		(filter.CategoryName == "" || len(nodes.Categories) > 0) &&
+		(filter.CityName == "" || len(nodes.Cities) > 0)
+
+	return nodes, found, nil
+}
+
+// Prices of company are prices with edge to company and prices without company of products of company.
+// Prices of category are prices of products of category.
+var priceRowsTemplate = template.Must(template.New("ReadPriceRows").Parse(`{
+				{{if .Companies}}
+				var(func: uid({{range $index, $company := .Companies}}{{if $index}}, {{end}}{{$company.ID}}{{end}})) {
+					has_category {
+						has_product @filter({{range $index, $company := .Companies}}{{if $index}} OR {{end}}uid_in(belongs_to_company, {{$company.ID}}){{end}}) {
+							productsPrices as has_price
+						}
+					}
+				}
+				{{end}}
+				{{if .Categories}}
+				var(func: uid({{range $index, $category := .Categories}}{{if $index}}, {{end}}{{$category.ID}}{{end}})) {
+					has_product {
+						categoriesPrices as has_price
+					}
+				}
+				{{end}}
+
+				prices(func: has(priceValue), first: {{.First}}{{if .After}}, after: {{.After}}{{end}})
+				@filter(eq(priceIsActive, true){{if .From}} AND ge(priceDateTime, "{{.From}}"){{end}}{{if .To}} AND le(priceDateTime, "{{.To}}"){{end}}
+				{{if .Companies}} AND ({{range .Companies}}uid_in(belongs_to_company, {{.ID}}) OR {{end}}(NOT has(belongs_to_company) AND uid(productsPrices))){{end}}
+				{{if .Categories}} AND uid(categoriesPrices){{end}}
+				{{if .Cities}} AND ({{range $index, $city := .Cities}}{{if $index}} OR {{end}}uid_in(belongs_to_city, {{$city.ID}}){{end}}){{end}}) {
+					uid
+					priceValue
+					priceAmount
+					priceCurrency
+					priceDateTime
+					belongs_to_city {
+						uid
+						cityName: cityName@{{.Language}}
+					}
+					belongs_to_company {
+						uid
+						companyName: companyName@{{.Language}}
+					}
+					belongs_to_product {
+						uid
+						productName: productName@{{.Language}}
+						productIri
+						belongs_to_category {
+							uid
+							categoryName: categoryName@{{.Language}}
+						}
+						belongs_to_company {
+							uid
+							companyName: companyName@{{.Language}}
+						}
+					}
+				}
+			}`))
+
+// ReadPriceRows is a method for read active prices matched by filter in batches and pass every row to handle.
+// Dates and names of filter are compared by database, rows are not collected in memory,
+// so handle can write them to file or network as they are read.
+func (prices *Prices) ReadPriceRows(ctx context.Context, filter PriceFilter, language string, handle func(PriceRow) error) error {
+	nodes, found, err := prices.readPriceFilterNodes(ctx, filter, language)
+	if err != nil || !found {
+		return err
+	}
+
+	variables := struct {
+		Companies                 []Company
+		Categories                []Category
+		Cities                    []City
+		First                     int
+		After, From, To, Language string
+	}{
+		Companies:  nodes.Companies,
+		Categories: nodes.Categories,
+		Cities:     nodes.Cities,
+		First:      priceRowsBatchSize,
+		Language:   language}
+
+	if !filter.From.IsZero() {
+		variables.From = filter.From.UTC().Format(time.RFC3339)
+	}
+
+	if !filter.To.IsZero() {
+		variables.To = filter.To.UTC().Format(time.RFC3339)
+	}
+
+	type pricesInStorage struct {
+		Prices []Price `json:"prices"`
+	}
+
+	for {
+		queryBuf := bytes.Buffer{}
+		err := priceRowsTemplate.Execute(&queryBuf, variables)
+		if err != nil {
+			logError(err)
+			return wrap(ErrPriceRowsCanNotBeRead, err)
+		}
+
+		transaction := prices.storage.newReadTransaction(ctx)
+		response, err := transaction.Query(ctx, queryBuf.String())
+		transaction.Discard(ctx)
+		if err != nil {
+			logError(err)
+			return wrap(ErrPriceRowsCanNotBeRead, err)
+		}
+
+		var foundedPrices pricesInStorage
+		err = json.Unmarshal(response.GetJson(), &foundedPrices)
+		if err != nil {
+			logError(err)
+			return wrap(ErrPriceRowsCanNotBeRead, err)
+		}
+
+		for _, price := range foundedPrices.Prices {
+			row, ok := priceRowOf(price, filter)
+			if !ok {
+				continue
+			}
+
+			row.Currency = price.MoneyOf(prices.storage.PricesCurrency()).Currency
+
+			err = handle(row)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(foundedPrices.Prices) < priceRowsBatchSize {
+			return nil
+		}
+
+		variables.After = foundedPrices.Prices[len(foundedPrices.Prices)-1].ID
+	}
+}
+
+// priceRowOf make flat row of price if price is matched by names of filter,
+// names of filter are compared again because database compares them by terms
+func priceRowOf(price Price, filter PriceFilter) (PriceRow, bool) {
+	row := PriceRow{Value: price.Value, DateTime: price.DateTime}
+
+	if len(price.Cities) > 0 {
+		row.CityName = price.Cities[0].Name
+	}
+
+	if len(price.Products) > 0 {
+		product := price.Products[0]
+		row.ProductName = product.Name
+		row.ProductIRI = product.IRI
+
+		for _, category := range product.Categories {
+			if filter.CategoryName == "" || category.Name == filter.CategoryName {
+				row.CategoryName = category.Name
+				break
+			}
+		}
+	}
+
+	// Price has its own company, companies of product are used only for prices without company
+	companies := price.Companies
+	if len(companies) == 0 && len(price.Products) > 0 {
+		companies = price.Products[0].Companies
+	}
+
+	for _, company := range companies {
+		if filter.CompanyName == "" || company.Name == filter.CompanyName {
+			row.CompanyName = company.Name
+			break
+		}
+	}
+
+	if filter.CategoryName != "" && row.CategoryName != filter.CategoryName {
+		return row, false
+	}
+
+	if filter.CompanyName != "" && row.CompanyName != filter.CompanyName {
+		return row, false
+	}
+
+	if filter.CityName != "" && row.CityName != filter.CityName {
+		return row, false
+	}
+
+	return row, true
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 
-	"log"
 	"time"
 
 	"bytes"
@@ -17,13 +16,16 @@ import (
 
 // Price is a structure of prices in database
 type Price struct {
-	ID        string    `json:"uid"`
-	Value     float64   `json:"priceValue,omitempty"`
-	DateTime  time.Time `json:"priceDateTime,omitempty"`
-	IsActive  bool      `json:"priceIsActive"`
-	Cities    []City    `json:"belongs_to_city,omitempty"`
-	Products  []Product `json:"belongs_to_product,omitempty"`
-	Companies []Company `json:"belongs_to_company,omitempty"`
+	ID         string    `json:"uid"`
+	Value      float64   `json:"priceValue,omitempty"`
+	Amount     int64     `json:"priceAmount,omitempty"`
+	Currency   string    `json:"priceCurrency,omitempty"`
+	DateTime   time.Time `json:"priceDateTime,omitempty"`
+	LastSeenAt time.Time `json:"priceLastSeenAt,omitempty"`
+	IsActive   bool      `json:"priceIsActive"`
+	Cities     []City    `json:"belongs_to_city,omitempty"`
+	Products   []Product `json:"belongs_to_product,omitempty"`
+	Companies  []Company `json:"belongs_to_company,omitempty"`
 }
 
 // func (price *Price) MarshalJSON() ([]byte, error) {
@@ -59,56 +61,40 @@ type Prices struct {
 	storage *Storage
 }
 
-// SetUp is a method of Prices resource for prepare database client and schema.
-func (prices *Prices) SetUp() (err error) {
-	schema := `
-		pricesValue: float @index(float) .
-		priceDateTime: dateTime @index(day) .
-		priceIsActive: bool @index(bool) .
-		belongs_to_city: uid @count .
-		belongs_to_product: uid @count .
-		belongs_to_company: uid @count .
-	`
-	operation := &dataBaseAPI.Operation{Schema: schema}
-
-	err = prices.storage.Client.Alter(context.Background(), operation)
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-
-	return nil
-}
-
 // ErrPriceCanNotBeCreated means that the price can't be added to database
 var ErrPriceCanNotBeCreated = errors.New("price can't be created")
 
 // CreatePrice is a method for make product and save it to storage
-func (prices *Prices) CreatePrice(price Price) (Price, error) {
-	transaction := prices.storage.Client.NewTxn()
+func (prices *Prices) CreatePrice(ctx context.Context, price Price) (Price, error) {
+	transaction := prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	price.IsActive = true
+	if price.LastSeenAt.IsZero() {
+		price.LastSeenAt = price.DateTime
+	}
+
 	encodedPrice, err := json.Marshal(price)
 	if err != nil {
-		log.Println(err)
-		return price, ErrPriceCanNotBeCreated
+		logError(err)
+		return price, wrap(ErrPriceCanNotBeCreated, err)
 	}
 
 	mutation := &dataBaseAPI.Mutation{
 		SetJson:   encodedPrice,
 		CommitNow: true}
 
-	assigned, err := transaction.Mutate(context.Background(), mutation)
+	assigned, err := transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
-		return price, ErrPriceCanNotBeCreated
+		logError(err)
+		return price, wrap(ErrPriceCanNotBeCreated, err)
 	}
 
 	price.ID = assigned.Uids["blank-0"]
 
-	priceFromStorage, err := prices.ReadPriceByID(price.ID, ".")
+	priceFromStorage, err := prices.ReadPriceByID(ctx, price.ID, ".")
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return priceFromStorage, err
 	}
 
@@ -119,25 +105,28 @@ func (prices *Prices) CreatePrice(price Price) (Price, error) {
 var ErrPriceCanNotBeDeleted = errors.New("price can't be deleted")
 
 // DeletePrice method for remove price from database
-func (prices *Prices) DeletePrice(price Price) (string, error) {
+func (prices *Prices) DeletePrice(ctx context.Context, price Price) (string, error) {
+	defer prices.storage.invalidate(price.ID)
+
 	var err error
 
 	deletePriceData, err := json.Marshal(map[string]string{"uid": price.ID})
 	if err != nil {
-		log.Println(err)
-		return price.ID, ErrPriceCanNotBeDeleted
+		logError(err)
+		return price.ID, wrap(ErrPriceCanNotBeDeleted, err)
 	}
 
 	mutation := dataBaseAPI.Mutation{
 		DeleteJson: deletePriceData,
 		CommitNow:  true}
 
-	transaction := prices.storage.Client.NewTxn()
+	transaction := prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
-		return price.ID, ErrPriceCanNotBeDeleted
+		logError(err)
+		return price.ID, wrap(ErrPriceCanNotBeDeleted, err)
 	}
 
 	return price.ID, nil
@@ -150,7 +139,7 @@ var ErrPriceByIDCanNotBeFound = errors.New("price by id can not be found")
 var ErrPriceDoesNotExist = errors.New("price by id not found")
 
 // ReadPriceByID is a method for get all nodes of prices by ID
-func (prices *Prices) ReadPriceByID(priceID, language string) (Price, error) {
+func (prices *Prices) ReadPriceByID(ctx context.Context, priceID, language string) (Price, error) {
 	variables := struct {
 		PriceID  string
 		Language string
@@ -162,8 +151,10 @@ func (prices *Prices) ReadPriceByID(priceID, language string) (Price, error) {
 				prices(func: uid("{{.PriceID}}")) @filter(has(priceValue)) {
 					uid
 					priceValue
+					priceAmount
+					priceCurrency
 					priceDateTime
-					priceCity
+					priceLastSeenAt
 					priceIsActive
 					belongs_to_product @filter(eq(productIsActive, true)) {
 						uid
@@ -193,22 +184,23 @@ func (prices *Prices) ReadPriceByID(priceID, language string) (Price, error) {
 
 	price := Price{ID: priceID}
 	if err != nil {
-		log.Println(err)
-		return price, ErrPriceByIDCanNotBeFound
+		logError(err)
+		return price, wrap(ErrPriceByIDCanNotBeFound, err)
 	}
 
 	queryBuf := bytes.Buffer{}
 	err = queryTemplate.Execute(&queryBuf, variables)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return price, err
 	}
 
-	transaction := prices.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := prices.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return price, ErrPriceByIDCanNotBeFound
+		logError(err)
+		return price, wrap(ErrPriceByIDCanNotBeFound, err)
 	}
 
 	type PricesInStore struct {
@@ -219,12 +211,12 @@ func (prices *Prices) ReadPriceByID(priceID, language string) (Price, error) {
 
 	err = json.Unmarshal(response.GetJson(), &foundedPrices)
 	if err != nil {
-		log.Println(err)
-		return price, ErrPriceByIDCanNotBeFound
+		logError(err)
+		return price, wrap(ErrPriceByIDCanNotBeFound, err)
 	}
 
 	if len(foundedPrices.Prices) == 0 {
-		return price, ErrPriceDoesNotExist
+		return price, fail(ErrPriceDoesNotExist)
 	}
 
 	return foundedPrices.Prices[0], nil
@@ -234,7 +226,9 @@ func (prices *Prices) ReadPriceByID(priceID, language string) (Price, error) {
 var ErrProductCanNotBeAddedToPrice = errors.New("product can not be added to price")
 
 // AddProductToPrice method for set quad of predicate about price and product
-func (prices *Prices) AddProductToPrice(priceID, productID string) error {
+func (prices *Prices) AddProductToPrice(ctx context.Context, priceID, productID string) error {
+	defer prices.storage.invalidate(priceID, productID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -243,10 +237,11 @@ func (prices *Prices) AddProductToPrice(priceID, productID string) error {
 		SetNquads: []byte(forPricePredicate),
 		CommitNow: true}
 
-	transaction := prices.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrProductCanNotBeAddedToPrice
+		return wrap(ErrProductCanNotBeAddedToPrice, err)
 	}
 
 	forProductPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, productID, "has_price", priceID)
@@ -254,10 +249,11 @@ func (prices *Prices) AddProductToPrice(priceID, productID string) error {
 		SetNquads: []byte(forProductPredicate),
 		CommitNow: true}
 
-	transaction = prices.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrProductCanNotBeAddedToPrice
+		return wrap(ErrProductCanNotBeAddedToPrice, err)
 	}
 
 	return nil
@@ -267,7 +263,9 @@ func (prices *Prices) AddProductToPrice(priceID, productID string) error {
 var ErrCompanyCanNotBeAddedToPrice = errors.New("company can not be added to price")
 
 // AddCompanyToPrice method for set quad of predicate about price and company
-func (prices *Prices) AddCompanyToPrice(priceID, companyID string) error {
+func (prices *Prices) AddCompanyToPrice(ctx context.Context, priceID, companyID string) error {
+	defer prices.storage.invalidate(priceID, companyID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -276,10 +274,11 @@ func (prices *Prices) AddCompanyToPrice(priceID, companyID string) error {
 		SetNquads: []byte(forPricePredicate),
 		CommitNow: true}
 
-	transaction := prices.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCompanyCanNotBeAddedToPrice
+		return wrap(ErrCompanyCanNotBeAddedToPrice, err)
 	}
 
 	return nil
@@ -289,111 +288,151 @@ func (prices *Prices) AddCompanyToPrice(priceID, companyID string) error {
 var ErrCityCanNotBeAddedToPrice = errors.New("city can not be added to price")
 
 // AddCityToPrice method for set quad of predicate about price and city
-func (prices *Prices) AddCityToPrice(priceID, cityID string) error {
+func (prices *Prices) AddCityToPrice(ctx context.Context, priceID, cityID string) error {
+	defer prices.storage.invalidate(priceID, cityID)
+
 	forPricePredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, priceID, "belongs_to_city", cityID)
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(forPricePredicate),
 		CommitNow: true}
 
-	transaction := prices.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCityCanNotBeAddedToPrice
+		return wrap(ErrCityCanNotBeAddedToPrice, err)
 	}
 	return nil
 }
 
-// ImportJSON is a method for add prices to database
-func (prices *Prices) ImportJSON(exportedPrices []byte) error {
+type allExportedPrices struct {
+	Language string  `json:"language"`
+	Rates    []Rate  `json:"rates,omitempty"`
+	Prices   []Price `json:"prices"`
+}
 
-	type allPrices struct {
-		Prices []Price `json:"prices"`
-	}
+// ImportJSON is a method for add rates and prices to database and update offers by prices.
+// Rates, products, cities and companies of prices are found by natural keys, exported uids are not written to database.
+func (prices *Prices) ImportJSON(ctx context.Context, exportedPrices []byte) (ImportReport, error) {
+	defer prices.storage.Cache.Purge()
 
-	var allPricesInJSON allPrices
+	var allPricesInJSON allExportedPrices
 
 	err := json.Unmarshal(exportedPrices, &allPricesInJSON)
 	if err != nil {
-		return err
+		return ImportReport{}, err
 	}
 
-	for _, exportedPrice := range allPricesInJSON.Prices {
-		encodedPrice, err := json.Marshal(exportedPrice)
+	mapping := newImportMapping(prices.storage, allPricesInJSON.Language)
+
+	for _, exportedRate := range allPricesInJSON.Rates {
+		_, err = mapping.rate(ctx, exportedRate)
 		if err != nil {
-			log.Println(err)
-			return err
+			return mapping.report, err
 		}
+	}
 
-		mutation := &dataBaseAPI.Mutation{
-			SetJson:   encodedPrice,
-			CommitNow: true}
-
-		transaction := prices.storage.Client.NewTxn()
+	for _, exportedPrice := range allPricesInJSON.Prices {
+		if len(exportedPrice.Products) == 0 {
+			return mapping.report, fail(ErrImportedNodeWithoutKey)
+		}
 
-		_, err = transaction.Mutate(context.Background(), mutation)
+		productID, err := mapping.product(ctx, exportedPrice.Products[0])
 		if err != nil {
-			log.Println(err)
-			return err
+			return mapping.report, err
 		}
 
-		if len(exportedPrice.Products) > 0 {
-			err = prices.AddProductToPrice(exportedPrice.ID, exportedPrice.Products[0].ID)
+		cityID := ""
+		if len(exportedPrice.Cities) > 0 {
+			cityID, err = mapping.city(ctx, exportedPrice.Cities[0])
 			if err != nil {
-				return err
+				return mapping.report, err
 			}
 		}
 
-		if len(exportedPrice.Cities) > 0 {
-			err = prices.AddCityToPrice(exportedPrice.ID, exportedPrice.Cities[0].ID)
+		companyID := ""
+		if len(exportedPrice.Companies) > 0 {
+			companyID, err = mapping.company(ctx, exportedPrice.Companies[0])
 			if err != nil {
-				return err
+				return mapping.report, err
 			}
 		}
+
+		priceID, err := mapping.price(ctx, exportedPrice, productID, companyID, cityID)
+		if err != nil {
+			return mapping.report, err
+		}
+
+		err = mapping.link(ctx, priceID,
+			"belongs_to_product", productID, "belongs_to_city", cityID, "belongs_to_company", companyID)
+		if err != nil {
+			return mapping.report, err
+		}
+
+		err = mapping.link(ctx, productID, "has_price", priceID)
+		if err != nil {
+			return mapping.report, err
+		}
+
+		err = mapping.offer(ctx, exportedPrice, productID, companyID, cityID)
+		if err != nil {
+			return mapping.report, err
+		}
 	}
 
-	return nil
+	return mapping.report, nil
 }
 
-// ExportJSON method for export all prices belongs to product from database to json
-func (prices *Prices) ExportJSON() ([]byte, error) {
+// ExportJSON method for export rates and all prices belongs to product from database to json
+// with natural keys of products, cities and companies
+func (prices *Prices) ExportJSON(ctx context.Context, language string) ([]byte, error) {
 	query := fmt.Sprintf(`{
 				prices(func: has(belongs_to_product)) {
 					uid
 					priceValue
+					priceAmount
+					priceCurrency
 					priceDateTime
-					priceCity
+					priceLastSeenAt
 					priceIsActive
 					belongs_to_product {
 						uid
+						productName: productName@%v
+						productIri
 						productIsActive
 					}
 					belongs_to_city {
 						uid
+						cityName: cityName@%v
 						cityIsActive
 					}
 					belongs_to_company {
 						uid
+						companyName: companyName@%v
+						companyIri
 						companyIsActive
 					}
 				}
-			}`)
+			}`, language, language, language)
 
-	transaction := prices.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), query)
+	transaction := prices.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
-	type PricesInStore struct {
-		Prices []Price `json:"prices"`
-	}
-
-	var foundedPrices PricesInStore
+	foundedPrices := allExportedPrices{Language: language}
 
 	err = json.Unmarshal(response.GetJson(), &foundedPrices)
 	if err != nil {
-		log.Println(err)
+		logError(err)
+		return nil, err
+	}
+
+	foundedPrices.Rates, err = prices.storage.Rates.ReadRates(ctx)
+	if err != nil {
 		return nil, err
 	}
 
@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 )
@@ -16,13 +18,13 @@ func TestIntegrationPriceCanBeCreated(test *testing.T) {
 	}
 
 	priceForCreate := Price{Value: 21.440, DateTime: dateTime}
-	createdPrice, err := storage.Prices.CreatePrice(priceForCreate)
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), priceForCreate)
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
@@ -43,19 +45,19 @@ func TestIntegrationPriceCanBeReadById(test *testing.T) {
 	}
 
 	priceForCreate := Price{Value: 21.440, DateTime: dateTime}
-	createdPrice, err := storage.Prices.CreatePrice(priceForCreate)
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), priceForCreate)
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	priceFromStore, err := storage.Prices.ReadPriceByID(createdPrice.ID, ".")
+	priceFromStore, err := storage.Prices.ReadPriceByID(context.Background(), createdPrice.ID, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -84,12 +86,12 @@ func TestIntegrationPriceCanBeDeleted(test *testing.T) {
 
 	priceForCreate := Price{Value: 22.440, DateTime: dateTime}
 
-	createdPrice, err := storage.Prices.CreatePrice(priceForCreate)
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), priceForCreate)
 	if err != nil {
 		test.Error(err)
 	}
 
-	deletedPriceID, err := storage.Prices.DeletePrice(createdPrice)
+	deletedPriceID, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 	if err != nil {
 		test.Error(err)
 	}
@@ -98,8 +100,8 @@ func TestIntegrationPriceCanBeDeleted(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = storage.Prices.ReadPriceByID(deletedPriceID, ".")
-	if err != ErrPriceDoesNotExist {
+	_, err = storage.Prices.ReadPriceByID(context.Background(), deletedPriceID, ".")
+	if !errors.Is(err, ErrPriceDoesNotExist) {
 		test.Error(err)
 	}
 }
@@ -113,36 +115,36 @@ func TestIntegrationProductCanBeAddedToPrice(test *testing.T) {
 		test.Error(err)
 	}
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: 123, DateTime: dateTime})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 123, DateTime: dateTime})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Prices.AddProductToPrice(createdPrice.ID, createdProduct.ID)
+	err = storage.Prices.AddProductToPrice(context.Background(), createdPrice.ID, createdProduct.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedPrice, _ := storage.Prices.ReadPriceByID(createdPrice.ID, "en")
+	updatedPrice, _ := storage.Prices.ReadPriceByID(context.Background(), createdPrice.ID, "en")
 
 	if updatedPrice.Products[0].ID != createdProduct.ID {
 		test.Fail()
@@ -158,36 +160,36 @@ func TestIntegrationCompanyCanBeAddedToPrice(test *testing.T) {
 		test.Error(err)
 	}
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: 123, DateTime: dateTime})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 123, DateTime: dateTime})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Prices.AddCompanyToPrice(createdPrice.ID, createdCompany.ID)
+	err = storage.Prices.AddCompanyToPrice(context.Background(), createdPrice.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedPrice, err := storage.Prices.ReadPriceByID(createdPrice.ID, "en")
+	updatedPrice, err := storage.Prices.ReadPriceByID(context.Background(), createdPrice.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -211,12 +213,12 @@ func TestIntegrationPriceCanBeAddedFromExportedJSON(test *testing.T) {
 	priceData := time.Now().UTC()
 	priceValue := 123.0
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: priceValue, DateTime: priceData, IsActive: true})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: priceValue, DateTime: priceData, IsActive: true})
 	if err != nil {
 		test.Error(err)
 	}
 
-	_, err = storage.Prices.DeletePrice(createdPrice)
+	_, err = storage.Prices.DeletePrice(context.Background(), createdPrice)
 	if err != nil {
 		test.Error(err)
 	}
@@ -228,25 +230,21 @@ func TestIntegrationPriceCanBeAddedFromExportedJSON(test *testing.T) {
 		test.Error(err)
 	}
 
-	_, err = storage.Prices.ReadPriceByID(createdPrice.ID, "en")
-	if err != ErrPriceDoesNotExist {
+	_, err = storage.Prices.ReadPriceByID(context.Background(), createdPrice.ID, "en")
+	if !errors.Is(err, ErrPriceDoesNotExist) {
 		test.Error(err)
 	}
 
-	err = storage.Prices.ImportJSON(exportedJSON)
+	report, err := storage.Prices.ImportJSON(context.Background(), exportedJSON)
 	if err != nil {
 		test.Error(err)
 	}
 
-	priceFromStorage, err := storage.Prices.ReadPriceByID(createdPrice.ID, "en")
+	priceFromStorage, err := storage.Prices.ReadPriceByID(context.Background(), report.ID("price", createdPrice.ID), "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	if priceFromStorage.ID != createdPrice.ID {
-		test.Fail()
-	}
-
 	if priceFromStorage.Value != priceValue {
 		test.Fail()
 	}
@@ -273,67 +271,72 @@ func TestIntegrationPriceCanBeExportedToJSON(test *testing.T) {
 
 	priceValue := 123.0
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: priceValue, DateTime: priceData})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: priceValue, DateTime: priceData})
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = storage.Prices.AddProductToPrice(createdPrice.ID, createdProduct.ID)
+	err = storage.Prices.AddProductToPrice(context.Background(), createdPrice.ID, createdProduct.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdCity, err := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	err = storage.Prices.AddCityToPrice(createdPrice.ID, createdCity.ID)
+	err = storage.Prices.AddCityToPrice(context.Background(), createdPrice.ID, createdCity.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	exportedJSON, err := storage.Prices.ExportJSON()
+	exportedJSON, err := storage.Prices.ExportJSON(context.Background(), "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	_, err = storage.Products.DeleteProduct(createdProduct)
+	_, err = storage.Products.DeleteProduct(context.Background(), createdProduct)
 	if err != nil {
 		test.Error(err)
 	}
 
-	_, err = storage.Prices.DeletePrice(createdPrice)
+	_, err = storage.Prices.DeletePrice(context.Background(), createdPrice)
 	if err != nil {
 		test.Error(err)
 	}
 
-	_, err = storage.Cities.DeleteCity(createdCity)
+	_, err = storage.Cities.DeleteCity(context.Background(), createdCity)
 	if err != nil {
 		test.Error(err)
 	}
 
-	_, err = storage.Prices.ReadPriceByID(createdPrice.ID, "en")
-	if err != ErrPriceDoesNotExist {
+	_, err = storage.Prices.ReadPriceByID(context.Background(), createdPrice.ID, "en")
+	if !errors.Is(err, ErrPriceDoesNotExist) {
 		test.Error(err)
 	}
 
-	err = storage.Prices.ImportJSON(exportedJSON)
+	report, err := storage.Prices.ImportJSON(context.Background(), exportedJSON)
 	if err != nil {
 		test.Error(err)
 	}
 
-	priceFromStorage, err := storage.Prices.ReadPriceByID(createdPrice.ID, "en")
+	importedPriceID := report.ID("price", createdPrice.ID)
+	if importedPriceID == "" || importedPriceID == createdPrice.ID {
+		test.Fatal()
+	}
+
+	priceFromStorage, err := storage.Prices.ReadPriceByID(context.Background(), importedPriceID, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	if priceFromStorage.ID != createdPrice.ID {
+	if priceFromStorage.Value != priceValue {
 		test.Fail()
 	}
 
@@ -341,28 +344,37 @@ func TestIntegrationPriceCanBeExportedToJSON(test *testing.T) {
 		test.Fatal()
 	}
 
-	if priceFromStorage.Products[0].ID != createdProduct.ID {
+	if priceFromStorage.Products[0].ID != report.ID("product", createdProduct.ID) {
 		test.Fail()
 	}
 
-	if priceFromStorage.Cities[0].ID != createdCity.ID {
+	if len(priceFromStorage.Cities) != 1 || priceFromStorage.Cities[0].ID != report.ID("city", createdCity.ID) {
+		test.Fatal()
+	}
+
+	repeatedReport, err := storage.Prices.ImportJSON(context.Background(), exportedJSON)
+	if err != nil {
+		test.Error(err)
+	}
+
+	if repeatedReport.ID("price", createdPrice.ID) != importedPriceID {
 		test.Fail()
 	}
 
 	createdPrice.ID = priceFromStorage.ID
-	_, err = storage.Prices.DeletePrice(createdPrice)
+	_, err = storage.Prices.DeletePrice(context.Background(), createdPrice)
 	if err != nil {
 		test.Error(err)
 	}
 
 	createdProduct.ID = priceFromStorage.Products[0].ID
-	_, err = storage.Products.DeleteProduct(createdProduct)
+	_, err = storage.Products.DeleteProduct(context.Background(), createdProduct)
 	if err != nil {
 		test.Error(err)
 	}
 
 	createdCity.ID = priceFromStorage.Cities[0].ID
-	_, err = storage.Cities.DeleteCity(createdCity)
+	_, err = storage.Cities.DeleteCity(context.Background(), createdCity)
 	if err != nil {
 		test.Error(err)
 	}
@@ -377,32 +389,32 @@ func TestIntegrationCityCanBeAddedToPrice(test *testing.T) {
 		test.Error(err)
 	}
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: 123, DateTime: dateTime})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 123, DateTime: dateTime})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdCity, _ := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	createdCity, _ := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Prices.AddCityToPrice(createdPrice.ID, createdCity.ID)
+	err = storage.Prices.AddCityToPrice(context.Background(), createdPrice.ID, createdCity.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedPrice, err := storage.Prices.ReadPriceByID(createdPrice.ID, "en")
+	updatedPrice, err := storage.Prices.ReadPriceByID(context.Background(), createdPrice.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -426,17 +438,17 @@ func TestPriceDateTimeCanBeMarshalingRight(test *testing.T) {
 		Value:    100,
 		DateTime: timeOfParse}
 
-	priceInStorage, err := storage.Prices.CreatePrice(price)
+	priceInStorage, err := storage.Prices.CreatePrice(context.Background(), price)
 	if err != nil {
 		test.Error(err)
 	}
 
-	priceFromStorage, err := storage.Prices.ReadPriceByID(priceInStorage.ID, ".")
+	priceFromStorage, err := storage.Prices.ReadPriceByID(context.Background(), priceInStorage.ID, ".")
 	if err != nil {
 		test.Error(err)
 	}
 
-	_, err = storage.Prices.DeletePrice(priceInStorage)
+	_, err = storage.Prices.DeletePrice(context.Background(), priceInStorage)
 	if err != nil {
 		test.Error(err)
 	}
@@ -450,3 +462,122 @@ func TestPriceDateTimeCanBeMarshalingRight(test *testing.T) {
 		test.Fail()
 	}
 }
+
+func TestPriceRowIsMatchedByNamesOfFilter(test *testing.T) {
+	price := Price{
+		Value:  100,
+		Cities: []City{{Name: "Москва"}},
+		Products: []Product{{
+			Name:       "Phone",
+			IRI:        "/phone",
+			Categories: []Category{{Name: "Телефоны"}, {Name: "Смартфоны"}},
+			Companies:  []Company{{Name: "М.Видео"}}}}}
+
+	row, ok := priceRowOf(price, PriceFilter{CategoryName: "Смартфоны", CompanyName: "М.Видео", CityName: "Москва"})
+	if !ok {
+		test.Fatal()
+	}
+
+	if row.CategoryName != "Смартфоны" || row.CompanyName != "М.Видео" || row.ProductIRI != "/phone" {
+		test.Fail()
+	}
+
+	_, ok = priceRowOf(price, PriceFilter{CityName: "Санкт-Петербург"})
+	if ok {
+		test.Fail()
+	}
+
+	_, ok = priceRowOf(price, PriceFilter{CategoryName: "Ноутбуки"})
+	if ok {
+		test.Fail()
+	}
+
+	price.Companies = []Company{{Name: "Эльдорадо"}}
+
+	_, ok = priceRowOf(price, PriceFilter{CompanyName: "М.Видео"})
+	if ok {
+		test.Error("Price of other company must not be matched by company of product")
+	}
+
+	row, ok = priceRowOf(price, PriceFilter{})
+	if !ok || row.CompanyName != "Эльдорадо" {
+		test.Error(row)
+	}
+}
+
+func TestIntegrationPriceRowsCanNotBeReadByUnknownName(test *testing.T) {
+	once.Do(prepareStorage)
+
+	read := 0
+	err := storage.Prices.ReadPriceRows(context.Background(), PriceFilter{CompanyName: "Unknown price row company"}, "en",
+		func(row PriceRow) error {
+			read++
+			return nil
+		})
+	if err != nil || read != 0 {
+		test.Error(read, err)
+	}
+}
+
+func TestIntegrationPriceRowsCanBeReadByFilter(test *testing.T) {
+	once.Do(prepareStorage)
+
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Price row product", IRI: "/price/row"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Products.DeleteProduct(context.Background(), createdProduct)
+
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Price row city"}, "en")
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer storage.Cities.DeleteCity(context.Background(), createdCity)
+
+	dates := []time.Time{
+		time.Date(2016, 1, 10, 12, 0, 0, 0, time.UTC),
+		time.Date(2016, 2, 10, 12, 0, 0, 0, time.UTC)}
+
+	for _, date := range dates {
+		createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 10, DateTime: date})
+		if err != nil {
+			test.Error(err)
+		}
+
+		defer storage.Prices.DeletePrice(context.Background(), createdPrice)
+
+		err = storage.Prices.AddProductToPrice(context.Background(), createdPrice.ID, createdProduct.ID)
+		if err != nil {
+			test.Error(err)
+		}
+
+		err = storage.Prices.AddCityToPrice(context.Background(), createdPrice.ID, createdCity.ID)
+		if err != nil {
+			test.Error(err)
+		}
+	}
+
+	filter := PriceFilter{
+		From:     time.Date(2016, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2016, 2, 28, 0, 0, 0, 0, time.UTC),
+		CityName: "Price row city"}
+
+	var rows []PriceRow
+	err = storage.Prices.ReadPriceRows(context.Background(), filter, "en", func(row PriceRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(rows) != 1 {
+		test.Fatalf("Expected 1 row, actual %v", len(rows))
+	}
+
+	if rows[0].ProductName != "Price row product" || rows[0].ProductIRI != "/price/row" || !rows[0].DateTime.Equal(dates[1]) {
+		test.Fail()
+	}
+}
@@ -2,7 +2,6 @@ package storage
 
 import (
 	"context"
-	"log"
 
 	"encoding/json"
 	"errors"
@@ -62,6 +61,7 @@ type Product struct {
 	Categories       []Category `json:"belongs_to_category,omitempty"`
 	Companies        []Company  `json:"belongs_to_company,omitempty"`
 	Prices           []Price    `json:"has_price,omitempty"`
+	Offers           []Offer    `json:"has_offer,omitempty"`
 }
 
 // Products is resource of storage for CRUD operations
@@ -74,28 +74,7 @@ func NewProductsResourceForStorage(storage *Storage) *Products {
 	return &Products{storage: storage}
 }
 
-// SetUp is a method of Products resource for prepare database client and schema.
-func (products *Products) SetUp() (err error) {
-	schema := `
-		productName: string @lang @index(term, trigram) .
-		productIri: string @index(term) .
-		productImageLink: string @index(term) .
-		productIsActive: bool @index(bool) .
-		belongs_to_category: uid .
-		belongs_to_company: uid .
-	`
-	operation := &dataBaseAPI.Operation{Schema: schema}
-
-	err = products.storage.Client.Alter(context.Background(), operation)
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-
-	return nil
-}
-
-func (products *Products) ReadTotalCountOfProductsByName(productName, language string) (int, error) {
+func (products *Products) ReadTotalCountOfProductsByName(ctx context.Context, productName, language string) (int, error) {
 	type Variables struct {
 		ProductName, Language     string
 		CurrentPage, ItemsPerPage int
@@ -113,7 +92,7 @@ func (products *Products) ReadTotalCountOfProductsByName(productName, language s
 			}`)
 
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return 0, err
 	}
 
@@ -121,15 +100,16 @@ func (products *Products) ReadTotalCountOfProductsByName(productName, language s
 
 	err = totalQueryTemplate.Execute(&totalQueryBuf, variables)
 	if err != nil {
-		log.Println(err)
-		return 0, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return 0, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
-	transaction := products.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), totalQueryBuf.String())
+	transaction := products.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, totalQueryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return 0, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return 0, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
 	type productsCountInStorage struct {
@@ -139,8 +119,8 @@ func (products *Products) ReadTotalCountOfProductsByName(productName, language s
 	var foundedProducts productsCountInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedProducts)
 	if err != nil {
-		log.Println(err)
-		return 0, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return 0, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
 	return foundedProducts.Total[0]["count"], nil
@@ -153,13 +133,37 @@ type ProductsByNameForPage struct {
 	TotalProductsFound      int
 	SearchedName            string
 	Language                string
+	Projection
 }
 
-func (products *Products) ReadProductsByNameWithPagination(productName, language string, currentPage, itemsPerPage int) (*ProductsByNameForPage, error) {
+// ReadProductsByNameWithPagination is a method for get page of active products by name with detail, prices
+// and currency of projection, page without products is returned when nothing is found
+func (products *Products) ReadProductsByNameWithPagination(ctx context.Context, productName, language string, currentPage, itemsPerPage int, projection Projection) (*ProductsByNameForPage, error) {
+	err := projection.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// currency is not a part of key, because prices are converted after cache by the current rates
+	key := cacheKey("products-by-name", language, currentPage, itemsPerPage, projection.Detail, projection.PricesLimit, productName)
+	cached, generation := products.storage.lookup(ctx, key)
+	if cached != nil {
+		page := cached.(ProductsByNameForPage)
+		page.Projection = projection
+
+		page.Products, err = products.inCurrency(ctx, page.Products, projection)
+		if err != nil {
+			return nil, err
+		}
+
+		return &page, nil
+	}
 
 	type Variables struct {
 		ProductName, Language             string
 		CurrentPage, ItemsPerPage, Offset int
+		Detail                            Detail
+		PricesLimit                       int
 	}
 
 	productsByPageTemplate, err := template.New("productsByPage").Parse(`{
@@ -175,6 +179,24 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 					productIri
 					previewImageLink
 					productIsActive
+					{{if eq .Detail "summary"}}
+					has_offer (orderdesc: offerDateTime{{if .PricesLimit}}, first: {{.PricesLimit}}{{end}}) {
+						offerValue
+						offerAmount
+						offerCurrency
+						offerDateTime
+						belongs_to_city @filter(eq(cityIsActive, true)) {
+							uid
+							cityName: cityName@{{.Language}}
+							cityIsActive
+						}
+						belongs_to_company @filter(eq(companyIsActive, true)) {
+							uid
+							companyName: companyName@{{.Language}}
+							companyIsActive
+						}
+					}
+					{{else}}
 					belongs_to_category @filter(eq(categoryIsActive, true)) {
 						uid
 						categoryName: categoryName@{{.Language}}
@@ -186,7 +208,7 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 								uid
 								categoryName: categoryName@{{.Language}}
 								categoryIsActive
-								belong_to_company @filter(eq(companyIsActive, true)){
+								belongs_to_company @filter(eq(companyIsActive, true)){
 									uid
 									companyName: companyName@{{.Language}}
 									companyIsActive
@@ -201,18 +223,19 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 							uid
 							categoryName: categoryName@{{.Language}}
 							categoryIsActive
-							belong_to_company @filter(eq(companyIsActive, true)){
+							belongs_to_company @filter(eq(companyIsActive, true)){
 								uid
 								companyName: companyName@{{.Language}}
 								companyIsActive
 							}
 						}
 					}
-					has_price @filter(eq(priceIsActive, true)) (orderdesc: priceDateTime) {
+					has_price @filter(eq(priceIsActive, true)) (orderdesc: priceDateTime{{if .PricesLimit}}, first: {{.PricesLimit}}{{end}}) {
 						uid
 						priceValue
+						priceAmount
+						priceCurrency
 						priceDateTime
-						priceCity
 						priceIsActive
 						belongs_to_product @filter(eq(productIsActive, true)) {
 							uid
@@ -220,11 +243,10 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 							productIri
 							previewImageLink
 							productIsActive
-							has_price @filter(eq(priceIsActive, true)) {
+							has_price @filter(eq(priceIsActive, true)) {{if .PricesLimit}}(orderdesc: priceDateTime, first: {{.PricesLimit}}){{end}} {
 								uid
 								priceValue
 								priceDateTime
-								priceCity
 								priceIsActive
 							}
 						}
@@ -240,12 +262,13 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 							companyIsActive
 						}
 					}
+					{{end}}
 				}
 			}`)
 
 	if err != nil {
-		log.Println(err)
-		return nil, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
 	variables := Variables{
@@ -253,21 +276,24 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 		ItemsPerPage: itemsPerPage,
 		CurrentPage:  currentPage,
 		Offset:       currentPage*itemsPerPage - itemsPerPage,
-		Language:     language}
+		Language:     language,
+		Detail:       projection.Detail,
+		PricesLimit:  projection.PricesLimit}
 
 	productsByPageBuf := bytes.Buffer{}
 
 	err = productsByPageTemplate.Execute(&productsByPageBuf, variables)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
-	transaction := products.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), productsByPageBuf.String())
+	transaction := products.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, productsByPageBuf.String())
 	if err != nil {
-		log.Println(err)
-		return nil, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
 	type productsInStorage struct {
@@ -278,8 +304,12 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 	var foundedProducts productsInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedProducts)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrProductsByNameCanNotBeFound, err)
+	}
+
+	if projection.isSummary() {
+		foundedProducts.AllProductsFoundedByName = summaryOf(foundedProducts.AllProductsFoundedByName, projection)
 	}
 
 	foundedProductsByNameForPage := ProductsByNameForPage{
@@ -288,23 +318,35 @@ func (products *Products) ReadProductsByNameWithPagination(productName, language
 		TotalProductsForOnePage: itemsPerPage,
 		SearchedName:            productName,
 		TotalProductsFound:      foundedProducts.Total[0]["total"],
-		Language:                language}
+		Language:                language,
+		Projection:              projection}
 
-	if len(foundedProducts.AllProductsFoundedByName) == 0 {
-		return &foundedProductsByNameForPage, ErrProductsByNameNotFound
+	products.storage.remember(key, generation, foundedProductsByNameForPage, productsTag)
+
+	foundedProductsByNameForPage.Products, err = products.inCurrency(ctx, foundedProductsByNameForPage.Products, projection)
+	if err != nil {
+		return nil, err
 	}
 
 	return &foundedProductsByNameForPage, nil
 }
 
-// ReadProductsByName is a method for get all nodes by product name
-func (products *Products) ReadProductsByName(productName, language string) ([]Product, error) {
+// ReadProductsByName is a method for get all nodes by product name with detail and prices of projection
+func (products *Products) ReadProductsByName(ctx context.Context, productName, language string, projection Projection) ([]Product, error) {
+	err := projection.validate()
+	if err != nil {
+		return nil, err
+	}
 
 	variables := struct {
 		ProductName, Language string
+		Detail                Detail
+		PricesLimit           int
 	}{
 		ProductName: productName,
-		Language:    language}
+		Language:    language,
+		Detail:      projection.Detail,
+		PricesLimit: projection.PricesLimit}
 
 	productsByNameTemplate, err := template.New("productsByName").Parse(`{
 				products(func: regexp(productName@{{.Language}}, /{{.ProductName}}/)) 
@@ -314,6 +356,24 @@ func (products *Products) ReadProductsByName(productName, language string) ([]Pr
 					productIri
 					previewImageLink
 					productIsActive
+					{{if eq .Detail "summary"}}
+					has_offer (orderdesc: offerDateTime{{if .PricesLimit}}, first: {{.PricesLimit}}{{end}}) {
+						offerValue
+						offerAmount
+						offerCurrency
+						offerDateTime
+						belongs_to_city @filter(eq(cityIsActive, true)) {
+							uid
+							cityName: cityName@{{.Language}}
+							cityIsActive
+						}
+						belongs_to_company @filter(eq(companyIsActive, true)) {
+							uid
+							companyName: companyName@{{.Language}}
+							companyIsActive
+						}
+					}
+					{{else}}
 					belongs_to_category @filter(eq(categoryIsActive, true)) {
 						uid
 						categoryName: categoryName@{{.Language}}
@@ -325,7 +385,7 @@ func (products *Products) ReadProductsByName(productName, language string) ([]Pr
 								uid
 								categoryName: categoryName@{{.Language}}
 								categoryIsActive
-								belong_to_company @filter(eq(companyIsActive, true)){
+								belongs_to_company @filter(eq(companyIsActive, true)){
 									uid
 									companyName: companyName@{{.Language}}
 									companyIsActive
@@ -342,16 +402,18 @@ func (products *Products) ReadProductsByName(productName, language string) ([]Pr
 							uid
 							categoryName: categoryName@{{.Language}}
 							categoryIsActive
-							belong_to_company @filter(eq(companyIsActive, true)){
+							belongs_to_company @filter(eq(companyIsActive, true)){
 								uid
 								companyName: companyName@{{.Language}}
 								companyIsActive
 							}
 						}
 					}
-					has_price @filter(eq(priceIsActive, true)) {
+					has_price @filter(eq(priceIsActive, true)) {{if .PricesLimit}}(orderdesc: priceDateTime, first: {{.PricesLimit}}){{end}} {
 						uid
 						priceValue
+						priceAmount
+						priceCurrency
 						priceDateTime
 						priceIsActive
 						belongs_to_company @filter(eq(companyIsActive, true)) {
@@ -366,7 +428,7 @@ func (products *Products) ReadProductsByName(productName, language string) ([]Pr
 							productIri
 							previewImageLink
 							productIsActive
-							has_price @filter(eq(priceIsActive, true)) {
+							has_price @filter(eq(priceIsActive, true)) {{if .PricesLimit}}(orderdesc: priceDateTime, first: {{.PricesLimit}}){{end}} {
 								uid
 								priceValue
 								priceDateTime
@@ -385,11 +447,12 @@ func (products *Products) ReadProductsByName(productName, language string) ([]Pr
 							cityIsActive
 						}
 					}
+					{{end}}
 				}
 			}`)
 
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
@@ -397,15 +460,16 @@ func (products *Products) ReadProductsByName(productName, language string) ([]Pr
 
 	err = productsByNameTemplate.Execute(&productsByNameQueryBuf, variables)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
-	transaction := products.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), productsByNameQueryBuf.String())
+	transaction := products.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, productsByNameQueryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return nil, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
 	type productsInStorage struct {
@@ -415,71 +479,81 @@ func (products *Products) ReadProductsByName(productName, language string) ([]Pr
 	var foundedProducts productsInStorage
 	err = json.Unmarshal(response.GetJson(), &foundedProducts)
 	if err != nil {
-		log.Println(err)
-		return nil, ErrProductsByNameCanNotBeFound
+		logError(err)
+		return nil, wrap(ErrProductsByNameCanNotBeFound, err)
 	}
 
 	if len(foundedProducts.AllProductsFoundedByName) == 0 {
-		return nil, ErrProductsByNameNotFound
+		return nil, fail(ErrProductsByNameNotFound)
 	}
 
-	return foundedProducts.AllProductsFoundedByName, nil
+	if projection.isSummary() {
+		foundedProducts.AllProductsFoundedByName = summaryOf(foundedProducts.AllProductsFoundedByName, projection)
+	}
+
+	return products.inCurrency(ctx, foundedProducts.AllProductsFoundedByName, projection)
 }
 
 // AddLanguageOfProductName is a method for add predicate "categoryName" for companyName value with new language
-func (products *Products) AddLanguageOfProductName(productID, name, language string) error {
+func (products *Products) AddLanguageOfProductName(ctx context.Context, productID, name, language string) error {
+	defer products.storage.invalidate(productID, productsTag)
+
 	forProductNamePredicate := fmt.Sprintf(`<%s> <productName> %s .`, productID, "\""+name+"\""+"@"+language)
 
 	mutation := dataBaseAPI.Mutation{
 		SetNquads: []byte(forProductNamePredicate),
 		CommitNow: true}
 
-	transaction := products.storage.Client.NewTxn()
-	_, err := transaction.Mutate(context.Background(), &mutation)
+	transaction := products.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err := transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrLanguageOfProductNameCanNotBeAdded
+		return wrap(ErrLanguageOfProductNameCanNotBeAdded, err)
 	}
 
 	return nil
 }
 
 // CreateProduct make product and save it to storage
-func (products *Products) CreateProduct(product Product, language string) (Product, error) {
-	existsProducts, err := products.ReadProductsByName(product.Name, language)
-	if err != nil && err != ErrProductsByNameNotFound {
-		log.Println(err)
-		return product, ErrProductCanNotBeCreated
+func (products *Products) CreateProduct(ctx context.Context, product Product, language string) (Product, error) {
+	defer products.storage.invalidate(productsTag)
+
+	existsProducts, err := products.ReadProductsByName(ctx, product.Name, language, Projection{})
+	if err != nil && !errors.Is(err, ErrProductsByNameNotFound) {
+		logError(err)
+		return product, wrap(ErrProductCanNotBeCreated, err)
 	}
 
 	if existsProducts != nil {
-		return existsProducts[0], ErrProductAlreadyExist
+		return existsProducts[0], fail(ErrProductAlreadyExist)
 	}
 
-	transaction := products.storage.Client.NewTxn()
+	transaction := products.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	product.IsActive = true
 	encodedProduct, err := json.Marshal(product)
 	if err != nil {
-		log.Println(err)
-		return product, ErrProductCanNotBeCreated
+		logError(err)
+		return product, wrap(ErrProductCanNotBeCreated, err)
 	}
 
 	mutation := &dataBaseAPI.Mutation{
 		SetJson:   encodedProduct,
 		CommitNow: true}
 
-	assigned, err := transaction.Mutate(context.Background(), mutation)
+	assigned, err := transaction.Mutate(ctx, mutation)
 	if err != nil {
-		log.Println(err)
-		return product, ErrProductCanNotBeCreated
+		logError(err)
+		return product, wrap(ErrProductCanNotBeCreated, err)
 	}
 
 	product.ID = assigned.Uids["blank-0"]
 	if product.ID == "" {
-		return product, ErrProductCanNotBeCreated
+		return product, fail(ErrProductCanNotBeCreated)
 	}
 
-	err = products.AddLanguageOfProductName(product.ID, product.Name, language)
+	err = products.AddLanguageOfProductName(ctx, product.ID, product.Name, language)
 	if err != nil {
 		return product, err
 	}
@@ -488,9 +562,11 @@ func (products *Products) CreateProduct(product Product, language string) (Produ
 }
 
 // DeleteProduct method for remove product from database
-func (products *Products) DeleteProduct(product Product) (string, error) {
+func (products *Products) DeleteProduct(ctx context.Context, product Product) (string, error) {
+	defer products.storage.invalidate(product.ID, productsTag)
+
 	if product.ID == "" {
-		return "", ErrProductCanNotBeWithoutID
+		return "", fail(ErrProductCanNotBeWithoutID)
 	}
 
 	deleteProductData, _ := json.Marshal(map[string]string{"uid": product.ID})
@@ -499,20 +575,26 @@ func (products *Products) DeleteProduct(product Product) (string, error) {
 		DeleteJson: deleteProductData,
 		CommitNow:  true}
 
-	transaction := products.storage.Client.NewTxn()
+	transaction := products.storage.newTransaction()
+	defer transaction.Discard(ctx)
 
 	var err error
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		log.Println(err)
-		return product.ID, ErrProductCanNotBeDeleted
+		logError(err)
+		return product.ID, wrap(ErrProductCanNotBeDeleted, err)
 	}
 
 	return product.ID, nil
 }
 
 // ReadProductByID is a method for get all nodes of products by ID
-func (products *Products) ReadProductByID(productID, language string) (Product, error) {
+func (products *Products) ReadProductByID(ctx context.Context, productID, language string) (Product, error) {
+	key := cacheKey("product", productID, language)
+	cached, generation := products.storage.lookup(ctx, key)
+	if cached != nil {
+		return cached.(Product), nil
+	}
 
 	variables := struct {
 		ProductID, Language string
@@ -538,7 +620,7 @@ func (products *Products) ReadProductByID(productID, language string) (Product,
 								uid
 								categoryName: categoryName@{{.Language}}
 								categoryIsActive
-								belong_to_company @filter(eq(companyIsActive, true)){
+								belongs_to_company @filter(eq(companyIsActive, true)){
 									uid
 									companyName: companyName@{{.Language}}
 									companyIsActive
@@ -553,7 +635,7 @@ func (products *Products) ReadProductByID(productID, language string) (Product,
 							uid
 							categoryName: categoryName@{{.Language}}
 							categoryIsActive
-							belong_to_company @filter(eq(companyIsActive, true)){
+							belongs_to_company @filter(eq(companyIsActive, true)){
 								uid
 								companyName: companyName@{{.Language}}
 								companyIsActive
@@ -563,8 +645,10 @@ func (products *Products) ReadProductByID(productID, language string) (Product,
 					has_price @filter(eq(priceIsActive, true)) (orderasc: priceDateTime) {
 						uid
 						priceValue
+						priceAmount
+						priceCurrency
 						priceDateTime
-						priceCity
+						priceLastSeenAt
 						priceIsActive
 						belongs_to_product @filter(eq(productIsActive, true)) {
 							uid
@@ -576,7 +660,6 @@ func (products *Products) ReadProductByID(productID, language string) (Product,
 								uid
 								priceValue
 								priceDateTime
-								priceCity
 								priceIsActive
 							}
 						}
@@ -591,22 +674,23 @@ func (products *Products) ReadProductByID(productID, language string) (Product,
 
 	product := Product{ID: productID}
 	if err != nil {
-		log.Println(err)
-		return product, ErrProductByIDCanNotBeFound
+		logError(err)
+		return product, wrap(ErrProductByIDCanNotBeFound, err)
 	}
 
 	queryBuf := bytes.Buffer{}
 	execErr := queryTemplate.Execute(&queryBuf, variables)
 	if execErr != nil {
-		log.Println(execErr)
+		logError(execErr)
 		return product, execErr
 	}
 
-	transaction := products.storage.Client.NewTxn()
-	response, err := transaction.Query(context.Background(), queryBuf.String())
+	transaction := products.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
 	if err != nil {
-		log.Println(err)
-		return product, ErrProductByIDCanNotBeFound
+		logError(err)
+		return product, wrap(ErrProductByIDCanNotBeFound, err)
 	}
 
 	type productsInStore struct {
@@ -617,19 +701,23 @@ func (products *Products) ReadProductByID(productID, language string) (Product,
 
 	err = json.Unmarshal(response.GetJson(), &foundedProducts)
 	if err != nil {
-		log.Println(err)
-		return product, ErrProductByIDCanNotBeFound
+		logError(err)
+		return product, wrap(ErrProductByIDCanNotBeFound, err)
 	}
 
 	if len(foundedProducts.Products) == 0 {
-		return product, ErrProductDoesNotExist
+		return product, fail(ErrProductDoesNotExist)
 	}
 
+	products.storage.remember(key, generation, foundedProducts.Products[0])
+
 	return foundedProducts.Products[0], nil
 }
 
 // AddCategoryToProduct method for set quad of predicate about product and category
-func (products *Products) AddCategoryToProduct(productID, categoryID string) error {
+func (products *Products) AddCategoryToProduct(ctx context.Context, productID, categoryID string) error {
+	defer products.storage.invalidate(productID, categoryID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -639,10 +727,11 @@ func (products *Products) AddCategoryToProduct(productID, categoryID string) err
 		SetNquads: []byte(forCategoryPredicate),
 		CommitNow: true}
 
-	transaction := products.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := products.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrProductCanNotBeAddedToCategory
+		return wrap(ErrProductCanNotBeAddedToCategory, err)
 	}
 
 	forProductPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, productID, "belongs_to_category", categoryID)
@@ -650,17 +739,20 @@ func (products *Products) AddCategoryToProduct(productID, categoryID string) err
 		SetNquads: []byte(forProductPredicate),
 		CommitNow: true}
 
-	transaction = products.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = products.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCategoryCanNotBeAddedToProduct
+		return wrap(ErrCategoryCanNotBeAddedToProduct, err)
 	}
 
 	return nil
 }
 
 // AddCompanyToProduct method for set quad of predicate about product and company
-func (products *Products) AddCompanyToProduct(productID, companyID string) error {
+func (products *Products) AddCompanyToProduct(ctx context.Context, productID, companyID string) error {
+	defer products.storage.invalidate(productID, companyID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -669,17 +761,20 @@ func (products *Products) AddCompanyToProduct(productID, companyID string) error
 		SetNquads: []byte(forProductPredicate),
 		CommitNow: true}
 
-	transaction := products.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := products.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrCompanyCanNotBeAddedToProduct
+		return wrap(ErrCompanyCanNotBeAddedToProduct, err)
 	}
 
 	return nil
 }
 
 // AddPriceToProduct method for set quad of predicate about product and price
-func (products *Products) AddPriceToProduct(productID, priceID string) error {
+func (products *Products) AddPriceToProduct(ctx context.Context, productID, priceID string) error {
+	defer products.storage.invalidate(productID, priceID)
+
 	var err error
 	var mutation dataBaseAPI.Mutation
 
@@ -688,10 +783,11 @@ func (products *Products) AddPriceToProduct(productID, priceID string) error {
 		SetNquads: []byte(forPricePredicate),
 		CommitNow: true}
 
-	transaction := products.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction := products.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrPriceCanNotBeAddedToProduct
+		return wrap(ErrPriceCanNotBeAddedToProduct, err)
 	}
 
 	forProductPredicate := fmt.Sprintf(`<%s> <%s> <%s> .`, productID, "has_price", priceID)
@@ -699,10 +795,11 @@ func (products *Products) AddPriceToProduct(productID, priceID string) error {
 		SetNquads: []byte(forProductPredicate),
 		CommitNow: true}
 
-	transaction = products.storage.Client.NewTxn()
-	_, err = transaction.Mutate(context.Background(), &mutation)
+	transaction = products.storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
 	if err != nil {
-		return ErrPriceCanNotBeAddedToProduct
+		return wrap(ErrPriceCanNotBeAddedToProduct, err)
 	}
 
 	return nil
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -9,13 +11,13 @@ func TestIntegrationProductCanBeCreated(test *testing.T) {
 	once.Do(prepareStorage)
 
 	productForCreate := Product{Name: "Test product"}
-	createdProduct, err := storage.Products.CreateProduct(productForCreate, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), productForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
@@ -25,8 +27,8 @@ func TestIntegrationProductCanBeCreated(test *testing.T) {
 		test.Fail()
 	}
 
-	existProduct, err := storage.Products.CreateProduct(productForCreate, "en")
-	if err == nil || err != ErrProductAlreadyExist {
+	existProduct, err := storage.Products.CreateProduct(context.Background(), productForCreate, "en")
+	if err == nil || !errors.Is(err, ErrProductAlreadyExist) {
 		test.Error(err)
 	}
 
@@ -40,8 +42,8 @@ func TestIntegrationProductsCanBeReadByName(test *testing.T) {
 
 	productForSearch := Product{Name: "Test product"}
 
-	productsFromStore, err := storage.Products.ReadProductsByName(productForSearch.Name, "en")
-	if err != ErrProductsByNameNotFound {
+	productsFromStore, err := storage.Products.ReadProductsByName(context.Background(), productForSearch.Name, "en", Projection{})
+	if !errors.Is(err, ErrProductsByNameNotFound) {
 		test.Fail()
 	}
 
@@ -49,19 +51,19 @@ func TestIntegrationProductsCanBeReadByName(test *testing.T) {
 		test.Fail()
 	}
 
-	createdProduct, err := storage.Products.CreateProduct(productForSearch, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), productForSearch, "en")
 	if err != nil || createdProduct.ID == "" {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	productsFromStore, err = storage.Products.ReadProductsByName(createdProduct.Name, "en")
+	productsFromStore, err = storage.Products.ReadProductsByName(context.Background(), createdProduct.Name, "en", Projection{})
 	if err != nil {
 		test.Fail()
 	}
@@ -84,13 +86,13 @@ func TestIntegrationProductCanBeReadById(test *testing.T) {
 
 	productForSearch := Product{Name: "Test product"}
 
-	productsFromStore, err := storage.Products.ReadProductsByName(productForSearch.Name, "en")
-	if err != ErrProductsByNameNotFound {
+	productsFromStore, err := storage.Products.ReadProductsByName(context.Background(), productForSearch.Name, "en", Projection{})
+	if !errors.Is(err, ErrProductsByNameNotFound) {
 		test.Fail()
 	}
 
-	productFromStore, err := storage.Products.ReadProductByID("0", ".")
-	if err != ErrProductDoesNotExist {
+	productFromStore, err := storage.Products.ReadProductByID(context.Background(), "0", ".")
+	if !errors.Is(err, ErrProductDoesNotExist) {
 		test.Fail()
 	}
 
@@ -98,19 +100,19 @@ func TestIntegrationProductCanBeReadById(test *testing.T) {
 		test.Fail()
 	}
 
-	createdProduct, err := storage.Products.CreateProduct(productForSearch, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), productForSearch, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	productFromStore, err = storage.Products.ReadProductByID(createdProduct.ID, ".")
+	productFromStore, err = storage.Products.ReadProductByID(context.Background(), createdProduct.ID, ".")
 	if err != nil {
 		test.Fail()
 	}
@@ -133,24 +135,24 @@ func TestIntegrationProductCanHasNameWithManyLanguages(test *testing.T) {
 
 	var err error
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Products.AddLanguageOfProductName(createdProduct.ID, "Тестовый продукт", "ru")
+	err = storage.Products.AddLanguageOfProductName(context.Background(), createdProduct.ID, "Тестовый продукт", "ru")
 	if err != nil {
 		test.Fail()
 	}
 
-	productWithEnName, err := storage.Products.ReadProductByID(createdProduct.ID, "en")
+	productWithEnName, err := storage.Products.ReadProductByID(context.Background(), createdProduct.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -159,7 +161,7 @@ func TestIntegrationProductCanHasNameWithManyLanguages(test *testing.T) {
 		test.Fail()
 	}
 
-	productWithRuName, err := storage.Products.ReadProductByID(createdProduct.ID, "ru")
+	productWithRuName, err := storage.Products.ReadProductByID(context.Background(), createdProduct.ID, "ru")
 	if err != nil {
 		test.Fail()
 	}
@@ -173,12 +175,12 @@ func TestIntegrationProductCanBeDeleted(test *testing.T) {
 	once.Do(prepareStorage)
 
 	productForCreate := Product{Name: "Test product"}
-	createdProduct, err := storage.Products.CreateProduct(productForCreate, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), productForCreate, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
-	deletedProductID, err := storage.Products.DeleteProduct(createdProduct)
+	deletedProductID, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 	if err != nil {
 		test.Error(err)
 	}
@@ -187,8 +189,8 @@ func TestIntegrationProductCanBeDeleted(test *testing.T) {
 		test.Fail()
 	}
 
-	_, err = storage.Products.ReadProductByID(deletedProductID, ".")
-	if err != ErrProductDoesNotExist {
+	_, err = storage.Products.ReadProductByID(context.Background(), deletedProductID, ".")
+	if !errors.Is(err, ErrProductDoesNotExist) {
 		test.Error(err)
 	}
 }
@@ -196,36 +198,36 @@ func TestIntegrationProductCanBeDeleted(test *testing.T) {
 func TestIntegrationCategoryCanBeAddedToProduct(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdCategory, err := storage.Categories.CreateCategory(Category{Name: "Test category"}, "en")
+	createdCategory, err := storage.Categories.CreateCategory(context.Background(), Category{Name: "Test category"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Categories.DeleteCategory(createdCategory)
+		_, err := storage.Categories.DeleteCategory(context.Background(), createdCategory)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Products.AddCategoryToProduct(createdProduct.ID, createdCategory.ID)
+	err = storage.Products.AddCategoryToProduct(context.Background(), createdProduct.ID, createdCategory.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedProduct, _ := storage.Products.ReadProductByID(createdProduct.ID, "en")
+	updatedProduct, _ := storage.Products.ReadProductByID(context.Background(), createdProduct.ID, "en")
 	if len(updatedProduct.Categories) < 1 || len(updatedProduct.Categories) > 1 {
 		test.Fatal()
 	}
@@ -238,36 +240,36 @@ func TestIntegrationCategoryCanBeAddedToProduct(test *testing.T) {
 func TestIntegrationCompanyCanBeAddedToProduct(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdCompany, err := storage.Companies.CreateCompany(Company{Name: "Test company"}, "en")
+	createdCompany, err := storage.Companies.CreateCompany(context.Background(), Company{Name: "Test company"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Companies.DeleteCompany(createdCompany)
+		_, err := storage.Companies.DeleteCompany(context.Background(), createdCompany)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Products.AddCompanyToProduct(createdProduct.ID, createdCompany.ID)
+	err = storage.Products.AddCompanyToProduct(context.Background(), createdProduct.ID, createdCompany.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedProduct, err := storage.Products.ReadProductByID(createdProduct.ID, "en")
+	updatedProduct, err := storage.Products.ReadProductByID(context.Background(), createdProduct.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -284,13 +286,13 @@ func TestIntegrationCompanyCanBeAddedToProduct(test *testing.T) {
 func TestIntegrationPriceCanBeAddedToProduct(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Error(err)
 		}
@@ -302,24 +304,24 @@ func TestIntegrationPriceCanBeAddedToProduct(test *testing.T) {
 		test.Error(err)
 	}
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: 123, DateTime: dateTime})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 123, DateTime: dateTime})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddPriceToProduct(createdProduct.ID, createdPrice.ID)
+	err = storage.Products.AddPriceToProduct(context.Background(), createdProduct.ID, createdPrice.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	updatedProduct, err := storage.Products.ReadProductByID(createdProduct.ID, "en")
+	updatedProduct, err := storage.Products.ReadProductByID(context.Background(), createdProduct.ID, "en")
 	if err != nil {
 		test.Error(err)
 	}
@@ -339,53 +341,53 @@ func TestIntegrationPriceCanBeAddedToProduct(test *testing.T) {
 
 func TestIntegrationProductCanHasPrice(test *testing.T) {
 	once.Do(prepareStorage)
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: 31.2})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 31.2})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	createdCity, err := storage.Cities.CreateCity(City{Name: "Test city"}, "en")
+	createdCity, err := storage.Cities.CreateCity(context.Background(), City{Name: "Test city"}, "en")
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Cities.DeleteCity(createdCity)
+		_, err := storage.Cities.DeleteCity(context.Background(), createdCity)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Prices.AddCityToPrice(createdPrice.ID, createdCity.ID)
+	err = storage.Prices.AddCityToPrice(context.Background(), createdPrice.ID, createdCity.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	createdProduct, err := storage.Products.CreateProduct(Product{Name: "Test product"}, "en")
+	createdProduct, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Test product"}, "en")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	err = storage.Products.AddPriceToProduct(createdProduct.ID, createdPrice.ID)
+	err = storage.Products.AddPriceToProduct(context.Background(), createdProduct.ID, createdPrice.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	productInStore, err := storage.Products.ReadProductByID(createdProduct.ID, "en")
+	productInStore, err := storage.Products.ReadProductByID(context.Background(), createdProduct.ID, "en")
 	if err != nil {
 		test.Fail()
 	}
@@ -402,67 +404,67 @@ func TestIntegrationProductCanHasPrice(test *testing.T) {
 func TestIntegrationProductsTotalCountCanBeReturned(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdProduct1, err := storage.Products.CreateProduct(Product{Name: "Первый тестовый продукт"}, "ru")
+	createdProduct1, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Первый тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct1)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct1)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct2, err := storage.Products.CreateProduct(Product{Name: "Второй тестовый продукт"}, "ru")
+	createdProduct2, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Второй тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct2)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct2)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct3, err := storage.Products.CreateProduct(Product{Name: "Третий тестовый продукт"}, "ru")
+	createdProduct3, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Третий тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct3)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct3)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct4, err := storage.Products.CreateProduct(Product{Name: "Четвёртый тестовый продукт"}, "ru")
+	createdProduct4, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Четвёртый тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct4)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct4)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct5, err := storage.Products.CreateProduct(Product{Name: "Пятый тестовый продукт"}, "ru")
+	createdProduct5, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Пятый тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct5)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct5)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	counterOfFoundedProducts, err := storage.Products.ReadTotalCountOfProductsByName("тестовый", "ru")
+	counterOfFoundedProducts, err := storage.Products.ReadTotalCountOfProductsByName(context.Background(), "тестовый", "ru")
 	if err != nil {
 		test.Error(err)
 	}
@@ -475,67 +477,67 @@ func TestIntegrationProductsTotalCountCanBeReturned(test *testing.T) {
 func TestIntegrationProductCanBeSearchedByNameWithPaginationAndCounter(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdProduct1, err := storage.Products.CreateProduct(Product{Name: "Первый тестовый продукт"}, "ru")
+	createdProduct1, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Первый тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct1)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct1)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct2, err := storage.Products.CreateProduct(Product{Name: "Второй тестовый продукт"}, "ru")
+	createdProduct2, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Второй тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct2)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct2)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct3, err := storage.Products.CreateProduct(Product{Name: "Третий тестовый продукт"}, "ru")
+	createdProduct3, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Третий тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct3)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct3)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct4, err := storage.Products.CreateProduct(Product{Name: "Четвёртый тестовый продукт"}, "ru")
+	createdProduct4, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Четвёртый тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct4)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct4)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	createdProduct5, err := storage.Products.CreateProduct(Product{Name: "Пятый тестовый продукт"}, "ru")
+	createdProduct5, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Пятый тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct5)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct5)
 		if err != nil {
 			test.Fail()
 		}
 	}()
 
-	foundedProductsForFirstPage, err := storage.Products.ReadProductsByNameWithPagination("тестовый", "ru", 1, 2)
+	foundedProductsForFirstPage, err := storage.Products.ReadProductsByNameWithPagination(context.Background(), "тестовый", "ru", 1, 2, Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -560,7 +562,7 @@ func TestIntegrationProductCanBeSearchedByNameWithPaginationAndCounter(test *tes
 		test.Errorf("Expected \"Второй тестовый продукт\", actual: %v", foundedProductsForFirstPage.Products[1].Name)
 	}
 
-	foundedProductsForFirstPage, err = storage.Products.ReadProductsByNameWithPagination("тестовый", "ru", 2, 2)
+	foundedProductsForFirstPage, err = storage.Products.ReadProductsByNameWithPagination(context.Background(), "тестовый", "ru", 2, 2, Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -573,7 +575,7 @@ func TestIntegrationProductCanBeSearchedByNameWithPaginationAndCounter(test *tes
 		test.Errorf("Expected \"Четвёртый тестовый продукт\", actual: %v", foundedProductsForFirstPage.Products[1].Name)
 	}
 
-	foundedProductsForFirstPage, err = storage.Products.ReadProductsByNameWithPagination("тестовый", "ru", 3, 2)
+	foundedProductsForFirstPage, err = storage.Products.ReadProductsByNameWithPagination(context.Background(), "тестовый", "ru", 3, 2, Projection{})
 	if err != nil {
 		test.Error(err)
 	}
@@ -598,13 +600,13 @@ func TestIntegrationProductCanBeSearchedByNameWithPaginationAndCounter(test *tes
 func TestPricesOfProductMustBeSortedByDate(test *testing.T) {
 	once.Do(prepareStorage)
 
-	createdProduct1, err := storage.Products.CreateProduct(Product{Name: "Первый тестовый продукт"}, "ru")
+	createdProduct1, err := storage.Products.CreateProduct(context.Background(), Product{Name: "Первый тестовый продукт"}, "ru")
 	if err != nil {
 		test.Fail()
 	}
 
 	defer func() {
-		_, err := storage.Products.DeleteProduct(createdProduct1)
+		_, err := storage.Products.DeleteProduct(context.Background(), createdProduct1)
 		if err != nil {
 			test.Error(err)
 		}
@@ -616,19 +618,19 @@ func TestPricesOfProductMustBeSortedByDate(test *testing.T) {
 		test.Error(err)
 	}
 
-	createdPrice, err := storage.Prices.CreatePrice(Price{Value: 123, DateTime: dateTime})
+	createdPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 123, DateTime: dateTime})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddPriceToProduct(createdProduct1.ID, createdPrice.ID)
+	err = storage.Products.AddPriceToProduct(context.Background(), createdProduct1.ID, createdPrice.ID)
 	if err != nil {
 		test.Error(err)
 	}
@@ -639,24 +641,24 @@ func TestPricesOfProductMustBeSortedByDate(test *testing.T) {
 		test.Error(err)
 	}
 
-	createdSecondPrice, err := storage.Prices.CreatePrice(Price{Value: 124, DateTime: secondDateTime})
+	createdSecondPrice, err := storage.Prices.CreatePrice(context.Background(), Price{Value: 124, DateTime: secondDateTime})
 	if err != nil {
 		test.Error(err)
 	}
 
 	defer func() {
-		_, err := storage.Prices.DeletePrice(createdSecondPrice)
+		_, err := storage.Prices.DeletePrice(context.Background(), createdSecondPrice)
 		if err != nil {
 			test.Error(err)
 		}
 	}()
 
-	err = storage.Products.AddPriceToProduct(createdProduct1.ID, createdSecondPrice.ID)
+	err = storage.Products.AddPriceToProduct(context.Background(), createdProduct1.ID, createdSecondPrice.ID)
 	if err != nil {
 		test.Error(err)
 	}
 
-	foundedProductsForFirstPage, err := storage.Products.ReadProductsByNameWithPagination("тестовый", "ru", 1, 1)
+	foundedProductsForFirstPage, err := storage.Products.ReadProductsByNameWithPagination(context.Background(), "тестовый", "ru", 1, 1, Projection{})
 	if err != nil {
 		test.Error(err)
 	}
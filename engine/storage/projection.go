@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hecatoncheir/Sproot/engine/money"
+)
+
+// ErrProjectionIsNotValid means that detail of projection is unknown, limit of prices is negative
+// or currency is not like RUB
+var ErrProjectionIsNotValid = errors.New("projection is not valid")
+
+// Detail is a level of detail of products found by name
+type Detail string
+
+const (
+	// Full products have categories, companies and prices with their cities, companies and products
+	Full Detail = "full"
+
+	// Summary products have name, IRI, image and the latest price for each city and company
+	Summary Detail = "summary"
+)
+
+// Projection is a level of detail of found products, a limit of prices of each product and a currency
+// which prices are converted to. Empty detail is Full, zero limit means all prices,
+// empty currency keeps currencies of prices.
+type Projection struct {
+	Detail      Detail `json:",omitempty"`
+	PricesLimit int    `json:",omitempty"`
+	Currency    string `json:",omitempty"`
+}
+
+// validate check that detail is known and limit of prices is not negative
+func (projection Projection) validate() error {
+	switch projection.Detail {
+	case "", Full, Summary:
+	default:
+		return wrap(ErrProjectionIsNotValid, fmt.Errorf("detail must be %v or %v, got %v", Full, Summary, projection.Detail))
+	}
+
+	if projection.PricesLimit < 0 {
+		return wrap(ErrProjectionIsNotValid, fmt.Errorf("limit of prices must not be negative, got %v", projection.PricesLimit))
+	}
+
+	if projection.Currency != "" {
+		err := money.ValidateCurrency(projection.Currency)
+		if err != nil {
+			return wrap(ErrProjectionIsNotValid, err)
+		}
+	}
+
+	return nil
+}
+
+// isSummary return true for projection of summary of products
+func (projection Projection) isSummary() bool {
+	return projection.Detail == Summary
+}
+
+// summaryOf make prices of products from their current offers, offers must be ordered from the latest,
+// so summary has the latest price of each pair of city and company. Count of prices of each product
+// is limited by PricesLimit of projection.
+func summaryOf(products []Product, projection Projection) []Product {
+	for index, product := range products {
+		var latest []Price
+		seen := map[string]bool{}
+
+		for _, offer := range product.Offers {
+			if projection.PricesLimit > 0 && len(latest) == projection.PricesLimit {
+				break
+			}
+
+			var city, company string
+			if len(offer.Cities) > 0 {
+				city = offer.Cities[0].ID
+			}
+
+			if len(offer.Companies) > 0 {
+				company = offer.Companies[0].ID
+			}
+
+			if seen[city+"/"+company] {
+				continue
+			}
+
+			seen[city+"/"+company] = true
+			latest = append(latest, Price{Value: offer.Value, Amount: offer.Amount, Currency: offer.Currency,
+				DateTime: offer.DateTime,
+				IsActive: true, Cities: offer.Cities, Companies: offer.Companies})
+		}
+
+		products[index].Prices, products[index].Offers = latest, nil
+	}
+
+	return products
+}
+
+// inCurrency return copies of products with prices converted to currency of projection
+func (products *Products) inCurrency(ctx context.Context, found []Product, projection Projection) ([]Product, error) {
+	if projection.Currency == "" {
+		return found, nil
+	}
+
+	rates, err := products.storage.Rates.ReadRateTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertProducts(found, rates, projection.Currency, products.storage.PricesCurrency())
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSummaryKeepsLatestPriceOfEachCityAndCompany(test *testing.T) {
+	moscow, spb := []City{{ID: "moscow"}}, []City{{ID: "spb"}}
+	company := []Company{{ID: "company"}}
+
+	products := []Product{{ID: "product", Offers: []Offer{
+		{Value: 3, Currency: "USD", Cities: moscow, Companies: company},
+		{Value: 2, Cities: spb, Companies: company},
+		{Value: 1, Cities: moscow, Companies: company}}}}
+
+	summary := summaryOf(products, Projection{Detail: Summary})
+	if len(summary[0].Prices) != 2 || summary[0].Prices[0].Value != 3 || summary[0].Prices[1].Value != 2 {
+		test.Error(summary[0].Prices)
+	}
+
+	if summary[0].Prices[0].Currency != "USD" || !summary[0].Prices[0].IsActive || summary[0].Offers != nil {
+		test.Error(summary[0])
+	}
+
+	products[0].Offers = []Offer{{Value: 3, Cities: moscow, Companies: company}, {Value: 0}}
+	summary = summaryOf(products, Projection{Detail: Summary, PricesLimit: 1})
+	if len(summary[0].Prices) != 1 || summary[0].Prices[0].Value != 3 {
+		test.Error(summary[0].Prices)
+	}
+}
+
+func TestProjectionCanBeValidated(test *testing.T) {
+	for _, projection := range []Projection{{}, {Detail: Full}, {Detail: Summary, PricesLimit: 5}} {
+		if err := projection.validate(); err != nil {
+			test.Error(projection, err)
+		}
+	}
+
+	for _, projection := range []Projection{{Detail: "short"}, {PricesLimit: -1}} {
+		err := projection.validate()
+		if !errors.Is(err, ErrProjectionIsNotValid) || KindOf(err) != InvalidInput {
+			test.Error(projection, err)
+		}
+	}
+}
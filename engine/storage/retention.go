@@ -0,0 +1,339 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+)
+
+// Resolutions of prices which are older than retention window
+const (
+	// PerDay keeps the latest price of each day of product in city of company
+	PerDay = "day"
+
+	// PerChange keeps prices which value differs from previous kept price of product in city of company
+	PerChange = "change"
+)
+
+// RetentionPolicy is a rule for downsample old prices. Prices of the last KeepDays are kept with full resolution,
+// older active prices are downsampled by Resolution. Removed prices are written to Archive as JSON lines
+// when it is set. Nothing is removed when DryRun is set.
+type RetentionPolicy struct {
+	KeepDays   int
+	Resolution string
+	Archive    io.Writer
+	DryRun     bool
+}
+
+// RetentionReport is a count of old prices which are read, kept and removed by retention
+type RetentionReport struct {
+	Products int
+	Read     int
+	Kept     int
+	Removed  int
+}
+
+func (report RetentionReport) String() string {
+	return fmt.Sprintf("products: %v, old prices: %v, kept: %v, removed: %v",
+		report.Products, report.Read, report.Kept, report.Removed)
+}
+
+// ArchivedPrice is a removed price with uids of its product, company and city
+type ArchivedPrice struct {
+	ID        string    `json:"uid"`
+	ProductID string    `json:"productID"`
+	CompanyID string    `json:"companyID,omitempty"`
+	CityID    string    `json:"cityID,omitempty"`
+	Value     float64   `json:"value"`
+	Amount    int64     `json:"amount,omitempty"`
+	Currency  string    `json:"currency,omitempty"`
+	DateTime  time.Time `json:"dateTime"`
+}
+
+var (
+	// ErrRetentionPolicyIsNotValid means that count of days is not positive or resolution is unknown
+	ErrRetentionPolicyIsNotValid = errors.New("retention policy is not valid")
+
+	// ErrRetentionCanNotBeApplied means that the old prices can't be read, archived or removed
+	ErrRetentionCanNotBeApplied = errors.New("retention can not be applied")
+)
+
+const retentionBatchSize = 100
+
+var oldPricesTemplate = template.Must(template.New("ApplyRetention").Parse(`{
+				products(func: has(productName), first: {{.First}}{{if .After}}, after: {{.After}}{{end}}) {
+					uid
+					has_price @filter(eq(priceIsActive, true) AND lt(priceDateTime, "{{.Before}}")) (orderasc: priceDateTime) {
+						uid
+						priceValue
+						priceAmount
+						priceCurrency
+						priceDateTime
+						priceLastSeenAt
+						belongs_to_company {
+							uid
+						}
+						belongs_to_city {
+							uid
+						}
+					}
+				}
+			}`))
+
+// ApplyRetention is a method for downsample active prices which are older than KeepDays of policy before now.
+// Products are processed in batches, removed prices of batch are archived after they are removed.
+func (prices *Prices) ApplyRetention(ctx context.Context, policy RetentionPolicy, now time.Time) (RetentionReport, error) {
+	var report RetentionReport
+
+	if policy.KeepDays <= 0 {
+		return report, wrap(ErrRetentionPolicyIsNotValid, fmt.Errorf("days must be positive, got %v", policy.KeepDays))
+	}
+
+	if policy.Resolution != PerDay && policy.Resolution != PerChange {
+		return report, wrap(ErrRetentionPolicyIsNotValid,
+			fmt.Errorf("resolution must be %v or %v, got %v", PerDay, PerChange, policy.Resolution))
+	}
+
+	variables := struct {
+		First         int
+		After, Before string
+	}{
+		First:  retentionBatchSize,
+		Before: now.AddDate(0, 0, -policy.KeepDays).UTC().Format(time.RFC3339)}
+
+	for {
+		queryBuf := bytes.Buffer{}
+		err := oldPricesTemplate.Execute(&queryBuf, variables)
+		if err != nil {
+			logError(err)
+			return report, wrap(ErrRetentionCanNotBeApplied, err)
+		}
+
+		transaction := prices.storage.newReadTransaction(ctx)
+		response, err := transaction.Query(ctx, queryBuf.String())
+		transaction.Discard(ctx)
+		if err != nil {
+			logError(err)
+			return report, wrap(ErrRetentionCanNotBeApplied, err)
+		}
+
+		var found struct {
+			Products []Product `json:"products"`
+		}
+
+		err = json.Unmarshal(response.GetJson(), &found)
+		if err != nil {
+			logError(err)
+			return report, wrap(ErrRetentionCanNotBeApplied, err)
+		}
+
+		var removed []ArchivedPrice
+		lastSeen := map[string]time.Time{}
+		for _, product := range found.Products {
+			report.Products++
+			report.Read += len(product.Prices)
+
+			removedOfProduct, lastSeenOfProduct := downsample(product.Prices, policy.Resolution)
+			for _, price := range removedOfProduct {
+				removed = append(removed, archivedPriceOf(product.ID, price))
+			}
+
+			for priceID, seenAt := range lastSeenOfProduct {
+				lastSeen[priceID] = seenAt
+			}
+		}
+
+		report.Removed += len(removed)
+
+		// prices are archived before they are removed, so failed archive keeps prices in database.
+		// Prices archived before failed removal are archived again by the next retention.
+		if !policy.DryRun && len(removed) > 0 && policy.Archive != nil {
+			err = writeArchive(policy.Archive, removed)
+			if err != nil {
+				report.Removed -= len(removed)
+				report.Kept = report.Read - report.Removed
+				return report, err
+			}
+		}
+
+		if !policy.DryRun && len(removed) > 0 {
+			err = prices.removePrices(ctx, removed, lastSeen)
+			if err != nil {
+				report.Removed -= len(removed)
+				report.Kept = report.Read - report.Removed
+				return report, err
+			}
+		}
+
+		report.Kept = report.Read - report.Removed
+
+		if len(found.Products) < retentionBatchSize {
+			return report, nil
+		}
+
+		variables.After = found.Products[len(found.Products)-1].ID
+	}
+}
+
+// downsample return prices which are removed by resolution, prices must be ordered from the oldest.
+// Per change the kept price gets the latest time when its value was seen by removed prices of the same value,
+// these times are returned by uids of kept prices.
+func downsample(prices []Price, resolution string) ([]Price, map[string]time.Time) {
+	var removed []Price
+	lastSeen := map[string]time.Time{}
+
+	kept := map[string]Price{}
+	for _, price := range prices {
+		series := seriesOf(price)
+		previous, ok := kept[series]
+		kept[series] = price
+
+		if !ok {
+			continue
+		}
+
+		switch resolution {
+		case PerDay:
+			if sameDay(previous.DateTime, price.DateTime) {
+				removed = append(removed, previous)
+			}
+		case PerChange:
+			if previous.Value == price.Value && previous.Currency == price.Currency {
+				removed = append(removed, price)
+				kept[series] = previous
+
+				if seenAt := lastSeenOf(price); seenAt.After(lastSeenOf(previous)) && seenAt.After(lastSeen[previous.ID]) {
+					lastSeen[previous.ID] = seenAt
+				}
+			}
+		}
+	}
+
+	return removed, lastSeen
+}
+
+// lastSeenOf is the latest time when value of price was parsed, prices saved before it have only date
+func lastSeenOf(price Price) time.Time {
+	if price.LastSeenAt.After(price.DateTime) {
+		return price.LastSeenAt
+	}
+
+	return price.DateTime
+}
+
+// seriesOf is a key of company and city of price
+func seriesOf(price Price) string {
+	var company, city string
+	if len(price.Companies) > 0 {
+		company = price.Companies[0].ID
+	}
+
+	if len(price.Cities) > 0 {
+		city = price.Cities[0].ID
+	}
+
+	return company + "/" + city
+}
+
+func sameDay(first, second time.Time) bool {
+	first, second = first.UTC(), second.UTC()
+	return first.Year() == second.Year() && first.YearDay() == second.YearDay()
+}
+
+func archivedPriceOf(productID string, price Price) ArchivedPrice {
+	archived := ArchivedPrice{ID: price.ID, ProductID: productID, Value: price.Value, Amount: price.Amount,
+		Currency: price.Currency, DateTime: price.DateTime}
+
+	if len(price.Companies) > 0 {
+		archived.CompanyID = price.Companies[0].ID
+	}
+
+	if len(price.Cities) > 0 {
+		archived.CityID = price.Cities[0].ID
+	}
+
+	return archived
+}
+
+// removePrices delete prices with edges of their products and move last seen times to kept prices in one mutation
+func (prices *Prices) removePrices(ctx context.Context, removed []ArchivedPrice, lastSeen map[string]time.Time) error {
+	nquads, seenNquads := bytes.Buffer{}, bytes.Buffer{}
+	changed := map[string]bool{}
+
+	for _, price := range removed {
+		fmt.Fprintf(&nquads, "<%s> * * .\n<%s> <has_price> <%s> .\n", price.ID, price.ProductID, price.ID)
+		changed[price.ProductID] = true
+	}
+
+	for priceID, seenAt := range lastSeen {
+		fmt.Fprintf(&seenNquads, "<%s> <priceLastSeenAt> %q .\n", priceID, seenAt.UTC().Format(time.RFC3339Nano))
+		changed[priceID] = true
+	}
+
+	for uid := range changed {
+		defer prices.storage.invalidate(uid)
+	}
+
+	mutation := &dataBaseAPI.Mutation{
+		SetNquads: seenNquads.Bytes(),
+		DelNquads: nquads.Bytes(),
+		CommitNow: true}
+
+	transaction := prices.storage.newTransaction()
+	defer transaction.Discard(ctx)
+
+	_, err := transaction.Mutate(ctx, mutation)
+	if err != nil {
+		logError(err)
+		return wrap(ErrRetentionCanNotBeApplied, err)
+	}
+
+	return nil
+}
+
+// writeArchive write removed prices to archive as JSON lines by one write,
+// archive is flushed and synced when it can be, so lines are stored before prices are removed
+func writeArchive(archive io.Writer, removed []ArchivedPrice) error {
+	lines := bytes.Buffer{}
+
+	for _, price := range removed {
+		line, err := json.Marshal(price)
+		if err != nil {
+			logError(err)
+			return wrap(ErrRetentionCanNotBeApplied, err)
+		}
+
+		lines.Write(append(line, '\n'))
+	}
+
+	_, err := archive.Write(lines.Bytes())
+	if err != nil {
+		logError(err)
+		return wrap(ErrRetentionCanNotBeApplied, err)
+	}
+
+	if flusher, ok := archive.(interface{ Flush() error }); ok {
+		err = flusher.Flush()
+		if err != nil {
+			logError(err)
+			return wrap(ErrRetentionCanNotBeApplied, err)
+		}
+	}
+
+	if syncer, ok := archive.(interface{ Sync() error }); ok {
+		err = syncer.Sync()
+		if err != nil {
+			logError(err)
+			return wrap(ErrRetentionCanNotBeApplied, err)
+		}
+	}
+
+	return nil
+}
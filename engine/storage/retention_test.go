@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOldPricesAreDownsampledPerDayOrPerChange(test *testing.T) {
+	dateTime := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	first, second := []Company{{ID: "0x1"}}, []Company{{ID: "0x2"}}
+
+	prices := []Price{
+		{ID: "0xa", Value: 10, DateTime: dateTime, Companies: first},
+		{ID: "0xb", Value: 10, DateTime: dateTime, Companies: second},
+		{ID: "0xc", Value: 12, DateTime: dateTime.Add(time.Hour), Companies: first},
+		{ID: "0xd", Value: 12, DateTime: dateTime.Add(2 * time.Hour), Companies: first},
+		{ID: "0xe", Value: 10, DateTime: dateTime.AddDate(0, 0, 1), Companies: first}}
+
+	idsOf := func(prices []Price) string {
+		var ids []string
+		for _, price := range prices {
+			ids = append(ids, price.ID)
+		}
+
+		return strings.Join(ids, ",")
+	}
+
+	removed, lastSeen := downsample(prices, PerDay)
+	if idsOf(removed) != "0xa,0xc" || len(lastSeen) != 0 {
+		test.Error(idsOf(removed), lastSeen)
+	}
+
+	removed, lastSeen = downsample(prices, PerChange)
+	if idsOf(removed) != "0xd" || len(lastSeen) != 1 || !lastSeen["0xc"].Equal(dateTime.Add(2*time.Hour)) {
+		test.Error(idsOf(removed), lastSeen)
+	}
+
+	prices[3].LastSeenAt = dateTime.Add(5 * time.Hour)
+	prices = append(prices[:4], Price{ID: "0xf", Value: 12, DateTime: dateTime.Add(3 * time.Hour), Companies: first})
+
+	removed, lastSeen = downsample(prices, PerChange)
+	if idsOf(removed) != "0xd,0xf" || !lastSeen["0xc"].Equal(dateTime.Add(5*time.Hour)) {
+		test.Error(idsOf(removed), lastSeen)
+	}
+}
+
+func TestRetentionPolicyMustBeValid(test *testing.T) {
+	prices := &Prices{}
+
+	for _, policy := range []RetentionPolicy{{KeepDays: 0, Resolution: PerDay}, {KeepDays: 30, Resolution: "week"}} {
+		_, err := prices.ApplyRetention(context.Background(), policy, time.Now())
+		if !errors.Is(err, ErrRetentionPolicyIsNotValid) || KindOf(err) != InvalidInput {
+			test.Error(policy, err)
+		}
+	}
+}
+
+func TestIntegrationOldPricesAreArchivedAndRemoved(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Retention test product"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -40)
+	for _, price := range []Price{
+		{Value: 10, DateTime: old},
+		{Value: 11, DateTime: old.Add(time.Hour)},
+		{Value: 12, DateTime: now.Add(-time.Hour)},
+		{Value: 13, DateTime: now.Add(-time.Minute)}} {
+		price.IsActive = true
+		created, err := storage.Prices.CreatePrice(ctx, price)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		defer storage.Prices.DeletePrice(ctx, created)
+
+		err = storage.Products.AddPriceToProduct(ctx, product.ID, created.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	archive := bytes.Buffer{}
+	_, err = storage.Prices.ApplyRetention(ctx, RetentionPolicy{KeepDays: 30, Resolution: PerDay, Archive: &archive}, now)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var archived ArchivedPrice
+	err = json.Unmarshal(archive.Bytes(), &archived)
+	if err != nil || archived.Value != 10 || archived.ProductID != product.ID {
+		test.Fatal(archived, err)
+	}
+
+	read, err := storage.Products.ReadProductByID(ctx, product.ID, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(read.Prices) != 3 {
+		test.Error(read.Prices)
+	}
+}
+
+func TestIntegrationKeptPriceHasLastSeenOfRemovedPricesOfSameValue(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Retention per change test product"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -40)
+	for _, price := range []Price{
+		{Value: 10, DateTime: old},
+		{Value: 10, DateTime: old.Add(time.Hour), LastSeenAt: old.Add(3 * time.Hour)}} {
+		price.IsActive = true
+		created, err := storage.Prices.CreatePrice(ctx, price)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		defer storage.Prices.DeletePrice(ctx, created)
+
+		err = storage.Products.AddPriceToProduct(ctx, product.ID, created.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	report, err := storage.Prices.ApplyRetention(ctx, RetentionPolicy{KeepDays: 30, Resolution: PerChange}, now)
+	if err != nil || report.Removed != 1 {
+		test.Fatal(report, err)
+	}
+
+	read, err := storage.Products.ReadProductByID(ctx, product.ID, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(read.Prices) != 1 || !read.Prices[0].DateTime.Equal(old) || !read.Prices[0].LastSeenAt.Equal(old.Add(3*time.Hour)) {
+		test.Error(read.Prices)
+	}
+}
+
+// failingArchive is an archive which can't be written, like full disk
+type failingArchive struct{}
+
+func (failingArchive) Write([]byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestArchiveIsFlushedAndSynced(test *testing.T) {
+	archive := &syncedArchive{}
+
+	err := writeArchive(archive, []ArchivedPrice{{ID: "0x1", Value: 10}})
+	if err != nil || !archive.flushed || !archive.synced || archive.Len() == 0 {
+		test.Error(archive, err)
+	}
+
+	err = writeArchive(failingArchive{}, []ArchivedPrice{{ID: "0x1", Value: 10}})
+	if !errors.Is(err, ErrRetentionCanNotBeApplied) {
+		test.Error(err)
+	}
+}
+
+// syncedArchive is an archive which remembers that it was flushed and synced
+type syncedArchive struct {
+	bytes.Buffer
+	flushed, synced bool
+}
+
+func (archive *syncedArchive) Flush() error {
+	archive.flushed = true
+	return nil
+}
+
+func (archive *syncedArchive) Sync() error {
+	archive.synced = true
+	return nil
+}
+
+func TestIntegrationPricesAreNotRemovedWhenArchiveFails(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Retention failed archive test product"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -40)
+	for _, price := range []Price{
+		{Value: 10, DateTime: old},
+		{Value: 11, DateTime: old.Add(time.Hour)}} {
+		price.IsActive = true
+		created, err := storage.Prices.CreatePrice(ctx, price)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		defer storage.Prices.DeletePrice(ctx, created)
+
+		err = storage.Products.AddPriceToProduct(ctx, product.ID, created.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	report, err := storage.Prices.ApplyRetention(ctx, RetentionPolicy{KeepDays: 30, Resolution: PerDay, Archive: failingArchive{}}, now)
+	if !errors.Is(err, ErrRetentionCanNotBeApplied) || report.Removed != 0 {
+		test.Fatal(report, err)
+	}
+
+	read, err := storage.Products.ReadProductByID(ctx, product.ID, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(read.Prices) != 2 {
+		test.Error(read.Prices)
+	}
+}
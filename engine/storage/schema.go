@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+)
+
+// Migration is a change of schema of database.
+// Schema is applied by Alter operation, DropPredicates are removed from database with all values.
+type Migration struct {
+	Version        int
+	Description    string
+	Schema         string
+	DropPredicates []string
+}
+
+// Migrations is an ordered list of all changes of schema of database.
+// New migration must be added to the end of list with next version, applied migrations must not be changed.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "schema of all resources",
+		Schema: `
+			categoryName: string @lang @index(term) .
+			categoryIsActive: bool @index(bool) .
+			has_product: uid .
+
+			companyName: string @lang @index(term) .
+			companyIsActive: bool @index(bool) .
+			has_category: uid @count .
+
+			productName: string @lang @index(term, trigram) .
+			productIri: string @index(term) .
+			productImageLink: string @index(term) .
+			productIsActive: bool @index(bool) .
+			belongs_to_category: uid .
+
+			pricesValue: float @index(float) .
+			priceDateTime: dateTime @index(day) .
+			priceIsActive: bool @index(bool) .
+			belongs_to_city: uid @count .
+			belongs_to_product: uid @count .
+			belongs_to_company: uid @count .
+
+			cityName: string @lang @index(term) .
+			cityIsActive: bool @index(bool) .
+
+			instructionLanguage: string @index(term) .
+			instructionIsActive: bool @index(bool) .
+			has_company: uid @count .
+			has_city: uid @count .
+			has_page: uid @count .
+
+			path: string @index(term) .
+			pageInPaginationSelector: string @index(term) .
+			previewImageOfSelector: string @index(term) .
+			pageParamPath: string @index(term) .
+			pageCityPath: string @index(term) .
+			itemSelector: string @index(term) .
+			nameOfItemSelector: string @index(term) .
+			priceOfItemSelector: string @index(term) .
+			cityInCookieKey: string @index(term) .
+			cityIdForCookie: string @index(term) .
+			pageInstructionIsActive: bool @index(bool) .
+		`},
+	{
+		Version:     2,
+		Description: "index priceValue predicate which is used by prices instead of pricesValue",
+		Schema: `
+			priceValue: float @index(float) .
+		`,
+		DropPredicates: []string{"pricesValue"}},
+	{
+		Version:     3,
+		Description: "declare predicates used by structures and queries, drop predicates which are never written",
+		Schema: `
+			companyIri: string @index(term) .
+			previewImageLink: string @index(term) .
+			has_price: uid @count .
+			cityParamPath: string @index(term) .
+			linkOfItemSelector: string @index(term) .
+		`,
+		DropPredicates: []string{"productImageLink", "pageCityPath"}},
+	{
+		Version:     4,
+		Description: "current offers of products in cities of companies updated by the latest prices",
+		Schema: `
+			offerKey: string @index(exact) @upsert .
+			offerValue: float @index(float) .
+			offerPreviousValue: float .
+			offerDateTime: dateTime @index(hour) .
+			has_offer: uid @count .
+		`},
+	{
+		Version:     5,
+		Description: "time of the last parse of the same value of price",
+		Schema: `
+			priceLastSeenAt: dateTime .
+		`},
+	{
+		Version:     6,
+		Description: "exact amounts of prices in minor units of currencies and rates of currencies",
+		Schema: `
+			priceAmount: int .
+			priceCurrency: string @index(exact) .
+			offerCurrency: string .
+			rateCurrency: string @index(exact) @upsert .
+			rateValue: string .
+			rateDateTime: dateTime .
+		`},
+	{
+		Version:     7,
+		Description: "exact amounts of offers and amounts in reference currency for order of offers in currency",
+		Schema: `
+			offerAmount: int .
+			offerReferenceAmount: int @index(int) .
+		`},
+	{
+		Version:     8,
+		Description: "exported uids of nodes of resumable imports of graph for match of nodes written before failure",
+		Schema: `
+			graphImportID: string @index(exact) .
+		`},
+}
+
+// LatestSchemaVersion is a version of schema of database which is expected by engine
+func LatestSchemaVersion() int {
+	return Migrations[len(Migrations)-1].Version
+}
+
+var (
+	// ErrSchemaVersionCanNotBeRead means that the version of schema can't be read from database
+	ErrSchemaVersionCanNotBeRead = errors.New("schema version can not be read")
+
+	// ErrSchemaVersionCanNotBeSaved means that the version of schema can't be saved to database
+	ErrSchemaVersionCanNotBeSaved = errors.New("schema version can not be saved")
+
+	// ErrSchemaIsOutdated means that the database has pending migrations
+	ErrSchemaIsOutdated = errors.New("schema of database is outdated, pending migrations must be applied")
+
+	// ErrSchemaIsNewer means that the database was migrated by newer version of engine
+	ErrSchemaIsNewer = errors.New("schema of database is newer than schema of engine")
+)
+
+// schemaVersionSchema is a schema of node with version of schema, it is not a part of migrations
+const schemaVersionSchema = `schemaVersion: int .`
+
+type schemaVersionInStorage struct {
+	ID      string `json:"uid,omitempty"`
+	Version int    `json:"schemaVersion"`
+}
+
+func (storage *Storage) readSchemaVersion(ctx context.Context) (schemaVersionInStorage, error) {
+	query := `{
+				versions(func: has(schemaVersion)) {
+					uid
+					schemaVersion
+				}
+			}`
+
+	transaction := storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, query)
+	if err != nil {
+		logError(err)
+		return schemaVersionInStorage{}, wrap(ErrSchemaVersionCanNotBeRead, err)
+	}
+
+	type versionsInStorage struct {
+		Versions []schemaVersionInStorage `json:"versions"`
+	}
+
+	var foundedVersions versionsInStorage
+	err = json.Unmarshal(response.GetJson(), &foundedVersions)
+	if err != nil {
+		logError(err)
+		return schemaVersionInStorage{}, wrap(ErrSchemaVersionCanNotBeRead, err)
+	}
+
+	if len(foundedVersions.Versions) == 0 {
+		return schemaVersionInStorage{}, nil
+	}
+
+	return foundedVersions.Versions[0], nil
+}
+
+// SchemaVersion is a method for get version of schema saved in database, 0 means that no migrations were applied
+func (storage *Storage) SchemaVersion(ctx context.Context) (int, error) {
+	version, err := storage.readSchemaVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return version.Version, nil
+}
+
+func (storage *Storage) saveSchemaVersion(ctx context.Context, version int) error {
+	current, err := storage.readSchemaVersion(ctx)
+	if err != nil {
+		return wrap(ErrSchemaVersionCanNotBeSaved, err)
+	}
+
+	subject := "_:schema"
+	if current.ID != "" {
+		subject = fmt.Sprintf("<%s>", current.ID)
+	}
+
+	predicate := fmt.Sprintf(`%s <schemaVersion> "%d" .`, subject, version)
+	mutation := dataBaseAPI.Mutation{
+		SetNquads: []byte(predicate),
+		CommitNow: true}
+
+	transaction := storage.newTransaction()
+	defer transaction.Discard(ctx)
+	_, err = transaction.Mutate(ctx, &mutation)
+	if err != nil {
+		logError(err)
+		return wrap(ErrSchemaVersionCanNotBeSaved, err)
+	}
+
+	return nil
+}
+
+// CheckSchema is a method for compare version of schema in database with version expected by engine
+func (storage *Storage) CheckSchema(ctx context.Context) error {
+	version, err := storage.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version > LatestSchemaVersion() {
+		return fail(ErrSchemaIsNewer)
+	}
+
+	if version < LatestSchemaVersion() {
+		return fail(ErrSchemaIsOutdated)
+	}
+
+	return nil
+}
+
+// PendingMigrations is a method for get migrations which are not applied to database yet
+func (storage *Storage) PendingMigrations(ctx context.Context) ([]Migration, error) {
+	version, err := storage.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > LatestSchemaVersion() {
+		return nil, fail(ErrSchemaIsNewer)
+	}
+
+	var pending []Migration
+	for _, migration := range Migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Migrate is a method for apply all pending migrations to database in order.
+// Version of schema is saved after each migration, so Migrate can be repeated after failure.
+func (storage *Storage) Migrate(ctx context.Context) ([]Migration, error) {
+	defer storage.Cache.Purge()
+
+	if storage.Client == nil {
+		err := storage.Connect()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := storage.PendingMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+
+	schemaVersionOperation := &dataBaseAPI.Operation{Schema: schemaVersionSchema}
+	err = storage.alter(ctx, schemaVersionOperation)
+	if err != nil {
+		logError(err)
+		return applied, err
+	}
+
+	for _, migration := range pending {
+		if migration.Schema != "" {
+			operation := &dataBaseAPI.Operation{Schema: migration.Schema}
+
+			err = storage.alter(ctx, operation)
+			if err != nil {
+				logError(err)
+				return applied, err
+			}
+		}
+
+		for _, predicate := range migration.DropPredicates {
+			operation := &dataBaseAPI.Operation{DropAttr: predicate}
+
+			err = storage.alter(ctx, operation)
+			if err != nil {
+				logError(err)
+				return applied, err
+			}
+		}
+
+		err = storage.saveSchemaVersion(ctx, migration.Version)
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
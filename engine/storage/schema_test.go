@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMigrationsMustBeOrderedByVersion(test *testing.T) {
+	for index, migration := range Migrations {
+		if migration.Version != index+1 {
+			test.Errorf("migration %v must have version %v", migration.Description, index+1)
+		}
+
+		if migration.Schema == "" && len(migration.DropPredicates) == 0 {
+			test.Errorf("migration %v does nothing", migration.Version)
+		}
+	}
+
+	if LatestSchemaVersion() != len(Migrations) {
+		test.Fail()
+	}
+}
+
+func TestIntegrationMigrationsCanBeAppliedToDatabase(test *testing.T) {
+	once.Do(prepareStorage)
+
+	pending, err := storage.PendingMigrations(context.Background())
+	if err != nil {
+		test.Error(err)
+	}
+
+	if len(pending) != 0 {
+		test.Fail()
+	}
+
+	applied, err := storage.Migrate(context.Background())
+	if err != nil {
+		test.Error(err)
+	}
+
+	if len(applied) != 0 {
+		test.Fail()
+	}
+
+	version, err := storage.SchemaVersion(context.Background())
+	if err != nil {
+		test.Error(err)
+	}
+
+	if version != LatestSchemaVersion() {
+		test.Fail()
+	}
+
+	err = storage.CheckSchema(context.Background())
+	if err != nil {
+		test.Error(err)
+	}
+}
+
+func TestIntegrationNewerSchemaCanNotBeUsed(test *testing.T) {
+	once.Do(prepareStorage)
+
+	err := storage.saveSchemaVersion(context.Background(), LatestSchemaVersion()+1)
+	if err != nil {
+		test.Error(err)
+	}
+
+	defer func() {
+		err := storage.saveSchemaVersion(context.Background(), LatestSchemaVersion())
+		if err != nil {
+			test.Error(err)
+		}
+	}()
+
+	err = storage.CheckSchema(context.Background())
+	if !errors.Is(err, ErrSchemaIsNewer) {
+		test.Error(err)
+	}
+
+	_, err = storage.Migrate(context.Background())
+	if !errors.Is(err, ErrSchemaIsNewer) {
+		test.Error(err)
+	}
+}
@@ -0,0 +1,478 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/money"
+)
+
+// ProductPriceStatistics are aggregates of active prices of product in window of dates.
+// Minimum, maximum, average and median are calculated for prices of window, all-time low for all prices.
+// Volatility is a standard deviation of prices of window divided by their average.
+// Prices are converted to currency of statistics by rates of storage.
+type ProductPriceStatistics struct {
+	ProductID  string    `json:"productID"`
+	Currency   string    `json:"currency"`
+	From       time.Time `json:"from,omitempty"`
+	To         time.Time `json:"to,omitempty"`
+	Count      int       `json:"count"`
+	Minimum    float64   `json:"minimum"`
+	Maximum    float64   `json:"maximum"`
+	Average    float64   `json:"average"`
+	Median     float64   `json:"median"`
+	Volatility float64   `json:"volatility"`
+	AllTimeLow float64   `json:"allTimeLow"`
+}
+
+// CategoryPriceStatistics is an average of active prices of active products of category in window of dates
+// in currency of statistics
+type CategoryPriceStatistics struct {
+	CategoryID   string  `json:"categoryID"`
+	CategoryName string  `json:"categoryName"`
+	Currency     string  `json:"currency"`
+	Products     int     `json:"products"`
+	Count        int     `json:"count"`
+	Average      float64 `json:"average"`
+}
+
+var (
+	// ErrPriceStatisticsCanNotBeRead means that the aggregates of prices can't be read from database
+	ErrPriceStatisticsCanNotBeRead = errors.New("price statistics can not be read")
+
+	// ErrPriceStatisticsWindowIsNotValid means that uid of product is not like 0x1a, window ends before it starts
+	// or currency of statistics is not like RUB
+	ErrPriceStatisticsWindowIsNotValid = errors.New("window of price statistics is not valid")
+)
+
+// priceWindow is a condition of dates of prices for templates of queries, empty dates are not used.
+// Prices are aggregated by database for each group of currency.
+type priceWindow struct {
+	ProductID, From, To, Language string
+	Groups                        []currencyGroup
+}
+
+// currencyGroup is a condition of prices in one currency for templates of queries,
+// prices without currency are in group of default currency
+type currencyGroup struct {
+	Index    int
+	Currency string
+	Filter   string
+}
+
+// currencyGroupsOf return group for each currency which has rate to reference currency,
+// for reference currency and for default currency
+func currencyGroupsOf(rates money.Rates, defaultCurrency string) []currencyGroup {
+	currencies := []string{rates.Reference, defaultCurrency}
+	for currency := range rates.ToReference {
+		currencies = append(currencies, currency)
+	}
+
+	sort.Strings(currencies)
+
+	var groups []currencyGroup
+	for index, currency := range currencies {
+		if index > 0 && currencies[index-1] == currency {
+			continue
+		}
+
+		group := currencyGroup{Index: len(groups), Currency: currency,
+			Filter: fmt.Sprintf(`eq(priceCurrency, "%v")`, currency)}
+
+		if currency == defaultCurrency {
+			group.Filter = fmt.Sprintf(`(eq(priceCurrency, "%v") OR NOT has(priceCurrency))`, currency)
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// OtherCurrencies is a condition of prices in currencies which have no rates
+func (window priceWindow) OtherCurrencies() string {
+	filters := make([]string, len(window.Groups))
+	for index, group := range window.Groups {
+		filters[index] = group.Filter
+	}
+
+	return "NOT (" + strings.Join(filters, " OR ") + ")"
+}
+
+// currencyOfStatistics return currency of statistics, empty currency is currency of prices which are saved without currency
+func (prices *Prices) currencyOfStatistics(currency string) (string, error) {
+	if currency == "" {
+		return prices.storage.PricesCurrency(), nil
+	}
+
+	err := money.ValidateCurrency(currency)
+	if err != nil {
+		return currency, wrap(ErrPriceStatisticsWindowIsNotValid, err)
+	}
+
+	return currency, nil
+}
+
+// withCurrencyGroups return window with groups of currencies of rates of storage
+func (prices *Prices) withCurrencyGroups(ctx context.Context, window priceWindow) (priceWindow, money.Rates, error) {
+	rates, err := prices.storage.Rates.ReadRateTable(ctx)
+	if err != nil {
+		return window, rates, err
+	}
+
+	window.Groups = currencyGroupsOf(rates, prices.storage.PricesCurrency())
+
+	return window, rates, nil
+}
+
+// convertValue return value in major units of currency converted to other currency by rates
+func convertValue(value float64, rates money.Rates, from, to string) (float64, error) {
+	converted, err := rates.Convert(money.FromFloat(value, from), to)
+	if err != nil {
+		return 0, wrap(ErrPricesCanNotBeConverted, err)
+	}
+
+	return converted.Float(), nil
+}
+
+func newPriceWindow(productID string, from, to time.Time, language string) (priceWindow, error) {
+	window := priceWindow{ProductID: productID, Language: language}
+
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return window, wrap(ErrPriceStatisticsWindowIsNotValid, fmt.Errorf("window ends at %v before it starts at %v", to, from))
+	}
+
+	if !from.IsZero() {
+		window.From = from.UTC().Format(time.RFC3339)
+	}
+
+	if !to.IsZero() {
+		window.To = to.UTC().Format(time.RFC3339)
+	}
+
+	return window, nil
+}
+
+var productPriceStatisticsTemplate = template.Must(template.New("ReadProductPriceStatistics").Parse(`{
+				{{range .Groups}}
+				var(func: uid({{$.ProductID}})) {
+					has_price @filter(eq(priceIsActive, true) AND {{.Filter}}) {
+						all{{.Index}} as priceValue
+					}
+				}
+
+				lowest{{.Index}}() {
+					allTimeLow: min(val(all{{.Index}}))
+				}
+				{{end}}
+
+				var(func: uid({{.ProductID}})) {
+					other as has_price @filter(eq(priceIsActive, true) AND {{.OtherCurrencies}}{{if .From}} AND ge(priceDateTime, "{{.From}}"){{end}}{{if .To}} AND le(priceDateTime, "{{.To}}"){{end}})
+				}
+
+				others(func: uid(other)) {
+					count(uid)
+				}
+
+				var(func: uid({{.ProductID}})) {
+					window as has_price @filter(eq(priceIsActive, true){{if .From}} AND ge(priceDateTime, "{{.From}}"){{end}}{{if .To}} AND le(priceDateTime, "{{.To}}"){{end}})
+				}
+
+				prices(func: uid(window)) {
+					priceValue
+					priceAmount
+					priceCurrency
+				}
+			}`))
+
+// ReadProductPriceStatistics is a method for get aggregates of active prices of product from one date to another
+// in currency, zero dates are not limited and empty currency is currency of prices which are saved without currency.
+// All-time low is aggregated by database for each currency which has rate and converted by rates,
+// minimum, maximum, average, median and volatility are calculated by converted prices of window.
+// Statistics can not be made when window has prices in currencies without rates,
+// such prices outside of window are not counted in all-time low.
+func (prices *Prices) ReadProductPriceStatistics(ctx context.Context, productID string, from, to time.Time, currency string) (ProductPriceStatistics, error) {
+	statistics := ProductPriceStatistics{ProductID: productID, Currency: currency, From: from, To: to}
+
+	if !uidPattern.MatchString(productID) {
+		return statistics, wrap(ErrPriceStatisticsWindowIsNotValid, fmt.Errorf("uid must be like 0x1a, got %v", productID))
+	}
+
+	window, err := newPriceWindow(productID, from, to, "")
+	if err != nil {
+		return statistics, err
+	}
+
+	statistics.Currency, err = prices.currencyOfStatistics(currency)
+	if err != nil {
+		return statistics, err
+	}
+
+	window, rates, err := prices.withCurrencyGroups(ctx, window)
+	if err != nil {
+		return statistics, err
+	}
+
+	queryBuf := bytes.Buffer{}
+	err = productPriceStatisticsTemplate.Execute(&queryBuf, window)
+	if err != nil {
+		logError(err)
+		return statistics, wrap(ErrPriceStatisticsCanNotBeRead, err)
+	}
+
+	transaction := prices.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return statistics, wrap(ErrPriceStatisticsCanNotBeRead, err)
+	}
+
+	var found map[string]json.RawMessage
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return statistics, wrap(ErrPriceStatisticsCanNotBeRead, err)
+	}
+
+	var others []struct {
+		Count int `json:"count"`
+	}
+
+	var windowPrices []Price
+	err = unmarshalBlocks(found, map[string]interface{}{"others": &others, "prices": &windowPrices})
+	if err != nil {
+		return statistics, err
+	}
+
+	if len(others) > 0 && others[0].Count > 0 {
+		return statistics, wrap(ErrPricesCanNotBeConverted,
+			fmt.Errorf("%v prices of product %v are in currencies without rates", others[0].Count, productID))
+	}
+
+	lowest := false
+	for _, group := range window.Groups {
+		var aggregates []map[string]float64
+		err = unmarshalBlocks(found, map[string]interface{}{fmt.Sprintf("lowest%v", group.Index): &aggregates})
+		if err != nil {
+			return statistics, err
+		}
+
+		for _, aggregate := range aggregates {
+			value, ok := aggregate["allTimeLow"]
+			if !ok {
+				continue
+			}
+
+			converted, err := convertValue(value, rates, group.Currency, statistics.Currency)
+			if err != nil {
+				return statistics, err
+			}
+
+			if !lowest || converted < statistics.AllTimeLow {
+				statistics.AllTimeLow, lowest = converted, true
+			}
+		}
+	}
+
+	values := make([]float64, len(windowPrices))
+	for index, price := range windowPrices {
+		converted, err := convertPrice(price, rates, statistics.Currency, prices.storage.PricesCurrency())
+		if err != nil {
+			return statistics, err
+		}
+
+		values[index] = converted.Value
+	}
+
+	sort.Float64s(values)
+
+	statistics.Count = len(values)
+	if len(values) > 0 {
+		total := 0.0
+		for _, value := range values {
+			total += value
+		}
+
+		statistics.Minimum, statistics.Maximum = values[0], values[len(values)-1]
+		statistics.Average = total / float64(len(values))
+	}
+
+	statistics.Median = median(values)
+	statistics.Volatility = volatility(values, statistics.Average)
+
+	return statistics, nil
+}
+
+// unmarshalBlocks decode blocks of response of query by their names, absent blocks are not decoded
+func unmarshalBlocks(found map[string]json.RawMessage, blocks map[string]interface{}) error {
+	for name, value := range blocks {
+		block, ok := found[name]
+		if !ok {
+			continue
+		}
+
+		err := json.Unmarshal(block, value)
+		if err != nil {
+			logError(err)
+			return wrap(ErrPriceStatisticsCanNotBeRead, err)
+		}
+	}
+
+	return nil
+}
+
+// median of values sorted from the least
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+
+	return values[middle]
+}
+
+// volatility is a standard deviation of values divided by their average
+func volatility(values []float64, average float64) float64 {
+	if len(values) == 0 || average == 0 {
+		return 0
+	}
+
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - average) * (value - average)
+	}
+
+	return math.Sqrt(variance/float64(len(values))) / average
+}
+
+var categoriesPriceStatisticsTemplate = template.Must(template.New("ReadCategoriesPriceStatistics").Parse(`{
+				{{range .Groups}}
+				var(func: eq(categoryIsActive, true)) {
+					has_product @filter(eq(productIsActive, true)) {
+						has_price @filter(eq(priceIsActive, true) AND {{.Filter}}{{if $.From}} AND ge(priceDateTime, "{{$.From}}"){{end}}{{if $.To}} AND le(priceDateTime, "{{$.To}}"){{end}}) {
+							values{{.Index}} as priceValue
+						}
+						productTotal{{.Index}} as sum(val(values{{.Index}}))
+						productCount{{.Index}} as count(has_price @filter(eq(priceIsActive, true) AND {{.Filter}}{{if $.From}} AND ge(priceDateTime, "{{$.From}}"){{end}}{{if $.To}} AND le(priceDateTime, "{{$.To}}"){{end}}))
+					}
+					categoryTotal{{.Index}} as sum(val(productTotal{{.Index}}))
+					categoryCount{{.Index}} as sum(val(productCount{{.Index}}))
+				}
+				{{end}}
+
+				var(func: eq(categoryIsActive, true)) {
+					has_product @filter(eq(productIsActive, true)) {
+						productOthers as count(has_price @filter(eq(priceIsActive, true) AND {{.OtherCurrencies}}{{if .From}} AND ge(priceDateTime, "{{.From}}"){{end}}{{if .To}} AND le(priceDateTime, "{{.To}}"){{end}}))
+					}
+					categoryOthers as sum(val(productOthers))
+				}
+
+				categories(func: eq(categoryIsActive, true)) @filter(has(categoryName)) {
+					uid
+					categoryName: categoryName@{{.Language}}
+					{{range .Groups}}
+					total{{.Index}}: val(categoryTotal{{.Index}})
+					count{{.Index}}: val(categoryCount{{.Index}})
+					{{end}}
+					others: val(categoryOthers)
+					products: count(has_product @filter(eq(productIsActive, true)))
+				}
+			}`))
+
+// ReadCategoriesPriceStatistics is a method for get average of active prices of active products of each active
+// category from one date to another in currency, zero dates are not limited and empty currency is currency
+// of prices which are saved without currency. Sums and counts of prices are aggregated by database
+// for each currency which has rate, sums are converted by rates. Statistics of prices in currencies
+// without rates can not be made.
+func (prices *Prices) ReadCategoriesPriceStatistics(ctx context.Context, from, to time.Time, language, currency string) ([]CategoryPriceStatistics, error) {
+	window, err := newPriceWindow("", from, to, language)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err = prices.currencyOfStatistics(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	window, rates, err := prices.withCurrencyGroups(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	queryBuf := bytes.Buffer{}
+	err = categoriesPriceStatisticsTemplate.Execute(&queryBuf, window)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrPriceStatisticsCanNotBeRead, err)
+	}
+
+	transaction := prices.storage.newReadTransaction(ctx)
+	defer transaction.Discard(ctx)
+	response, err := transaction.Query(ctx, queryBuf.String())
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrPriceStatisticsCanNotBeRead, err)
+	}
+
+	var found struct {
+		Categories []map[string]interface{} `json:"categories"`
+	}
+
+	err = json.Unmarshal(response.GetJson(), &found)
+	if err != nil {
+		logError(err)
+		return nil, wrap(ErrPriceStatisticsCanNotBeRead, err)
+	}
+
+	statistics := make([]CategoryPriceStatistics, len(found.Categories))
+	for index, category := range found.Categories {
+		id, _ := category["uid"].(string)
+		name, _ := category["categoryName"].(string)
+		products, _ := category["products"].(float64)
+
+		if others, _ := category["others"].(float64); others > 0 {
+			return nil, wrap(ErrPricesCanNotBeConverted,
+				fmt.Errorf("%v prices of category %v are in currencies without rates", others, id))
+		}
+
+		total, count := 0.0, 0.0
+		for _, group := range window.Groups {
+			groupTotal, _ := category[fmt.Sprintf("total%v", group.Index)].(float64)
+			groupCount, _ := category[fmt.Sprintf("count%v", group.Index)].(float64)
+
+			converted, err := convertValue(groupTotal, rates, group.Currency, currency)
+			if err != nil {
+				return nil, err
+			}
+
+			total, count = total+converted, count+groupCount
+		}
+
+		statistics[index] = CategoryPriceStatistics{
+			CategoryID:   id,
+			CategoryName: name,
+			Currency:     currency,
+			Products:     int(products),
+			Count:        int(count)}
+
+		if count > 0 {
+			statistics[index].Average = total / count
+		}
+	}
+
+	return statistics, nil
+}
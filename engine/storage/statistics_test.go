@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/money"
+)
+
+func TestIntegrationPriceStatisticsOfProductCanBeRead(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Statistics test product"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	dateTime := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	for index, value := range []float64{5, 10, 20, 30} {
+		price, err := storage.Prices.CreatePrice(ctx, Price{Value: value, DateTime: dateTime.AddDate(0, 0, index)})
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		defer storage.Prices.DeletePrice(ctx, price)
+
+		err = storage.Products.AddPriceToProduct(ctx, product.ID, price.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	statistics, err := storage.Prices.ReadProductPriceStatistics(ctx, product.ID, dateTime.AddDate(0, 0, 1), time.Time{}, "")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if statistics.Count != 3 || statistics.Minimum != 10 || statistics.Maximum != 30 || statistics.Average != 20 {
+		test.Error(statistics)
+	}
+
+	if statistics.Median != 20 || statistics.AllTimeLow != 5 || statistics.Currency != storage.PricesCurrency() {
+		test.Error(statistics)
+	}
+}
+
+func TestIntegrationPriceStatisticsOfProductAreConvertedToCurrency(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	_, err := storage.Rates.SetRate(ctx, "USD", "100", time.Now())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Statistics test product in currencies"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	dateTime := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	for index, price := range []Price{
+		{Value: 1000, Currency: storage.PricesCurrency()},
+		{Value: 20, Currency: "USD"},
+		{Value: 3000}} {
+		price.DateTime = dateTime.AddDate(0, 0, index)
+		price, err := storage.Prices.CreatePrice(ctx, price)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		defer storage.Prices.DeletePrice(ctx, price)
+
+		err = storage.Products.AddPriceToProduct(ctx, product.ID, price.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	statistics, err := storage.Prices.ReadProductPriceStatistics(ctx, product.ID, time.Time{}, time.Time{}, "USD")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if statistics.Currency != "USD" || statistics.Count != 3 || statistics.Minimum != 10 || statistics.Maximum != 30 {
+		test.Error(statistics)
+	}
+
+	if statistics.Average != 20 || statistics.Median != 20 || statistics.AllTimeLow != 10 {
+		test.Error(statistics)
+	}
+}
+
+func TestIntegrationPricesWithoutRatesOutsideOfWindowDoNotFailStatistics(test *testing.T) {
+	once.Do(prepareStorage)
+	ctx := context.Background()
+
+	product, err := storage.Products.CreateProduct(ctx, Product{Name: "Statistics test product without rates"}, "en")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	defer storage.Products.DeleteProduct(ctx, product)
+
+	dateTime := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, price := range []Price{
+		{Value: 1000, Currency: storage.PricesCurrency(), DateTime: dateTime},
+		{Value: 500, Currency: "JPY", DateTime: dateTime.AddDate(0, -1, 0)}} {
+		price, err := storage.Prices.CreatePrice(ctx, price)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		defer storage.Prices.DeletePrice(ctx, price)
+
+		err = storage.Products.AddPriceToProduct(ctx, product.ID, price.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	statistics, err := storage.Prices.ReadProductPriceStatistics(ctx, product.ID, dateTime.AddDate(0, 0, -1), time.Time{}, "")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if statistics.Count != 1 || statistics.Minimum != 1000 || statistics.AllTimeLow != 1000 {
+		test.Error(statistics)
+	}
+
+	_, err = storage.Prices.ReadProductPriceStatistics(ctx, product.ID, time.Time{}, time.Time{}, "")
+	if !errors.Is(err, ErrPricesCanNotBeConverted) {
+		test.Error(err)
+	}
+}
+
+func TestPricesWithoutCurrencyAreInGroupOfDefaultCurrency(test *testing.T) {
+	rates := money.Rates{Reference: "USD", ToReference: map[string]*big.Rat{"EUR": big.NewRat(11, 10), "RUB": big.NewRat(1, 90)}}
+
+	groups := currencyGroupsOf(rates, "RUB")
+	if len(groups) != 3 {
+		test.Fatal(groups)
+	}
+
+	for index, currency := range []string{"EUR", "RUB", "USD"} {
+		if groups[index].Currency != currency || groups[index].Index != index {
+			test.Error(groups[index])
+		}
+	}
+
+	if groups[1].Filter != `(eq(priceCurrency, "RUB") OR NOT has(priceCurrency))` || groups[2].Filter != `eq(priceCurrency, "USD")` {
+		test.Error(groups)
+	}
+
+	window := priceWindow{Groups: groups[2:]}
+	if window.OtherCurrencies() != `NOT (eq(priceCurrency, "USD"))` {
+		test.Error(window.OtherCurrencies())
+	}
+}
+
+func TestMedianAndVolatilityOfPrices(test *testing.T) {
+	if median([]float64{1, 3, 10}) != 3 || median([]float64{1, 3, 5, 10}) != 4 || median(nil) != 0 {
+		test.Error("Median of sorted values must be the middle value or average of two middle values")
+	}
+
+	if math.Abs(volatility([]float64{10, 20, 30}, 20)-math.Sqrt(200.0/3)/20) > 1e-9 {
+		test.Error(volatility([]float64{10, 20, 30}, 20))
+	}
+
+	if volatility([]float64{10, 10}, 10) != 0 || volatility(nil, 0) != 0 {
+		test.Error("Volatility of the same prices must be zero")
+	}
+}
+
+func TestWindowOfPriceStatisticsMustBeValid(test *testing.T) {
+	prices := &Prices{}
+	from := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := prices.ReadProductPriceStatistics(context.Background(), "0x1", from, from.AddDate(0, 0, -1), "")
+	if !errors.Is(err, ErrPriceStatisticsWindowIsNotValid) || KindOf(err) != InvalidInput {
+		test.Error(err)
+	}
+
+	_, err = prices.ReadProductPriceStatistics(context.Background(), "product", time.Time{}, time.Time{}, "")
+	if !errors.Is(err, ErrPriceStatisticsWindowIsNotValid) {
+		test.Error(err)
+	}
+
+	_, err = prices.ReadProductPriceStatistics(context.Background(), "0x1", time.Time{}, time.Time{}, "dollars")
+	if !errors.Is(err, ErrPriceStatisticsWindowIsNotValid) {
+		test.Error(err)
+	}
+
+	_, err = prices.ReadCategoriesPriceStatistics(context.Background(), time.Time{}, time.Time{}, "en", "dollars")
+	if !errors.Is(err, ErrPriceStatisticsWindowIsNotValid) {
+		test.Error(err)
+	}
+
+	window, err := newPriceWindow("", from, time.Time{}, "en")
+	if err != nil || window.From != "2018-03-01T00:00:00Z" || window.To != "" {
+		test.Error(window, err)
+	}
+}
@@ -3,12 +3,22 @@ package storage
 import (
 	"context"
 	"fmt"
-	"log"
+	"time"
 
 	"google.golang.org/grpc"
 
 	dataBaseClient "github.com/dgraph-io/dgo"
 	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+	"github.com/hecatoncheir/Sproot/engine/cache"
+	"github.com/hecatoncheir/Sproot/engine/logging"
+)
+
+var (
+	// DefaultQueryTimeout is a limit of time of query when context of call has no deadline
+	DefaultQueryTimeout = 10 * time.Second
+
+	// DefaultMutationTimeout is a limit of time of mutation or change of schema when context of call has no deadline
+	DefaultMutationTimeout = 30 * time.Second
 )
 
 // Storage is a object with database resource
@@ -17,78 +27,112 @@ type Storage struct {
 	GraphGRPCHost string
 	GraphGRPCPort int
 
+	Endpoints         []Endpoint
+	TLS               *TLSConfig
+	KeepaliveTime     time.Duration
+	KeepaliveTimeout  time.Duration
+	ReconnectMaxDelay time.Duration
+
+	QueryTimeout    time.Duration
+	MutationTimeout time.Duration
+
+	Cache *cache.Cache
+
+	DefaultCurrency   string
+	ReferenceCurrency string
+
 	Client       *dataBaseClient.Dgraph
 	Categories   *Categories
 	Companies    *Companies
 	Products     *Products
 	Prices       *Prices
 	Cities       *Cities
+	Offers       *Offers
+	Rates        *Rates
 	Instructions *Instructions
+
+	connections []*grpc.ClientConn
 }
 
-// New is a constructor for Storage objects
-func New(host string, port int) *Storage {
+// New is a constructor for Storage objects with endpoint of host and port and other endpoints of cluster
+func New(host string, port int, endpoints ...Endpoint) *Storage {
 	storage := &Storage{}
 
 	storage.GraphGRPCHost = host
 	storage.GraphGRPCPort = port
 	storage.GraphAddress = fmt.Sprintf("%v:%v", host, port)
+	storage.Endpoints = append([]Endpoint{{Host: host, Port: port}}, endpoints...)
+	storage.KeepaliveTime = DefaultKeepaliveTime
+	storage.KeepaliveTimeout = DefaultKeepaliveTimeout
+	storage.ReconnectMaxDelay = DefaultReconnectMaxDelay
+	storage.QueryTimeout = DefaultQueryTimeout
+	storage.MutationTimeout = DefaultMutationTimeout
 
 	return storage
 }
 
+// prepareDataBaseClient connect to each endpoint, transactions of client are spread between endpoints.
+// Connections are restored by gRPC when endpoint becomes available again.
 func (storage *Storage) prepareDataBaseClient() (*dataBaseClient.Dgraph, error) {
-	conn, err := grpc.Dial(storage.GraphAddress, grpc.WithInsecure())
+	options, err := storage.dialOptions()
 	if err != nil {
-		log.Println(err)
+		logError(err)
 		return nil, err
 	}
 
-	baseClient := dataBaseAPI.NewDgraphClient(conn)
-	databaseGraph := dataBaseClient.NewDgraphClient(baseClient)
+	endpoints := storage.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []Endpoint{{Host: storage.GraphGRPCHost, Port: storage.GraphGRPCPort}}
+	}
+
+	var clients []dataBaseAPI.DgraphClient
+	for _, endpoint := range endpoints {
+		connection, err := grpc.Dial(endpoint.String(), options...)
+		if err != nil {
+			logError(err)
+			storage.Close()
+			return nil, err
+		}
+
+		storage.connections = append(storage.connections, connection)
+		clients = append(clients, dataBaseAPI.NewDgraphClient(connection))
+	}
 
-	return databaseGraph, nil
+	return dataBaseClient.NewDgraphClient(clients...), nil
 }
 
-// SetUp is a method of storage for prepare database client and objects of resource of database.
-func (storage *Storage) SetUp() (err error) {
-	storage.Client, err = storage.prepareDataBaseClient()
-	if err != nil {
-		return err
+// Connect is a method of storage for prepare database client and objects of resource of database.
+func (storage *Storage) Connect() (err error) {
+	if storage.connections != nil {
+		storage.Close()
 	}
 
-	storage.Categories = NewCategoriesResourceForStorage(storage)
-	err = storage.Categories.SetUp()
+	storage.Client, err = storage.prepareDataBaseClient()
 	if err != nil {
 		return err
 	}
 
+	storage.Categories = NewCategoriesResourceForStorage(storage)
 	storage.Companies = NewCompaniesResourceForStorage(storage)
-	err = storage.Companies.SetUp()
-	if err != nil {
-		return err
-	}
-
 	storage.Products = NewProductsResourceForStorage(storage)
-	err = storage.Products.SetUp()
-	if err != nil {
-		return err
-	}
-
 	storage.Prices = NewPricesResourceForStorage(storage)
-	err = storage.Prices.SetUp()
-	if err != nil {
-		return err
-	}
-
 	storage.Cities = NewCitiesResourceForStorage(storage)
-	err = storage.Cities.SetUp()
+	storage.Offers = NewOffersResourceForStorage(storage)
+	storage.Rates = NewRatesResourceForStorage(storage)
+	storage.Instructions = NewInstructionsResourceForStorage(storage)
+
+	return nil
+}
+
+// SetUp is a method of storage for connect to database and check that schema of database is compatible.
+// Pending migrations are not applied by SetUp, use Migrate for it.
+func (storage *Storage) SetUp(ctx context.Context) (err error) {
+	err = storage.Connect()
 	if err != nil {
 		return err
 	}
 
-	storage.Instructions = NewInstructionsResourceForStorage(storage)
-	err = storage.Instructions.SetUp()
+	err = storage.CheckSchema(ctx)
 	if err != nil {
 		return err
 	}
@@ -97,6 +141,21 @@ func (storage *Storage) SetUp() (err error) {
 }
 
 // DeleteAll drop all records in database
-func (storage *Storage) DeleteAll() error {
-	return storage.Client.Alter(context.Background(), &dataBaseAPI.Operation{DropAll: true})
+func (storage *Storage) DeleteAll(ctx context.Context) error {
+	defer storage.Cache.Purge()
+
+	return storage.alter(ctx, &dataBaseAPI.Operation{DropAll: true})
+}
+
+// alter change schema of database with MutationTimeout when context has no deadline
+func (storage *Storage) alter(ctx context.Context, operation *dataBaseAPI.Operation) error {
+	ctx, cancel := withTimeout(ctx, storage.MutationTimeout)
+	defer cancel()
+
+	return storage.Client.Alter(ctx, operation)
+}
+
+// logError write error to log with name of method of storage which failed
+func logError(err error) {
+	logging.Default.With(logging.Fields{"method": callerOf(2)}).Error(err.Error())
 }
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"log"
 	"sync"
 	"testing"
@@ -18,7 +19,12 @@ func prepareStorage() {
 	storage = New(config.Development.Database.Host,
 		config.Development.Database.Port)
 
-	err = storage.SetUp()
+	_, err = storage.Migrate(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = storage.SetUp(context.Background())
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -28,8 +34,34 @@ func TestIntegrationStorageCanConnectToDatabase(test *testing.T) {
 	config := configuration.New()
 	storage = New(config.Development.Database.Host, config.Development.Database.Port)
 
-	err := storage.SetUp()
+	_, err := storage.Migrate(context.Background())
+	if err != nil {
+		test.Fail()
+	}
+
+	err = storage.SetUp(context.Background())
 	if err != nil {
 		test.Fail()
 	}
 }
+
+func deleteImportedNodes(report ImportReport) {
+	for _, node := range report.Nodes {
+		if !node.Created {
+			continue
+		}
+
+		switch node.Kind {
+		case "company":
+			storage.Companies.DeleteCompany(context.Background(), Company{ID: node.ID})
+		case "category":
+			storage.Categories.DeleteCategory(context.Background(), Category{ID: node.ID})
+		case "product":
+			storage.Products.DeleteProduct(context.Background(), Product{ID: node.ID})
+		case "price":
+			storage.Prices.DeletePrice(context.Background(), Price{ID: node.ID})
+		case "city":
+			storage.Cities.DeleteCity(context.Background(), City{ID: node.ID})
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	dataBaseClient "github.com/dgraph-io/dgo"
+	dataBaseAPI "github.com/dgraph-io/dgo/protos/api"
+	"github.com/hecatoncheir/Sproot/engine/metrics"
+	"github.com/hecatoncheir/Sproot/engine/tracing"
+)
+
+var (
+	operationDuration = metrics.NewHistogramVec("sproot_storage_operation_duration_seconds",
+		"Duration of queries and mutations of database by method of storage.",
+		metrics.DefaultBuckets, "method", "operation")
+
+	operationErrors = metrics.NewCounterVec("sproot_storage_operation_errors_total",
+		"Failed queries and mutations of database by method of storage.",
+		"method", "operation")
+)
+
+// transaction of database which measures duration and errors of queries and mutations,
+// makes span of trace for each of them and limits their time by timeouts of storage
+type transaction struct {
+	txn             *dataBaseClient.Txn
+	queryTimeout    time.Duration
+	mutationTimeout time.Duration
+}
+
+// newTransaction is a constructor for transaction of database client of storage
+func (storage *Storage) newTransaction() *transaction {
+	return &transaction{
+		txn:             storage.Client.NewTxn(),
+		queryTimeout:    storage.QueryTimeout,
+		mutationTimeout: storage.MutationTimeout}
+}
+
+// Consistency is a level of consistency of reads of database
+type Consistency int
+
+const (
+	// ReadOnly reads are made in read-only transactions which see all committed mutations,
+	// it is the default level of queries of storage
+	ReadOnly Consistency = iota
+
+	// BestEffort reads are made in read-only transactions with timestamp from memory of Alpha,
+	// they don't wait for Zero and may not see the latest mutations
+	BestEffort
+
+	// Linearizable reads are made in read-write transactions
+	Linearizable
+)
+
+func (consistency Consistency) String() string {
+	switch consistency {
+	case BestEffort:
+		return "best-effort"
+	case Linearizable:
+		return "linearizable"
+	default:
+		return "read-only"
+	}
+}
+
+type consistencyKey struct{}
+
+// WithConsistency is a function for select consistency of reads of storage made with returned context
+func WithConsistency(ctx context.Context, consistency Consistency) context.Context {
+	return context.WithValue(ctx, consistencyKey{}, consistency)
+}
+
+// ConsistencyOf return consistency of reads selected for context, ReadOnly if it is not selected
+func ConsistencyOf(ctx context.Context) Consistency {
+	consistency, ok := ctx.Value(consistencyKey{}).(Consistency)
+	if !ok {
+		return ReadOnly
+	}
+
+	return consistency
+}
+
+// newReadTransaction is a constructor for transaction of queries with consistency selected for context
+func (storage *Storage) newReadTransaction(ctx context.Context) *transaction {
+	var txn *dataBaseClient.Txn
+
+	switch ConsistencyOf(ctx) {
+	case Linearizable:
+		txn = storage.Client.NewTxn()
+	case BestEffort:
+		txn = storage.Client.NewReadOnlyTxn().BestEffort()
+	default:
+		txn = storage.Client.NewReadOnlyTxn()
+	}
+
+	return &transaction{
+		txn:             txn,
+		queryTimeout:    storage.QueryTimeout,
+		mutationTimeout: storage.MutationTimeout}
+}
+
+// withTimeout add timeout to context which has no deadline, zero timeout means no limit
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Query is the same as Query of transaction of database client
+func (transaction *transaction) Query(ctx context.Context, query string) (*dataBaseAPI.Response, error) {
+	method, startedAt := callerOf(2), time.Now()
+
+	ctx, span := tracing.Start(ctx, "storage."+method)
+	span.SetAttribute("db.system", "dgraph")
+	span.SetAttribute("db.operation", "query")
+	defer span.Finish()
+
+	ctx, cancel := withTimeout(ctx, transaction.queryTimeout)
+	defer cancel()
+
+	response, err := transaction.txn.Query(ctx, query)
+	operationDuration.Observe(time.Since(startedAt).Seconds(), method, "query")
+	if err != nil {
+		operationErrors.Inc(method, "query")
+		span.SetError(err)
+	}
+
+	return response, err
+}
+
+// Mutate is the same as Mutate of transaction of database client
+func (transaction *transaction) Mutate(ctx context.Context, mutation *dataBaseAPI.Mutation) (*dataBaseAPI.Assigned, error) {
+	method, startedAt := callerOf(2), time.Now()
+
+	ctx, span := tracing.Start(ctx, "storage."+method)
+	span.SetAttribute("db.system", "dgraph")
+	span.SetAttribute("db.operation", "mutation")
+	defer span.Finish()
+
+	ctx, cancel := withTimeout(ctx, transaction.mutationTimeout)
+	defer cancel()
+
+	assigned, err := transaction.txn.Mutate(ctx, mutation)
+	operationDuration.Observe(time.Since(startedAt).Seconds(), method, "mutation")
+	if err != nil {
+		operationErrors.Inc(method, "mutation")
+		span.SetError(err)
+	}
+
+	return assigned, err
+}
+
+// Discard is a method for finish transaction which was not committed,
+// it is a no-op for committed and read-only transactions, so it can be deferred after creation.
+// Error of discard is not returned because Alpha cleans up transactions itself.
+func (transaction *transaction) Discard(ctx context.Context) {
+	ctx, cancel := withTimeout(ctx, transaction.mutationTimeout)
+	defer cancel()
+
+	err := transaction.txn.Discard(ctx)
+	if err != nil {
+		logError(err)
+	}
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
+// callerOf return name of method of storage like Companies.CreateCompany, skip is the same as in runtime.Caller
+func callerOf(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+
+	name := runtime.FuncForPC(pc).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimPrefix(name, "storage.")
+	name = closureSuffix.ReplaceAllString(name, "")
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+
+	return name
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCallerIsNameOfMethodOfStorage(test *testing.T) {
+	var method string
+	func() { method = callerOf(1) }()
+
+	if method != "TestCallerIsNameOfMethodOfStorage" {
+		test.Error(method)
+	}
+
+	method = (&Companies{}).callerForTest()
+	if method != "Companies.callerForTest" {
+		test.Error(method)
+	}
+}
+
+func (companies *Companies) callerForTest() string {
+	return callerOf(1)
+}
+
+func TestTimeoutIsAddedToContextWithoutDeadline(test *testing.T) {
+	ctx, cancel := withTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Second {
+		test.Error(deadline)
+	}
+
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelParent()
+
+	ctx, cancel = withTimeout(parent, time.Second)
+	defer cancel()
+
+	deadline, _ = ctx.Deadline()
+	if time.Until(deadline) < 30*time.Second {
+		test.Error("Deadline of caller must be kept")
+	}
+
+	ctx, cancel = withTimeout(context.Background(), 0)
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		test.Error("Zero timeout must not limit time")
+	}
+}
+
+func TestConsistencyIsSelectedByContext(test *testing.T) {
+	ctx := context.Background()
+	if ConsistencyOf(ctx) != ReadOnly {
+		test.Error(ConsistencyOf(ctx))
+	}
+
+	ctx = WithConsistency(ctx, BestEffort)
+	if ConsistencyOf(ctx) != BestEffort || ConsistencyOf(ctx).String() != "best-effort" {
+		test.Error(ConsistencyOf(ctx))
+	}
+
+	ctx = WithConsistency(ctx, Linearizable)
+	if ConsistencyOf(ctx) != Linearizable {
+		test.Error(ConsistencyOf(ctx))
+	}
+}
@@ -0,0 +1,312 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hecatoncheir/Sproot/engine/logging"
+)
+
+var (
+	// ErrExporterIsNotSupported means that name of exporter is not none, stdout or otlp
+	ErrExporterIsNotSupported = errors.New("exporter of traces is not supported")
+
+	// ErrSpansAreNotAccepted means that collector answered with not successful status
+	ErrSpansAreNotAccepted = errors.New("spans are not accepted by collector")
+)
+
+// StdoutExporter write each finished span as line of JSON
+type StdoutExporter struct {
+	mutex sync.Mutex
+	out   io.Writer
+}
+
+// NewStdoutExporter is a constructor for StdoutExporter
+func NewStdoutExporter(out io.Writer) *StdoutExporter {
+	return &StdoutExporter{out: out}
+}
+
+type exportedSpan struct {
+	TraceID    string                 `json:"traceID"`
+	SpanID     string                 `json:"spanID"`
+	ParentID   string                 `json:"parentID,omitempty"`
+	Name       string                 `json:"name"`
+	Start      time.Time              `json:"start"`
+	Duration   string                 `json:"duration"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// Export is a method for write spans
+func (exporter *StdoutExporter) Export(spans []*Span) error {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	encoder := json.NewEncoder(exporter.out)
+	for _, span := range spans {
+		span.mutex.Lock()
+		exported := exportedSpan{
+			TraceID:    span.Context.TraceID.String(),
+			SpanID:     span.Context.SpanID.String(),
+			Name:       span.Name,
+			Start:      span.Start,
+			Duration:   span.End.Sub(span.Start).String(),
+			Attributes: span.Attributes,
+			Error:      span.Error}
+
+		if span.ParentID != (SpanID{}) {
+			exported.ParentID = span.ParentID.String()
+		}
+
+		err := encoder.Encode(exported)
+		span.mutex.Unlock()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Shutdown is a method of Exporter, StdoutExporter has nothing to flush
+func (exporter *StdoutExporter) Shutdown() error {
+	return nil
+}
+
+// CollectorExporter send batches of spans to collector by OTLP over HTTP with JSON encoding
+type CollectorExporter struct {
+	Endpoint  string
+	Service   string
+	BatchSize int
+	Interval  time.Duration
+	Client    *http.Client
+
+	spans chan *Span
+	flush chan chan error
+	once  sync.Once
+}
+
+// NewCollectorExporter is a constructor for CollectorExporter with one goroutine which sends batches,
+// spans are dropped when queue of 2048 spans is full
+func NewCollectorExporter(endpoint, service string) *CollectorExporter {
+	exporter := &CollectorExporter{
+		Endpoint:  endpoint,
+		Service:   service,
+		BatchSize: 512,
+		Interval:  5 * time.Second,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+		spans:     make(chan *Span, 2048),
+		flush:     make(chan chan error)}
+
+	go exporter.run()
+
+	return exporter
+}
+
+// Export is a method for add spans to queue of exporter
+func (exporter *CollectorExporter) Export(spans []*Span) error {
+	for _, span := range spans {
+		select {
+		case exporter.spans <- span:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Shutdown is a method for send queued spans and stop exporter
+func (exporter *CollectorExporter) Shutdown() error {
+	result := make(chan error)
+
+	var err error
+	exporter.once.Do(func() {
+		exporter.flush <- result
+		err = <-result
+	})
+
+	return err
+}
+
+func (exporter *CollectorExporter) run() {
+	ticker := time.NewTicker(exporter.Interval)
+	defer ticker.Stop()
+
+	var batch []*Span
+
+	for {
+		select {
+		case span := <-exporter.spans:
+			batch = append(batch, span)
+			if len(batch) >= exporter.BatchSize {
+				exporter.sendAndLog(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				exporter.sendAndLog(batch)
+				batch = nil
+			}
+		case result := <-exporter.flush:
+			for queued := len(exporter.spans); queued > 0; queued-- {
+				batch = append(batch, <-exporter.spans)
+			}
+
+			var err error
+			if len(batch) > 0 {
+				err = exporter.send(batch)
+			}
+
+			result <- err
+			return
+		}
+	}
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+func otlpValueOf(value interface{}) otlpValue {
+	switch typed := value.(type) {
+	case bool:
+		return otlpValue{BoolValue: &typed}
+	case int:
+		text := strconv.Itoa(typed)
+		return otlpValue{IntValue: &text}
+	case int64:
+		text := strconv.FormatInt(typed, 10)
+		return otlpValue{IntValue: &text}
+	case float64:
+		return otlpValue{DoubleValue: &typed}
+	default:
+		text := fmt.Sprint(typed)
+		return otlpValue{StringValue: &text}
+	}
+}
+
+func otlpAttributesOf(attributes map[string]interface{}) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	converted := make([]otlpAttribute, len(keys))
+	for index, key := range keys {
+		converted[index] = otlpAttribute{Key: key, Value: otlpValueOf(attributes[key])}
+	}
+
+	return converted
+}
+
+// encode is a method for make request body of OTLP with spans of service
+func (exporter *CollectorExporter) encode(batch []*Span) ([]byte, error) {
+	spans := make([]otlpSpan, len(batch))
+	for index, span := range batch {
+		span.mutex.Lock()
+		converted := otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			Name:              span.Name,
+			Kind:              1,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributesOf(span.Attributes)}
+
+		if span.ParentID != (SpanID{}) {
+			converted.ParentSpanID = span.ParentID.String()
+		}
+
+		if span.Error != "" {
+			converted.Status.Code = 2
+			converted.Status.Message = span.Error
+		}
+		span.mutex.Unlock()
+
+		spans[index] = converted
+	}
+
+	request := map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": otlpAttributesOf(map[string]interface{}{"service.name": exporter.Service})},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]string{"name": "github.com/hecatoncheir/Sproot"},
+				"spans": spans}}}}}
+
+	return json.Marshal(request)
+}
+
+func (exporter *CollectorExporter) sendAndLog(batch []*Span) {
+	err := exporter.send(batch)
+	if err != nil {
+		logging.Default.With(logging.Fields{"endpoint": exporter.Endpoint, "spans": len(batch)}).Warning(err.Error())
+	}
+}
+
+func (exporter *CollectorExporter) send(batch []*Span) error {
+	body, err := exporter.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	response, err := exporter.Client.Post(exporter.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return ErrSpansAreNotAccepted
+	}
+
+	return nil
+}
+
+// NewExporter is a constructor for exporter by name: none, stdout or otlp with endpoint of collector
+func NewExporter(name, endpoint, service string, out io.Writer) (Exporter, error) {
+	switch name {
+	case "none", "":
+		return nil, nil
+	case "stdout":
+		return NewStdoutExporter(out), nil
+	case "otlp":
+		return NewCollectorExporter(endpoint, service), nil
+	}
+
+	return nil, ErrExporterIsNotSupported
+}
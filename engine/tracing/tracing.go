@@ -0,0 +1,273 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceID is an identifier of trace which is the same for all spans of one request
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID is an identifier of span in trace
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext is a part of span which is propagated to other services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid is a method for check that trace and span are set
+func (spanContext SpanContext) IsValid() bool {
+	return spanContext.TraceID != TraceID{} && spanContext.SpanID != SpanID{}
+}
+
+// Span is a timed operation of trace
+type Span struct {
+	mutex      sync.Mutex
+	tracer     *Tracer
+	Name       string
+	Context    SpanContext
+	ParentID   SpanID
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]interface{}
+	Error      string
+	ended      bool
+}
+
+// SetAttribute is a method for add detail of operation to span
+func (span *Span) SetAttribute(key string, value interface{}) {
+	if span == nil {
+		return
+	}
+
+	span.mutex.Lock()
+	span.Attributes[key] = value
+	span.mutex.Unlock()
+}
+
+// SetError is a method for mark span as failed
+func (span *Span) SetError(err error) {
+	if span == nil || err == nil {
+		return
+	}
+
+	span.mutex.Lock()
+	span.Error = err.Error()
+	span.mutex.Unlock()
+}
+
+// Finish is a method for end span and send it to exporter of tracer
+func (span *Span) Finish() {
+	if span == nil {
+		return
+	}
+
+	span.mutex.Lock()
+	if span.ended {
+		span.mutex.Unlock()
+		return
+	}
+
+	span.ended = true
+	span.End = time.Now()
+	span.mutex.Unlock()
+
+	span.tracer.export(span)
+}
+
+// Exporter send finished spans to collector or output
+type Exporter interface {
+	Export(spans []*Span) error
+	Shutdown() error
+}
+
+// Tracer make spans and pass finished spans to exporter
+type Tracer struct {
+	mutex    sync.RWMutex
+	exporter Exporter
+}
+
+// Default is a tracer of engine and storage, spans are dropped until exporter is set
+var Default = &Tracer{}
+
+// SetExporter is a method for set exporter of finished spans, previous exporter is shut down
+func (tracer *Tracer) SetExporter(exporter Exporter) error {
+	tracer.mutex.Lock()
+	previous := tracer.exporter
+	tracer.exporter = exporter
+	tracer.mutex.Unlock()
+
+	if previous != nil {
+		return previous.Shutdown()
+	}
+
+	return nil
+}
+
+// Shutdown is a method for flush and stop exporter
+func (tracer *Tracer) Shutdown() error {
+	return tracer.SetExporter(nil)
+}
+
+func (tracer *Tracer) export(span *Span) {
+	tracer.mutex.RLock()
+	exporter := tracer.exporter
+	tracer.mutex.RUnlock()
+
+	if exporter != nil {
+		exporter.Export([]*Span{span})
+	}
+}
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+// Start is a method for make span which is a child of span of context or of remote span of context
+func (tracer *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	span := &Span{tracer: tracer, Name: name, Start: time.Now(), Attributes: map[string]interface{}{}}
+
+	parent := SpanContextOf(ctx)
+	if parent.IsValid() {
+		span.Context.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		rand.Read(span.Context.TraceID[:])
+	}
+
+	rand.Read(span.Context.SpanID[:])
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Start is a function for make span by Default tracer
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return Default.Start(ctx, name)
+}
+
+// SpanOf is a function for get current span of context, nil if context has no span
+func SpanOf(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+
+	span, _ := ctx.Value(spanKey{}).(*Span)
+
+	return span
+}
+
+// SpanContextOf is a function for get context of current span or of remote span of context
+func SpanContextOf(ctx context.Context) SpanContext {
+	if span := SpanOf(ctx); span != nil {
+		return span.Context
+	}
+
+	if ctx == nil {
+		return SpanContext{}
+	}
+
+	remote, _ := ctx.Value(remoteKey{}).(SpanContext)
+
+	return remote
+}
+
+// ErrTraceParentIsNotValid means that traceparent is not in format of W3C Trace Context
+var ErrTraceParentIsNotValid = errors.New("traceparent is not valid")
+
+// TraceParent is a function for format span context as traceparent header of W3C Trace Context
+func TraceParent(spanContext SpanContext) string {
+	return fmt.Sprintf("00-%v-%v-01", spanContext.TraceID, spanContext.SpanID)
+}
+
+// ParseTraceParent is a function for read span context from traceparent header of W3C Trace Context
+func ParseTraceParent(traceParent string) (SpanContext, error) {
+	parts := strings.Split(traceParent, "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, ErrTraceParentIsNotValid
+	}
+
+	spanContext := SpanContext{}
+
+	_, err := hex.Decode(spanContext.TraceID[:], []byte(parts[1]))
+	if err != nil {
+		return SpanContext{}, ErrTraceParentIsNotValid
+	}
+
+	_, err = hex.Decode(spanContext.SpanID[:], []byte(parts[2]))
+	if err != nil || !spanContext.IsValid() {
+		return SpanContext{}, ErrTraceParentIsNotValid
+	}
+
+	return spanContext, nil
+}
+
+// traceParentKey is a key of traceparent in JSON data of events of broker
+const traceParentKey = "traceparent"
+
+// Inject is a function for add traceparent of span of context to JSON object of data of event.
+// Field is written before other fields of object and bytes of other fields are not changed,
+// data which is not JSON object or already has traceparent is returned without changes
+func Inject(ctx context.Context, data string) string {
+	spanContext := SpanContextOf(ctx)
+	if !spanContext.IsValid() {
+		return data
+	}
+
+	object := strings.TrimLeft(data, " \t\r\n")
+	if !strings.HasPrefix(object, "{") || !json.Valid([]byte(object)) {
+		return data
+	}
+
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal([]byte(object), &fields)
+	if err != nil {
+		return data
+	}
+
+	if _, ok := fields[traceParentKey]; ok {
+		return data
+	}
+
+	field := fmt.Sprintf("%q:%q", traceParentKey, TraceParent(spanContext))
+	if len(fields) != 0 {
+		field += ","
+	}
+
+	return "{" + field + object[1:]
+}
+
+// Extract is a function for add remote span of traceparent of JSON data of event to context
+func Extract(ctx context.Context, data string) context.Context {
+	fields := struct {
+		TraceParent string `json:"traceparent"`
+	}{}
+
+	err := json.Unmarshal([]byte(data), &fields)
+	if err != nil || fields.TraceParent == "" {
+		return ctx
+	}
+
+	spanContext, err := ParseTraceParent(fields.TraceParent)
+	if err != nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, remoteKey{}, spanContext)
+}
@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type exporterForTest struct {
+	spans []*Span
+}
+
+func (exporter *exporterForTest) Export(spans []*Span) error {
+	exporter.spans = append(exporter.spans, spans...)
+	return nil
+}
+
+func (exporter *exporterForTest) Shutdown() error {
+	return nil
+}
+
+func TestChildSpanContinuesTraceOfParent(test *testing.T) {
+	exporter := &exporterForTest{}
+	tracer := &Tracer{exporter: exporter}
+
+	ctx, parent := tracer.Start(context.Background(), "handle Need items by name")
+	_, child := tracer.Start(ctx, "storage.Products.ReadProductsByNameWithPagination")
+	child.SetAttribute("db.operation", "query")
+	child.Finish()
+	parent.Finish()
+	parent.Finish()
+
+	if len(exporter.spans) != 2 {
+		test.Fatal(len(exporter.spans))
+	}
+
+	if child.Context.TraceID != parent.Context.TraceID || child.ParentID != parent.Context.SpanID {
+		test.Error("Child span must be in trace of parent span")
+	}
+
+	if child.Context.SpanID == parent.Context.SpanID || !parent.Context.IsValid() {
+		test.Error("Spans must have different identifiers")
+	}
+}
+
+func TestTraceParentIsPropagatedInDataOfEvent(test *testing.T) {
+	ctx, span := (&Tracer{}).Start(context.Background(), "publish Need products of category of company")
+
+	data := Inject(ctx, `{"Language":"ru"}`)
+	if !strings.Contains(data, `"traceparent":"`+TraceParent(span.Context)+`"`) || !strings.Contains(data, `"Language":"ru"`) {
+		test.Fatal(data)
+	}
+
+	for _, data := range []string{"error of report", `[1,2]`, `"text"`, `{"broken":`} {
+		if Inject(ctx, data) != data {
+			test.Error("Data which is not JSON object must not be changed", data)
+		}
+	}
+
+	payload := `{"b":1.50,"a":{"z":1e3,"y":"\u00e9"}}`
+	injected := Inject(ctx, payload)
+	if injected != `{"traceparent":"`+TraceParent(span.Context)+`",`+payload[1:] {
+		test.Error("Fields of JSON object must not be changed", injected)
+	}
+
+	if Inject(ctx, injected) != injected {
+		test.Error("Traceparent of data must not be written twice")
+	}
+
+	if Inject(ctx, `{}`) != `{"traceparent":"`+TraceParent(span.Context)+`"}` {
+		test.Error(Inject(ctx, `{}`))
+	}
+
+	remote := SpanContextOf(Extract(context.Background(), data))
+	if remote != span.Context {
+		test.Error(remote, span.Context)
+	}
+
+	_, child := (&Tracer{}).Start(Extract(context.Background(), data), "handle Product of category of company ready")
+	if child.Context.TraceID != span.Context.TraceID || child.ParentID != span.Context.SpanID {
+		test.Error("Span of handler must continue trace of event")
+	}
+
+	_, err := ParseTraceParent("00-00000000000000000000000000000000-0000000000000000-01")
+	if err != ErrTraceParentIsNotValid {
+		test.Error(err)
+	}
+}
+
+func TestSpansAreWrittenToStdout(test *testing.T) {
+	out := bytes.Buffer{}
+	tracer := &Tracer{exporter: NewStdoutExporter(&out)}
+
+	_, span := tracer.Start(context.Background(), "search products by name")
+	span.SetAttribute("products", 2)
+	span.Finish()
+
+	exported := exportedSpan{}
+	err := json.Unmarshal(out.Bytes(), &exported)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if exported.Name != "search products by name" || exported.TraceID != span.Context.TraceID.String() ||
+		exported.Attributes["products"] != float64(2) {
+		test.Error(exported)
+	}
+}
+
+func TestSpansAreSentToCollector(test *testing.T) {
+	bodies := make(chan []byte, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		bodies <- body
+	}))
+	defer collector.Close()
+
+	exporter := NewCollectorExporter(collector.URL+"/v1/traces", "Sproot")
+	tracer := &Tracer{exporter: exporter}
+
+	_, span := tracer.Start(context.Background(), "handle Need items by name")
+	span.Finish()
+
+	err := tracer.Shutdown()
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	body := string(<-bodies)
+	for _, part := range []string{`"traceId":"` + span.Context.TraceID.String() + `"`,
+		`"name":"handle Need items by name"`, `"stringValue":"Sproot"`} {
+		if !strings.Contains(body, part) {
+			test.Errorf("%v is not sent: %v", part, body)
+		}
+	}
+}
@@ -2,35 +2,20 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/hecatoncheir/Configuration"
-	"github.com/hecatoncheir/Sproot/engine"
+	"github.com/hecatoncheir/Sproot/cli"
 )
 
 func main() {
-	config := configuration.New()
-	if config.ServiceName == "" {
-		config.ServiceName = "Sproot"
-	}
-
-	var err error
-
-	puffer := engine.New(config)
-	err = puffer.SetUpStorage(config.Production.Database.Host,
-		config.Production.Database.Port)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = puffer.SetUpModel()
+	sproot, err := cli.New(configuration.New())
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	err = puffer.SetUpBroker(config.Production.EventBus.Host, config.Production.EventBus.Port)
+	err = sproot.Run(os.Args[1:])
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	puffer.SubscribeOnEvents(config.Production.SprootTopic)
 }